@@ -65,34 +65,7 @@ Without --confirm, shows a preview of what would be purchased.`,
 		}
 
 		// Get address and payment IDs, use defaults if not provided
-		addressID := buyAddressID
-		paymentID := buyPaymentID
-
-		if addressID == "" {
-			addresses, _ := c.GetAddresses()
-			for _, addr := range addresses {
-				if addr.Default {
-					addressID = addr.ID
-					break
-				}
-			}
-			if addressID == "" && len(addresses) > 0 {
-				addressID = addresses[0].ID
-			}
-		}
-
-		if paymentID == "" {
-			payments, _ := c.GetPaymentMethods()
-			for _, pm := range payments {
-				if pm.Default {
-					paymentID = pm.ID
-					break
-				}
-			}
-			if paymentID == "" && len(payments) > 0 {
-				paymentID = payments[0].ID
-			}
-		}
+		addressID, paymentID := checkoutIDs(c, buyAddressID, buyPaymentID)
 
 		// Add to cart and checkout
 		_, err = c.AddToCart(asin, buyQuantity)
@@ -111,6 +84,38 @@ Without --confirm, shows a preview of what would be purchased.`,
 	},
 }
 
+// checkoutIDs returns the given address and payment method IDs, falling back to the
+// default (or first) saved address and payment method when one is empty
+func checkoutIDs(c *amazon.Client, addressID, paymentID string) (string, string) {
+	if addressID == "" {
+		addresses, _ := c.GetAddresses()
+		for _, addr := range addresses {
+			if addr.Default {
+				addressID = addr.ID
+				break
+			}
+		}
+		if addressID == "" && len(addresses) > 0 {
+			addressID = addresses[0].ID
+		}
+	}
+
+	if paymentID == "" {
+		payments, _ := c.GetPaymentMethods()
+		for _, pm := range payments {
+			if pm.Default {
+				paymentID = pm.ID
+				break
+			}
+		}
+		if paymentID == "" && len(payments) > 0 {
+			paymentID = payments[0].ID
+		}
+	}
+
+	return addressID, paymentID
+}
+
 func init() {
 	rootCmd.AddCommand(buyCmd)
 
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/zkwentz/amazon-cli/internal/amazon"
+	"github.com/zkwentz/amazon-cli/internal/orderstats"
+	"github.com/zkwentz/amazon-cli/internal/output"
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+// buyAgainYears is how far back buy-again list looks without --since
+const buyAgainYears = 2
+
+var (
+	buyAgainSince     string
+	buyAgainMinOrders int
+	buyAgainLimit     int
+)
+
+// buyAgainCmd represents the buy-again command
+var buyAgainCmd = &cobra.Command{
+	Use:   "buy-again",
+	Short: "Find items you buy repeatedly",
+	Long:  `List the items you order again and again. Use 'orders reorder' to order them.`,
+}
+
+// buyAgainListCmd represents the buy-again list command
+var buyAgainListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List frequently repurchased items",
+	Long: `List the items bought in at least --min-orders orders since --since (default:
+the last 2 years), most frequently ordered first.
+
+Each item shows how many orders it appeared in, the total quantity, the average
+number of days between orders and the last order it was in, which can be passed
+to 'orders reorder --item'. Cancelled orders are ignored. Use --offline to read
+from the local index built by 'orders sync'.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if buyAgainMinOrders < 1 {
+			_ = output.Error(models.ErrInvalidInput, "--min-orders must be at least 1", nil)
+			os.Exit(models.ExitInvalidArgs)
+		}
+		if buyAgainLimit < 0 {
+			_ = output.Error(models.ErrInvalidInput, "--limit must not be negative", nil)
+			os.Exit(models.ExitInvalidArgs)
+		}
+
+		since, _, err := dateRangeFlags(buyAgainSince, "")
+		if err != nil {
+			_ = output.Error(models.ErrInvalidInput, err.Error(), nil)
+			os.Exit(models.ExitInvalidArgs)
+		}
+		if since.IsZero() {
+			since = time.Now().AddDate(-buyAgainYears, 0, 0)
+		}
+
+		orders, m := ordersForReport(amazon.OrderQuery{All: true, Since: since, Expand: true})
+		list, err := orderstats.Repurchases(orders, buyAgainMinOrders, buyAgainLimit, m)
+		if err != nil {
+			_ = output.Error(models.ErrAmazonError, err.Error(), nil)
+			os.Exit(models.ExitGeneralError)
+		}
+
+		_ = output.NewPrinter(viper.GetString("output"), false).Print(list)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(buyAgainCmd)
+	buyAgainCmd.AddCommand(buyAgainListCmd)
+
+	buyAgainListCmd.Flags().StringVar(&buyAgainSince, "since", "", "Only orders placed on or after this date (YYYY-MM-DD; default: 2 years ago)")
+	buyAgainListCmd.Flags().IntVar(&buyAgainMinOrders, "min-orders", orderstats.DefaultMinOrders, "Minimum number of orders an item must appear in")
+	buyAgainListCmd.Flags().IntVar(&buyAgainLimit, "limit", 20, "Maximum number of items (0 for all)")
+	buyAgainListCmd.Flags().BoolVar(&ordersOffline, "offline", false, "Serve from the local order index (see 'orders sync')")
+}
@@ -0,0 +1,22 @@
+package cmd
+
+import "testing"
+
+func TestBuyAgainCmd_Subcommands(t *testing.T) {
+	if buyAgainCmd.Use != "buy-again" {
+		t.Errorf("Expected Use='buy-again', got '%s'", buyAgainCmd.Use)
+	}
+	if len(buyAgainCmd.Commands()) != 1 || buyAgainCmd.Commands()[0] != buyAgainListCmd {
+		t.Error("Expected buy-again to have a list subcommand")
+	}
+}
+
+func TestBuyAgainListCmd_Flags(t *testing.T) {
+	defaults := map[string]string{"since": "", "min-orders": "2", "limit": "20", "offline": "false"}
+	for name, def := range defaults {
+		flag := buyAgainListCmd.Flags().Lookup(name)
+		if flag == nil || flag.DefValue != def {
+			t.Errorf("Expected --%s flag defaulting to %q", name, def)
+		}
+	}
+}
@@ -19,13 +19,56 @@ var (
 // Shared client instance for cart operations
 var client *amazon.Client
 
-func getClient() *amazon.Client {
+// getClient returns the shared client, creating it on first use from the configured
+// options followed by extra, such as a command's order index or hook notifier
+func getClient(extra ...amazon.Option) *amazon.Client {
 	if client == nil {
-		client = amazon.NewClient()
+		opts, err := clientOptions()
+		if err != nil {
+			_ = output.Error(models.ErrInvalidInput, err.Error(), nil)
+			os.Exit(models.ExitInvalidArgs)
+		}
+		client = amazon.NewClient(append(opts, extra...)...)
+		configureTransport(client)
 	}
 	return client
 }
 
+// configureTransport wraps the client transport for --record, --replay and --har
+func configureTransport(c *amazon.Client) {
+	if recordDir != "" && replayDir != "" {
+		_ = output.Error(models.ErrInvalidInput, "--record and --replay cannot be used together", nil)
+		os.Exit(models.ExitInvalidArgs)
+	}
+
+	if replayDir != "" {
+		rt, err := amazon.NewReplayTransport(replayDir)
+		if err != nil {
+			_ = output.Error(models.ErrInvalidInput, err.Error(), nil)
+			os.Exit(models.ExitInvalidArgs)
+		}
+		c.SetTransport(rt)
+	}
+
+	if recordDir != "" {
+		rt, err := amazon.NewRecordingTransport(recordDir, c.Transport())
+		if err != nil {
+			_ = output.Error(models.ErrInvalidInput, err.Error(), nil)
+			os.Exit(models.ExitInvalidArgs)
+		}
+		c.SetTransport(rt)
+	}
+
+	if harFile != "" {
+		rt, err := amazon.NewHARTransport(harFile, c.Transport(), rootCmd.Version)
+		if err != nil {
+			_ = output.Error(models.ErrInvalidInput, err.Error(), nil)
+			os.Exit(models.ExitInvalidArgs)
+		}
+		c.SetTransport(rt)
+	}
+}
+
 // cartCmd represents the cart command
 var cartCmd = &cobra.Command{
 	Use:   "cart",
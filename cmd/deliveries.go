@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/zkwentz/amazon-cli/internal/calendar"
+	"github.com/zkwentz/amazon-cli/internal/output"
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+var (
+	deliveriesDays   int
+	deliveriesFormat string
+)
+
+// deliveriesCmd represents the deliveries command
+var deliveriesCmd = &cobra.Command{
+	Use:   "deliveries",
+	Short: "Show everything arriving soon",
+	Long: `Show what is expected to arrive in the next --days days, across orders and
+Subscribe & Save subscriptions, sorted by expected date.
+
+Packages of orders placed in the last 30 days that haven't been delivered are
+listed with their tracking; overdue packages are included and packages without an
+expected date are listed last. Subscriptions are included when their next shipment
+falls within the window. Use --output table or csv for a compact view.
+
+Use --format ics to write an iCalendar file with an all-day event per delivery that
+has an expected date. Event UIDs are derived from the order, package or
+subscription ID, so importing the file again updates events instead of
+duplicating them.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if deliveriesDays <= 0 {
+			_ = output.Error(models.ErrInvalidInput, "--days must be positive", nil)
+			os.Exit(models.ExitInvalidArgs)
+		}
+		validateCalendarFormat(deliveriesFormat)
+
+		c := getClient()
+
+		deliveries, err := c.GetUpcomingDeliveries(deliveriesDays)
+		if err != nil {
+			_ = output.Error(models.ErrAmazonError, err.Error(), nil)
+			os.Exit(models.ExitGeneralError)
+		}
+
+		if deliveriesFormat == calendar.FormatICS {
+			printCalendar(deliveries.Deliveries)
+			return
+		}
+
+		_ = output.NewPrinter(viper.GetString("output"), false).Print(deliveries)
+	},
+}
+
+// validateCalendarFormat checks a --format value; empty means printing with --output
+func validateCalendarFormat(format string) {
+	if format != "" && format != calendar.FormatICS {
+		_ = output.Error(models.ErrInvalidInput, fmt.Sprintf("unsupported format %q: use %s", format, calendar.FormatICS), nil)
+		os.Exit(models.ExitInvalidArgs)
+	}
+}
+
+// printCalendar writes deliveries to stdout as an iCalendar file
+func printCalendar(deliveries []models.Delivery) {
+	if err := calendar.Write(os.Stdout, calendar.DeliveryEvents(deliveries), time.Now()); err != nil {
+		_ = output.Error(models.ErrAmazonError, err.Error(), nil)
+		os.Exit(models.ExitGeneralError)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(deliveriesCmd)
+
+	deliveriesCmd.Flags().IntVar(&deliveriesDays, "days", 7, "Number of days ahead to include")
+	deliveriesCmd.Flags().StringVar(&deliveriesFormat, "format", "", "Export format: ics (default: print with --output)")
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+func TestDeliveriesCmd_Configuration(t *testing.T) {
+	if deliveriesCmd.Use != "deliveries" {
+		t.Errorf("Expected Use='deliveries', got '%s'", deliveriesCmd.Use)
+	}
+	if deliveriesCmd.Run == nil {
+		t.Error("Expected Run function to be defined")
+	}
+
+	days := deliveriesCmd.Flags().Lookup("days")
+	if days == nil || days.DefValue != "7" {
+		t.Error("Expected --days flag defaulting to 7")
+	}
+}
+
+func TestDeliveriesCmd_FormatFlags(t *testing.T) {
+	for _, c := range []*cobra.Command{deliveriesCmd, ordersTrackCmd} {
+		format := c.Flags().Lookup("format")
+		if format == nil || format.DefValue != "" {
+			t.Errorf("Expected --format flag on %s defaulting to empty", c.Name())
+		}
+	}
+}
+
+func TestTrackingDelivery(t *testing.T) {
+	tracking := &models.Tracking{Carrier: "UPS", DeliveryDate: "2026-03-05"}
+	delivery := trackingDelivery("111-2222222-3333333", "DmF7kq3Lz", "in transit", nil, tracking)
+
+	if delivery.Source != models.DeliverySourceOrder || delivery.ExpectedDate != "2026-03-05" {
+		t.Errorf("Unexpected delivery: %+v", delivery)
+	}
+	if delivery.OrderID != "111-2222222-3333333" || delivery.ShipmentID != "DmF7kq3Lz" || delivery.Tracking != tracking {
+		t.Errorf("Expected the order, shipment and tracking to be kept, got %+v", delivery)
+	}
+
+	if untracked := trackingDelivery("111-2222222-3333333", "", "pending", nil, nil); untracked.ExpectedDate != "" {
+		t.Errorf("Expected no date without tracking, got %q", untracked.ExpectedDate)
+	}
+}
@@ -1,11 +1,23 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"os/signal"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/zkwentz/amazon-cli/internal/accounting"
+	"github.com/zkwentz/amazon-cli/internal/amazon"
+	"github.com/zkwentz/amazon-cli/internal/calendar"
+	"github.com/zkwentz/amazon-cli/internal/config"
+	"github.com/zkwentz/amazon-cli/internal/hooks"
+	"github.com/zkwentz/amazon-cli/internal/marketplace"
+	"github.com/zkwentz/amazon-cli/internal/orderindex"
+	"github.com/zkwentz/amazon-cli/internal/orderstats"
 	"github.com/zkwentz/amazon-cli/internal/output"
 	"github.com/zkwentz/amazon-cli/pkg/models"
 )
@@ -14,6 +26,46 @@ var (
 	ordersLimit  int
 	ordersStatus string
 	ordersYear   int
+	ordersAll    bool
+	ordersSince  string
+	ordersUntil  string
+	ordersExpand bool
+	ordersType   string
+
+	ordersOffline  bool
+	ordersSyncFull bool
+
+	trackWatch    bool
+	trackInterval time.Duration
+	trackShipment string
+	trackPackages bool
+	trackFormat   string
+
+	invoiceOut   string
+	invoiceText  bool
+	invoicesYear int
+	invoicesDir  string
+
+	exportFormat string
+	exportYear   int
+	exportSince  string
+	exportUntil  string
+	exportOut    string
+
+	statsYear    int
+	statsSince   string
+	statsUntil   string
+	statsGroupBy string
+	statsTop     int
+
+	cancelItems   []string
+	cancelConfirm bool
+
+	reorderItems     []string
+	reorderConfirm   bool
+	reorderCheckout  bool
+	reorderAddressID string
+	reorderPaymentID string
 )
 
 // ordersCmd represents the orders command
@@ -27,11 +79,55 @@ var ordersCmd = &cobra.Command{
 var ordersListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List recent orders",
-	Long:  `Display a list of your recent Amazon orders with status and tracking info.`,
+	Long: `Display a list of your recent Amazon orders with status and tracking info.
+
+Use --since/--until (YYYY-MM-DD) to select a date range spanning any number of
+years, or --all to walk the full order history. With --output ndjson, orders are
+printed one per line as each page is fetched instead of after the walk completes.
+
+Items shown on the order history page are included for each order. Use --expand to
+fetch the detail page of orders missing item information (fetched concurrently,
+still subject to rate limiting).
+
+Use --type digital to list Kindle, video, app and software purchases instead of
+physical orders, or --type all for both merged by date. Digital items include their
+format, the device they were delivered to and any license.
+
+Use --offline to list orders from the local index built by 'orders sync'.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		query, err := ordersQuery(cmd)
+		if err != nil {
+			_ = output.Error(models.ErrInvalidInput, err.Error(), nil)
+			os.Exit(models.ExitInvalidArgs)
+		}
+		validateOrdersType()
+
+		if ordersOffline {
+			printOfflineOrders(amazon.OrderSearchQuery{
+				Status: query.Status,
+				Since:  query.Since,
+				Until:  query.Until,
+				Limit:  query.Limit,
+			})
+			return
+		}
+
 		c := getClient()
 
-		orders, err := c.GetOrders(ordersLimit, ordersStatus)
+		// Stream orders as they are parsed so long histories need not fit in memory
+		if viper.GetString("output") == string(output.FormatNDJSON) {
+			printer := output.NewPrinter(string(output.FormatNDJSON), false)
+			err := c.WalkOrders(query, func(order models.Order) error {
+				return printer.Print(order)
+			})
+			if err != nil {
+				_ = output.Error(models.ErrAmazonError, err.Error(), nil)
+				os.Exit(models.ExitGeneralError)
+			}
+			return
+		}
+
+		orders, err := c.ListOrders(query)
 		if err != nil {
 			_ = output.Error(models.ErrAmazonError, err.Error(), nil)
 			os.Exit(models.ExitGeneralError)
@@ -41,6 +137,158 @@ var ordersListCmd = &cobra.Command{
 	},
 }
 
+// validateOrdersType checks --type and exits if it is unsupported or combined with
+// --offline, since the local index only holds physical orders
+func validateOrdersType() {
+	if err := amazon.ValidateOrderType(ordersType); err != nil {
+		_ = output.Error(models.ErrInvalidInput, err.Error(), nil)
+		os.Exit(models.ExitInvalidArgs)
+	}
+	if ordersOffline && ordersType != "" && ordersType != models.OrderTypePhysical {
+		_ = output.Error(models.ErrInvalidInput, "--type "+ordersType+" cannot be combined with --offline: the local index only holds physical orders", nil)
+		os.Exit(models.ExitInvalidArgs)
+	}
+}
+
+// ordersQuery builds the order query from the orders list flags
+func ordersQuery(cmd *cobra.Command) (amazon.OrderQuery, error) {
+	query := amazon.OrderQuery{
+		Limit:  ordersLimit,
+		Status: ordersStatus,
+		All:    ordersAll,
+		Expand: ordersExpand,
+		Type:   ordersType,
+	}
+
+	// --all walks the whole history unless a limit is given explicitly
+	if ordersAll && !cmd.Flags().Changed("limit") {
+		query.Limit = 0
+	}
+	if query.Limit < 0 {
+		return query, fmt.Errorf("limit must not be negative")
+	}
+	if err := amazon.ValidateOrderStatus(query.Status); err != nil {
+		return query, err
+	}
+
+	var err error
+	query.Since, query.Until, err = dateRangeFlags(ordersSince, ordersUntil)
+	return query, err
+}
+
+// dateRangeFlags parses the --since and --until flags (YYYY-MM-DD); empty values are left zero
+func dateRangeFlags(since, until string) (time.Time, time.Time, error) {
+	var sinceDate, untilDate time.Time
+	var err error
+
+	if since != "" {
+		if sinceDate, err = time.Parse("2006-01-02", since); err != nil {
+			return sinceDate, untilDate, fmt.Errorf("invalid --since date %q: expected YYYY-MM-DD", since)
+		}
+	}
+	if until != "" {
+		if untilDate, err = time.Parse("2006-01-02", until); err != nil {
+			return sinceDate, untilDate, fmt.Errorf("invalid --until date %q: expected YYYY-MM-DD", until)
+		}
+	}
+	if !sinceDate.IsZero() && !untilDate.IsZero() && sinceDate.After(untilDate) {
+		return sinceDate, untilDate, fmt.Errorf("--since must not be after --until")
+	}
+
+	return sinceDate, untilDate, nil
+}
+
+// ordersSearchCmd represents the orders search command
+var ordersSearchCmd = &cobra.Command{
+	Use:   "search [text]",
+	Short: "Search order history",
+	Long: `Search your full order history by item title, ASIN, seller, order ID or amount.
+
+Text searches use Amazon's order search; if it is unavailable the local order index
+is searched when it has been synced, otherwise the order history is walked and
+filtered locally. Combine text with --asin, --min-total/--max-total, --status and
+--since/--until, or use the filters alone. Use --offline to search only the index.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		query, err := ordersSearchQuery(args)
+		if err != nil {
+			_ = output.Error(models.ErrInvalidInput, err.Error(), nil)
+			os.Exit(models.ExitInvalidArgs)
+		}
+
+		if ordersOffline {
+			printOfflineOrders(query)
+			return
+		}
+
+		// Fall back to the local index when Amazon's order search is unavailable
+		var opts []amazon.Option
+		if idx, err := openOrderIndex(); err == nil && idx.Len() > 0 {
+			opts = append(opts, amazon.WithOrderIndex(idx))
+		}
+		c := getClient(opts...)
+
+		orders, err := c.SearchOrders(query)
+		if err != nil {
+			_ = output.Error(models.ErrAmazonError, err.Error(), nil)
+			os.Exit(models.ExitGeneralError)
+		}
+
+		_ = output.JSON(orders)
+	},
+}
+
+var (
+	searchOrdersASIN     string
+	searchOrdersMinTotal float64
+	searchOrdersMaxTotal float64
+	searchOrdersStatus   string
+	searchOrdersSince    string
+	searchOrdersUntil    string
+	searchOrdersLimit    int
+)
+
+// ordersSearchQuery builds the order search query from the arguments and flags
+func ordersSearchQuery(args []string) (amazon.OrderSearchQuery, error) {
+	query := amazon.OrderSearchQuery{
+		ASIN:     strings.ToUpper(strings.TrimSpace(searchOrdersASIN)),
+		MinTotal: searchOrdersMinTotal,
+		MaxTotal: searchOrdersMaxTotal,
+		Status:   searchOrdersStatus,
+		Limit:    searchOrdersLimit,
+	}
+	if len(args) > 0 {
+		query.Text = strings.TrimSpace(args[0])
+	}
+
+	if query.MinTotal < 0 || query.MaxTotal < 0 || query.Limit < 0 {
+		return query, fmt.Errorf("--min-total, --max-total and --limit must not be negative")
+	}
+	if query.MaxTotal > 0 && query.MinTotal > query.MaxTotal {
+		return query, fmt.Errorf("--min-total must not be greater than --max-total")
+	}
+	if query.ASIN != "" {
+		if err := amazon.ValidateASIN(query.ASIN); err != nil {
+			return query, err
+		}
+	}
+	if err := amazon.ValidateOrderStatus(query.Status); err != nil {
+		return query, err
+	}
+
+	var err error
+	if query.Since, query.Until, err = dateRangeFlags(searchOrdersSince, searchOrdersUntil); err != nil {
+		return query, err
+	}
+
+	if query.Text == "" && query.ASIN == "" && query.MinTotal == 0 && query.MaxTotal == 0 &&
+		query.Status == "" && query.Since.IsZero() && query.Until.IsZero() {
+		return query, fmt.Errorf("provide search text or at least one filter")
+	}
+
+	return query, nil
+}
+
 // ordersGetCmd represents the orders get command
 var ordersGetCmd = &cobra.Command{
 	Use:   "get <order-id>",
@@ -57,6 +305,17 @@ var ordersGetCmd = &cobra.Command{
 			os.Exit(models.ExitInvalidArgs)
 		}
 
+		if ordersOffline {
+			idx := mustOpenOrderIndex()
+			order, exists := idx.Get(orderID)
+			if !exists {
+				_ = output.Error(models.ErrNotFound, "order not found in local index: "+orderID, nil)
+				os.Exit(models.ExitNotFound)
+			}
+			_ = output.JSON(order)
+			return
+		}
+
 		// Create client
 		c := getClient()
 
@@ -85,8 +344,26 @@ var ordersGetCmd = &cobra.Command{
 var ordersTrackCmd = &cobra.Command{
 	Use:   "track <order-id>",
 	Short: "Track order shipment",
-	Long:  `Display tracking information for an order's shipment.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Display tracking information for an order's shipment.
+
+Orders split into several packages can be tracked per package: --packages lists
+every package with the items it contains and its tracking, and --shipment tracks a
+single package by the shipment ID 'orders get' and --packages report.
+
+Use --format ics to write the expected delivery date as an iCalendar event, one per
+package with --packages. Event UIDs are derived from the order and shipment IDs, so
+re-importing updates the event.
+
+Use --watch to poll the shipment every --interval until it is delivered. Only
+changes are printed, as newline-delimited JSON: one line per new tracking event,
+a line when the status changes without a new event, and a final line with the
+outcome. A failed poll prints a line with its error and is retried at the next
+interval; the command gives up after 5 failed polls in a row and exits 1. The
+command exits 0 once delivered, 7 on a delivery exception (undeliverable, returned
+to sender, ...) and 130 when interrupted with Ctrl-C. Polls are rate limited like
+any other request. Changes between polls trigger the hooks configured in the
+config file.`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		orderID := args[0]
 
@@ -96,32 +373,142 @@ var ordersTrackCmd = &cobra.Command{
 			os.Exit(models.ExitInvalidArgs)
 		}
 
+		if trackPackages && (trackShipment != "" || trackWatch) {
+			_ = output.Error(models.ErrInvalidInput, "--packages cannot be combined with --shipment or --watch", nil)
+			os.Exit(models.ExitInvalidArgs)
+		}
+
+		validateCalendarFormat(trackFormat)
+		if trackFormat != "" && trackWatch {
+			_ = output.Error(models.ErrInvalidInput, "--format cannot be combined with --watch", nil)
+			os.Exit(models.ExitInvalidArgs)
+		}
+
+		if trackWatch {
+			watchOrderTracking(orderID, trackShipment)
+			return
+		}
+
 		c := getClient()
 
-		tracking, err := c.GetOrderTracking(orderID)
+		if trackPackages {
+			packages, err := c.GetOrderShipmentsTracking(orderID)
+			if err != nil {
+				_ = output.Error(models.ErrNotFound, err.Error(), nil)
+				os.Exit(models.ExitNotFound)
+			}
+			if trackFormat == calendar.FormatICS {
+				deliveries := make([]models.Delivery, len(packages.Shipments))
+				for i, shipment := range packages.Shipments {
+					deliveries[i] = trackingDelivery(packages.OrderID, shipment.ShipmentID, shipment.Status, shipment.Items, shipment.Tracking)
+				}
+				printCalendar(deliveries)
+				return
+			}
+			_ = output.JSON(packages)
+			return
+		}
+
+		tracking, err := c.GetShipmentTracking(orderID, trackShipment)
 		if err != nil {
 			_ = output.Error(models.ErrNotFound, err.Error(), nil)
 			os.Exit(models.ExitNotFound)
 		}
 
+		if trackFormat == calendar.FormatICS {
+			printCalendar([]models.Delivery{trackingDelivery(orderID, trackShipment, tracking.Status, nil, tracking)})
+			return
+		}
+
 		_ = output.JSON(tracking)
 	},
 }
 
+// trackingDelivery describes a tracked package as a delivery on its tracking delivery date
+func trackingDelivery(orderID, shipmentID, status string, items []models.OrderItem, tracking *models.Tracking) models.Delivery {
+	delivery := models.Delivery{
+		Source:     models.DeliverySourceOrder,
+		OrderID:    orderID,
+		ShipmentID: shipmentID,
+		Status:     status,
+		Items:      items,
+		Tracking:   tracking,
+	}
+	if tracking != nil {
+		delivery.ExpectedDate = tracking.DeliveryDate
+	}
+	return delivery
+}
+
+// minTrackInterval is the shortest polling interval allowed for orders track --watch
+const minTrackInterval = 30 * time.Second
+
+// watchOrderTracking polls the tracking of an order, or one of its packages, until
+// delivery, printing changes as NDJSON
+func watchOrderTracking(orderID, shipmentID string) {
+	if trackInterval < minTrackInterval {
+		_ = output.Error(models.ErrInvalidInput, fmt.Sprintf("--interval must be at least %s", minTrackInterval), nil)
+		os.Exit(models.ExitInvalidArgs)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	c := getClient(hookOptions(ctx)...)
+
+	printer := output.NewPrinter(string(output.FormatNDJSON), false)
+	outcome, err := c.WatchShipmentTracking(ctx, orderID, shipmentID, trackInterval, func(update models.TrackingUpdate) error {
+		if update.Error != "" {
+			fmt.Fprintln(os.Stderr, "warning: tracking poll failed:", update.Error)
+		}
+		return printer.Print(update)
+	})
+	if err != nil {
+		// Ctrl-C gets its own exit code so scripts can tell it from a delivery
+		if ctx.Err() != nil {
+			stop()
+			os.Exit(models.ExitInterrupted)
+		}
+		if strings.Contains(err.Error(), "invalid order ID format") {
+			_ = output.Error(models.ErrInvalidInput, err.Error(), nil)
+			os.Exit(models.ExitInvalidArgs)
+		}
+		_ = output.Error(models.ErrAmazonError, err.Error(), nil)
+		os.Exit(models.ExitGeneralError)
+	}
+
+	if outcome == amazon.TrackingException {
+		stop()
+		os.Exit(models.ExitTrackingException)
+	}
+}
+
 // ordersHistoryCmd represents the orders history command
 var ordersHistoryCmd = &cobra.Command{
 	Use:   "history",
 	Short: "Get order history",
-	Long:  `Display order history for a specific year.`,
+	Long: `Display order history for a specific year.
+
+Use --type digital for Kindle, video, app and software purchases, or --type all
+for physical and digital orders merged by date.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		c := getClient()
+		validateOrdersType()
 
 		year := ordersYear
 		if year == 0 {
 			year = time.Now().Year()
 		}
 
-		orders, err := c.GetOrderHistory(year)
+		if ordersOffline {
+			printOfflineOrders(amazon.OrderSearchQuery{
+				Since: time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC),
+				Until: time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC),
+			})
+			return
+		}
+
+		c := getClient()
+
+		orders, err := c.GetOrderHistoryByType(year, ordersType)
 		if err != nil {
 			_ = output.Error(models.ErrAmazonError, err.Error(), nil)
 			os.Exit(models.ExitGeneralError)
@@ -131,6 +518,490 @@ var ordersHistoryCmd = &cobra.Command{
 	},
 }
 
+// ordersSyncCmd represents the orders sync command
+var ordersSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync orders into the local index",
+	Long: `Pull new and changed orders into the local order index used by --offline.
+
+The first sync walks the full order history and fetches each order's details.
+Later syncs stop shortly after reaching the newest order seen by the previous
+sync; use --full to check every order again. Progress is saved every 10 orders
+and when a sync fails, so an interrupted sync resumes close to where it left off.
+Each --profile has its own index under ~/.amazon-cli/index/.
+
+Status and delivery date changes of stored orders trigger the hooks configured in
+the config file.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		idx := mustOpenOrderIndex()
+		c := getClient(hookOptions(context.Background())...)
+
+		result, err := c.SyncOrders(idx, amazon.SyncOptions{Full: ordersSyncFull})
+		if err != nil {
+			_ = output.Error(models.ErrAmazonError, err.Error(), nil)
+			os.Exit(models.ExitGeneralError)
+		}
+
+		_ = output.JSON(result)
+	},
+}
+
+// ordersInvoiceCmd represents the orders invoice command
+var ordersInvoiceCmd = &cobra.Command{
+	Use:   "invoice <order-id>",
+	Short: "Download an order invoice",
+	Long: `Download the printable invoice of an order as HTML.
+
+The invoice is saved to --out (default <order-id>.html). Use --text to also write a
+plain-text receipt next to it with a .txt extension.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		orderID := args[0]
+
+		path := invoiceOut
+		if path == "" {
+			path = orderID + ".html"
+		}
+
+		c := getClient()
+
+		file, err := c.SaveInvoice(orderID, path, invoiceText)
+		if err != nil {
+			if strings.Contains(err.Error(), "invalid order ID format") {
+				_ = output.Error(models.ErrInvalidInput, err.Error(), nil)
+				os.Exit(models.ExitInvalidArgs)
+			}
+			if strings.Contains(err.Error(), "invoice not found") {
+				_ = output.Error(models.ErrNotFound, err.Error(), nil)
+				os.Exit(models.ExitNotFound)
+			}
+			_ = output.Error(models.ErrAmazonError, err.Error(), nil)
+			os.Exit(models.ExitGeneralError)
+		}
+
+		_ = output.JSON(file)
+	},
+}
+
+// ordersInvoicesCmd represents the orders invoices command
+var ordersInvoicesCmd = &cobra.Command{
+	Use:   "invoices",
+	Short: "Download invoices for a year",
+	Long: `Download the invoices of every order placed in a year.
+
+Files are named <date>_<order-id>.html in --dir. Invoices already downloaded are
+skipped, so the command can be rerun to pick up new orders or retry failures.
+Use --text to also write plain-text receipts.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		year := invoicesYear
+		if year == 0 {
+			year = time.Now().Year()
+		}
+
+		c := getClient()
+
+		response, err := c.DownloadInvoices(year, invoicesDir, invoiceText)
+		if err != nil {
+			_ = output.Error(models.ErrAmazonError, err.Error(), nil)
+			os.Exit(models.ExitGeneralError)
+		}
+
+		_ = output.JSON(response)
+	},
+}
+
+// ordersExportCmd represents the orders export command
+var ordersExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export orders for plain-text accounting",
+	Long: `Export orders as beancount, ledger, hledger, OFX or QIF transactions.
+
+Items are posted to accounts chosen by the "accounting" rules in the config file
+(matched by category, seller or ASIN regular expression), and tax, shipping,
+promotions and gift card amounts get postings of their own. Every order is exported
+as a purchase; when its detail page shows a refund, a refund transaction for the
+refunded amount follows. Cancelled orders are skipped.
+
+Each order's detail page is fetched for the charges breakdown; use --offline to
+export from the local index built by 'orders sync' instead. Without --year or
+--since/--until, the full order history is exported.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := accounting.ValidateFormat(exportFormat); err != nil {
+			_ = output.Error(models.ErrInvalidInput, err.Error(), nil)
+			os.Exit(models.ExitInvalidArgs)
+		}
+
+		since, until, err := yearDateRange(exportYear, exportSince, exportUntil)
+		if err != nil {
+			_ = output.Error(models.ErrInvalidInput, err.Error(), nil)
+			os.Exit(models.ExitInvalidArgs)
+		}
+
+		cfg, err := config.LoadConfig(cfgFile)
+		if err != nil {
+			_ = output.Error(models.ErrInvalidInput, err.Error(), nil)
+			os.Exit(models.ExitInvalidArgs)
+		}
+		accountingConfig := accounting.Config{}
+		if cfg.Accounting != nil {
+			accountingConfig = *cfg.Accounting
+		}
+		exporter, err := accounting.NewExporter(accountingConfig)
+		if err != nil {
+			_ = output.Error(models.ErrInvalidInput, err.Error(), nil)
+			os.Exit(models.ExitInvalidArgs)
+		}
+
+		// Collect the orders with their charges
+		orders, m := ordersForReport(amazon.OrderQuery{All: true, Since: since, Until: until, Details: true})
+
+		transactions, err := exporter.Transactions(orders, m)
+		if err != nil {
+			_ = output.Error(models.ErrAmazonError, err.Error(), nil)
+			os.Exit(models.ExitGeneralError)
+		}
+
+		if exportOut == "" {
+			if err := accounting.Write(os.Stdout, exportFormat, transactions); err != nil {
+				_ = output.Error(models.ErrAmazonError, err.Error(), nil)
+				os.Exit(models.ExitGeneralError)
+			}
+			return
+		}
+
+		file, err := os.OpenFile(exportOut, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err == nil {
+			err = accounting.Write(file, exportFormat, transactions)
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		if err != nil {
+			_ = output.Error(models.ErrAmazonError, err.Error(), nil)
+			os.Exit(models.ExitGeneralError)
+		}
+
+		_ = output.JSON(map[string]interface{}{
+			"format":       exportFormat,
+			"path":         exportOut,
+			"transactions": len(transactions),
+		})
+	},
+}
+
+// yearDateRange returns the date range of --year or --since/--until; a year covers its calendar year
+func yearDateRange(year int, since, until string) (time.Time, time.Time, error) {
+	if year != 0 {
+		if since != "" || until != "" {
+			return time.Time{}, time.Time{}, fmt.Errorf("--year cannot be combined with --since or --until")
+		}
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC), nil
+	}
+	return dateRangeFlags(since, until)
+}
+
+// ordersStatsCmd represents the orders stats command
+var ordersStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Summarize spending",
+	Long: `Summarize spending by month, category, seller or ASIN.
+
+Reports totals, order and item counts, averages, refunds and the top items for the
+orders placed in --year (default: current year) or --since/--until. Cancelled
+orders are ignored; refunded amounts shown on order detail pages count as refunds,
+matching 'orders export'. With --output
+table or csv, a row is printed per group followed by a total row.
+
+Category and seller groupings fetch each order's detail page; use --offline to
+compute from the local index built by 'orders sync' instead.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := orderstats.ValidateGroupBy(statsGroupBy); err != nil {
+			_ = output.Error(models.ErrInvalidInput, err.Error(), nil)
+			os.Exit(models.ExitInvalidArgs)
+		}
+		if statsTop < 0 {
+			_ = output.Error(models.ErrInvalidInput, "--top must not be negative", nil)
+			os.Exit(models.ExitInvalidArgs)
+		}
+
+		year := statsYear
+		if year == 0 && statsSince == "" && statsUntil == "" {
+			year = time.Now().Year()
+		}
+		since, until, err := yearDateRange(year, statsSince, statsUntil)
+		if err != nil {
+			_ = output.Error(models.ErrInvalidInput, err.Error(), nil)
+			os.Exit(models.ExitInvalidArgs)
+		}
+
+		// Sellers and categories are only shown on order detail pages
+		query := amazon.OrderQuery{All: true, Since: since, Until: until, Expand: true}
+		if statsGroupBy == orderstats.GroupByCategory || statsGroupBy == orderstats.GroupBySeller {
+			query.Details = true
+		}
+
+		orders, m := ordersForReport(query)
+		stats, err := orderstats.Compute(orders, statsGroupBy, statsTop, m)
+		if err != nil {
+			_ = output.Error(models.ErrAmazonError, err.Error(), nil)
+			os.Exit(models.ExitGeneralError)
+		}
+
+		_ = output.NewPrinter(viper.GetString("output"), false).Print(stats)
+	},
+}
+
+// ordersForReport returns the orders in the query's date range from Amazon, or from
+// the local index with --offline, along with the marketplace they belong to
+func ordersForReport(query amazon.OrderQuery) ([]models.Order, *marketplace.Marketplace) {
+	if ordersOffline {
+		idx := mustOpenOrderIndex()
+		m, err := marketplace.Get(idx.Marketplace())
+		if err != nil {
+			m = marketplace.Default()
+		}
+		return amazon.OrderSearchQuery{Since: query.Since, Until: query.Until}.Filter(idx.Orders(), m), m
+	}
+
+	c := getClient()
+	response, err := c.ListOrders(query)
+	if err != nil {
+		_ = output.Error(models.ErrAmazonError, err.Error(), nil)
+		os.Exit(models.ExitGeneralError)
+	}
+	return response.Orders, c.Marketplace()
+}
+
+// ordersCancelCmd represents the orders cancel command
+var ordersCancelCmd = &cobra.Command{
+	Use:   "cancel <order-id>",
+	Short: "Cancel an order or items before shipment",
+	Long: `Cancel an order, or individual items with --item, before they ship.
+Without --item, every item that can still be cancelled is cancelled.
+Requires --confirm flag to execute the cancellation.
+Without --confirm, shows which items are still cancellable.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		orderID := args[0]
+		c := getClient()
+
+		// Without --confirm, show cancellation preview
+		if !cancelConfirm {
+			cancellation, err := c.GetCancellableItems(orderID)
+			if err != nil {
+				exitCancelError(err)
+			}
+
+			// Requested items are checked like --confirm would; without --item, an
+			// order with nothing left to cancel just previews an empty selection
+			toCancel, err := amazon.SelectCancellableItems(cancellation, cancelItems)
+			if err != nil && len(cancelItems) > 0 {
+				exitCancelError(err)
+			}
+			if toCancel == nil {
+				toCancel = []models.CancellableItem{}
+			}
+
+			_ = output.JSON(map[string]interface{}{
+				"dry_run":   true,
+				"order_id":  orderID,
+				"items":     cancellation.Items,
+				"to_cancel": toCancel,
+				"message":   "Add --confirm to cancel these items",
+			})
+			return
+		}
+
+		// With --confirm, execute the cancellation
+		result, err := c.CancelOrder(orderID, cancelItems)
+		if err != nil {
+			exitCancelError(err)
+		}
+
+		_ = output.JSON(result)
+	},
+}
+
+// exitCancelError reports an orders cancel error with the matching error code and exits
+func exitCancelError(err error) {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "invalid order ID format"), strings.Contains(msg, "can no longer be cancelled"),
+		strings.Contains(msg, "no items that can still be cancelled"), strings.Contains(msg, "is not in order"):
+		_ = output.Error(models.ErrInvalidInput, msg, nil)
+		os.Exit(models.ExitInvalidArgs)
+	case strings.Contains(msg, "order not found"):
+		_ = output.Error(models.ErrNotFound, msg, nil)
+		os.Exit(models.ExitNotFound)
+	default:
+		_ = output.Error(models.ErrAmazonError, msg, nil)
+		os.Exit(models.ExitGeneralError)
+	}
+}
+
+// ordersReorderCmd represents the orders reorder command
+var ordersReorderCmd = &cobra.Command{
+	Use:   "reorder <order-id>",
+	Short: "Order the items of a past order again",
+	Long: `Add the items of a past order, or individual items with --item, to the cart
+in their original quantities.
+
+Without --confirm, shows each item's old and current price and whether it is still
+in stock. Items that are out of stock or no longer sold are skipped.
+With --checkout, the cart is checked out after the items are added, using the
+default address and payment method unless --address-id or --payment-id is set.
+Checkout buys the whole cart, so --checkout is refused while the cart holds other items.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if (reorderAddressID != "" || reorderPaymentID != "") && !reorderCheckout {
+			_ = output.Error(models.ErrInvalidInput, "--address-id and --payment-id require --checkout", nil)
+			os.Exit(models.ExitInvalidArgs)
+		}
+
+		c := getClient()
+
+		plan, err := c.PlanReorder(args[0], reorderItems)
+		if err != nil {
+			exitReorderError(err)
+		}
+
+		// Checkout buys the whole cart, so refuse when it holds anything else
+		if reorderCheckout {
+			if err := c.CheckReorderCheckout(); err != nil {
+				exitReorderError(err)
+			}
+		}
+
+		// Without --confirm, show how prices changed since the order
+		if !reorderConfirm {
+			message := "Add --confirm to add these items to the cart"
+			if reorderCheckout {
+				message = "Add --confirm to add these items to the cart and complete purchase"
+			}
+			_ = output.JSON(map[string]interface{}{
+				"dry_run":   true,
+				"order_id":  plan.OrderID,
+				"currency":  plan.Currency,
+				"items":     plan.Items,
+				"old_total": plan.OldTotal,
+				"new_total": plan.NewTotal,
+				"message":   message,
+			})
+			return
+		}
+
+		result, err := c.Reorder(plan)
+		if err != nil {
+			exitReorderError(err)
+		}
+
+		if !reorderCheckout {
+			_ = output.JSON(result)
+			return
+		}
+
+		addressID, paymentID := checkoutIDs(c, reorderAddressID, reorderPaymentID)
+		confirmation, err := c.CompleteCheckout(addressID, paymentID)
+		if err != nil {
+			_ = output.Error(models.ErrPurchaseFailed, "Checkout failed: "+err.Error(), nil)
+			os.Exit(models.ExitGeneralError)
+		}
+
+		_ = output.JSON(map[string]interface{}{
+			"order_id":     plan.OrderID,
+			"added":        result.Added,
+			"skipped":      result.Skipped,
+			"confirmation": confirmation,
+		})
+	},
+}
+
+// exitReorderError reports an orders reorder error with the matching error code and exits
+func exitReorderError(err error) {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "invalid order ID format"), strings.Contains(msg, "can be reordered"),
+		strings.Contains(msg, "cart already contains"):
+		_ = output.Error(models.ErrInvalidInput, msg, nil)
+		os.Exit(models.ExitInvalidArgs)
+	case strings.Contains(msg, "order not found"), strings.Contains(msg, "is not in order"):
+		_ = output.Error(models.ErrNotFound, msg, nil)
+		os.Exit(models.ExitNotFound)
+	default:
+		_ = output.Error(models.ErrAmazonError, msg, nil)
+		os.Exit(models.ExitGeneralError)
+	}
+}
+
+// hookOptions returns the client options that run the hooks configured in the config
+// file for the changes the client detects. Hook failures are reported on stderr and
+// don't stop the command.
+func hookOptions(ctx context.Context) []amazon.Option {
+	cfg, err := config.LoadConfig(cfgFile)
+	if err != nil {
+		_ = output.Error(models.ErrInvalidInput, err.Error(), nil)
+		os.Exit(models.ExitInvalidArgs)
+	}
+	if len(cfg.Hooks) == 0 {
+		return nil
+	}
+
+	dispatcher, err := hooks.NewDispatcher(cfg.Hooks)
+	if err != nil {
+		_ = output.Error(models.ErrInvalidInput, err.Error(), nil)
+		os.Exit(models.ExitInvalidArgs)
+	}
+
+	return []amazon.Option{amazon.WithNotifier(func(event models.HookEvent) {
+		if err := dispatcher.Dispatch(ctx, event); err != nil {
+			fmt.Fprintln(os.Stderr, "warning:", err)
+		}
+	})}
+}
+
+// openOrderIndex opens the local order index of the selected profile
+func openOrderIndex() (*orderindex.Index, error) {
+	profile := viper.GetString("profile")
+	if profile == "" {
+		profile = orderindex.DefaultProfile
+	}
+	if err := orderindex.ValidateProfile(profile); err != nil {
+		return nil, err
+	}
+	return orderindex.Open(orderindex.DefaultPath(profile), profile)
+}
+
+// mustOpenOrderIndex opens the local order index or exits with an error
+func mustOpenOrderIndex() *orderindex.Index {
+	idx, err := openOrderIndex()
+	if err != nil {
+		_ = output.Error(models.ErrInvalidInput, err.Error(), nil)
+		os.Exit(models.ExitInvalidArgs)
+	}
+	return idx
+}
+
+// printOfflineOrders prints the indexed orders matching the query
+func printOfflineOrders(query amazon.OrderSearchQuery) {
+	idx := mustOpenOrderIndex()
+	if idx.Len() == 0 {
+		_ = output.Error(models.ErrNotFound, "local order index for profile "+idx.Profile()+" is empty; run 'amazon-cli orders sync' first", nil)
+		os.Exit(models.ExitNotFound)
+	}
+
+	m, err := marketplace.Get(idx.Marketplace())
+	if err != nil {
+		m = marketplace.Default()
+	}
+
+	orders := query.Filter(idx.Orders(), m)
+	_ = output.JSON(&models.OrdersResponse{
+		Orders:     orders,
+		TotalCount: len(orders),
+	})
+}
+
 func init() {
 	rootCmd.AddCommand(ordersCmd)
 
@@ -139,11 +1010,81 @@ func init() {
 	ordersCmd.AddCommand(ordersGetCmd)
 	ordersCmd.AddCommand(ordersTrackCmd)
 	ordersCmd.AddCommand(ordersHistoryCmd)
+	ordersCmd.AddCommand(ordersSearchCmd)
+	ordersCmd.AddCommand(ordersSyncCmd)
+	ordersCmd.AddCommand(ordersInvoiceCmd)
+	ordersCmd.AddCommand(ordersInvoicesCmd)
+	ordersCmd.AddCommand(ordersExportCmd)
+	ordersCmd.AddCommand(ordersStatsCmd)
+	ordersCmd.AddCommand(ordersCancelCmd)
+	ordersCmd.AddCommand(ordersReorderCmd)
 
 	// Flags for orders list
 	ordersListCmd.Flags().IntVar(&ordersLimit, "limit", 10, "Number of orders to return")
-	ordersListCmd.Flags().StringVar(&ordersStatus, "status", "", "Filter by status: pending, delivered, returned")
+	ordersListCmd.Flags().StringVar(&ordersStatus, "status", "", "Filter by status: pending (incl. out_for_delivery, delayed), delivered, cancelled, returned (incl. return_started, refunded)")
+	ordersListCmd.Flags().BoolVar(&ordersAll, "all", false, "Walk the full order history (no limit unless --limit is set)")
+	ordersListCmd.Flags().StringVar(&ordersSince, "since", "", "Only orders placed on or after this date (YYYY-MM-DD)")
+	ordersListCmd.Flags().StringVar(&ordersUntil, "until", "", "Only orders placed on or before this date (YYYY-MM-DD)")
+	ordersListCmd.Flags().BoolVar(&ordersExpand, "expand", false, "Fetch order details for orders whose list entry lacks item prices or tracking")
+	ordersListCmd.Flags().StringVar(&ordersType, "type", models.OrderTypePhysical, "Order type: "+strings.Join(amazon.OrderTypes(), ", "))
+
+	// Flags for orders search
+	ordersSearchCmd.Flags().StringVar(&searchOrdersASIN, "asin", "", "Only orders containing this ASIN")
+	ordersSearchCmd.Flags().Float64Var(&searchOrdersMinTotal, "min-total", 0, "Only orders with a total of at least this amount")
+	ordersSearchCmd.Flags().Float64Var(&searchOrdersMaxTotal, "max-total", 0, "Only orders with a total of at most this amount")
+	ordersSearchCmd.Flags().StringVar(&searchOrdersStatus, "status", "", "Only orders with this status (same values as orders list --status)")
+	ordersSearchCmd.Flags().StringVar(&searchOrdersSince, "since", "", "Only orders placed on or after this date (YYYY-MM-DD)")
+	ordersSearchCmd.Flags().StringVar(&searchOrdersUntil, "until", "", "Only orders placed on or before this date (YYYY-MM-DD)")
+	ordersSearchCmd.Flags().IntVar(&searchOrdersLimit, "limit", 0, "Maximum number of results (0 for all)")
+
+	// Flags for orders track
+	ordersTrackCmd.Flags().BoolVar(&trackWatch, "watch", false, "Poll until delivery, printing new tracking events as NDJSON")
+	ordersTrackCmd.Flags().DurationVar(&trackInterval, "interval", 5*time.Minute, "Polling interval for --watch (minimum 30s)")
+	ordersTrackCmd.Flags().StringVar(&trackShipment, "shipment", "", "Track only the package with this shipment ID")
+	ordersTrackCmd.Flags().BoolVar(&trackPackages, "packages", false, "Track every package of the order with the items it contains")
+	ordersTrackCmd.Flags().StringVar(&trackFormat, "format", "", "Export format: ics (default: JSON)")
 
 	// Flags for orders history
 	ordersHistoryCmd.Flags().IntVar(&ordersYear, "year", 0, "Year to fetch orders from (default: current year)")
+	ordersHistoryCmd.Flags().StringVar(&ordersType, "type", models.OrderTypePhysical, "Order type: "+strings.Join(amazon.OrderTypes(), ", "))
+
+	// Flags for orders sync
+	ordersSyncCmd.Flags().BoolVar(&ordersSyncFull, "full", false, "Check every order instead of stopping after the last synced order")
+
+	// Flags for orders invoice and invoices
+	ordersInvoiceCmd.Flags().StringVar(&invoiceOut, "out", "", "File to save the invoice to (default <order-id>.html)")
+	ordersInvoiceCmd.Flags().BoolVar(&invoiceText, "text", false, "Also write a plain-text receipt (.txt)")
+	ordersInvoicesCmd.Flags().IntVar(&invoicesYear, "year", 0, "Year of the orders (default: current year)")
+	ordersInvoicesCmd.Flags().StringVar(&invoicesDir, "dir", "invoices", "Directory to save invoices to")
+	ordersInvoicesCmd.Flags().BoolVar(&invoiceText, "text", false, "Also write plain-text receipts (.txt)")
+
+	// Flags for orders export
+	ordersExportCmd.Flags().StringVar(&exportFormat, "format", accounting.FormatBeancount, "Export format: "+strings.Join(accounting.Formats(), ", "))
+	ordersExportCmd.Flags().IntVar(&exportYear, "year", 0, "Only orders placed in this year")
+	ordersExportCmd.Flags().StringVar(&exportSince, "since", "", "Only orders placed on or after this date (YYYY-MM-DD)")
+	ordersExportCmd.Flags().StringVar(&exportUntil, "until", "", "Only orders placed on or before this date (YYYY-MM-DD)")
+	ordersExportCmd.Flags().StringVar(&exportOut, "out", "", "File to write the export to (default: stdout)")
+
+	// Flags for orders stats
+	ordersStatsCmd.Flags().IntVar(&statsYear, "year", 0, "Only orders placed in this year (default: current year)")
+	ordersStatsCmd.Flags().StringVar(&statsSince, "since", "", "Only orders placed on or after this date (YYYY-MM-DD)")
+	ordersStatsCmd.Flags().StringVar(&statsUntil, "until", "", "Only orders placed on or before this date (YYYY-MM-DD)")
+	ordersStatsCmd.Flags().StringVar(&statsGroupBy, "group-by", orderstats.GroupByMonth, "Group spending by: "+strings.Join(orderstats.GroupBys(), ", "))
+	ordersStatsCmd.Flags().IntVar(&statsTop, "top", orderstats.DefaultTopItems, "Number of top items to report")
+
+	// Flags for orders cancel
+	ordersCancelCmd.Flags().StringSliceVar(&cancelItems, "item", nil, "ASIN of an item to cancel (repeatable; default: all cancellable items)")
+	ordersCancelCmd.Flags().BoolVar(&cancelConfirm, "confirm", false, "Confirm the cancellation")
+
+	// Flags for orders reorder
+	ordersReorderCmd.Flags().StringSliceVar(&reorderItems, "item", nil, "ASIN of an item to reorder (repeatable; default: all items)")
+	ordersReorderCmd.Flags().BoolVar(&reorderConfirm, "confirm", false, "Add the items to the cart")
+	ordersReorderCmd.Flags().BoolVar(&reorderCheckout, "checkout", false, "Complete purchase after adding the items to the cart")
+	ordersReorderCmd.Flags().StringVar(&reorderAddressID, "address-id", "", "Shipping address ID for --checkout")
+	ordersReorderCmd.Flags().StringVar(&reorderPaymentID, "payment-id", "", "Payment method ID for --checkout")
+
+	// Serve from the local order index instead of Amazon
+	for _, c := range []*cobra.Command{ordersListCmd, ordersGetCmd, ordersSearchCmd, ordersHistoryCmd, ordersExportCmd, ordersStatsCmd} {
+		c.Flags().BoolVar(&ordersOffline, "offline", false, "Serve from the local order index (see 'orders sync')")
+	}
 }
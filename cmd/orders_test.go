@@ -4,8 +4,11 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"testing"
 
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"github.com/zkwentz/amazon-cli/internal/amazon"
 	"github.com/zkwentz/amazon-cli/pkg/models"
 )
@@ -199,7 +202,7 @@ func TestOrdersHistoryCmd_Success(t *testing.T) {
 
 func TestOrdersCmd_Subcommands(t *testing.T) {
 	// Test that all subcommands are registered
-	expectedSubcommands := []string{"list", "get", "track", "history"}
+	expectedSubcommands := []string{"list", "get", "track", "history", "search", "sync", "invoice", "invoices", "export", "stats", "cancel", "reorder"}
 	commands := ordersCmd.Commands()
 
 	if len(commands) != len(expectedSubcommands) {
@@ -210,7 +213,7 @@ func TestOrdersCmd_Subcommands(t *testing.T) {
 	for _, expected := range expectedSubcommands {
 		found := false
 		for _, cmd := range commands {
-			if cmd.Use == expected || cmd.Use == expected+" <order-id>" {
+			if cmd.Use == expected || cmd.Use == expected+" <order-id>" || cmd.Name() == expected {
 				found = true
 				break
 			}
@@ -430,3 +433,268 @@ func TestOrdersGetCmd_ResponseParsing(t *testing.T) {
 		t.Error("Expected 'tracking' field in JSON output")
 	}
 }
+
+func TestOrdersListCmd_RangeFlags(t *testing.T) {
+	for _, name := range []string{"all", "since", "until", "expand"} {
+		if ordersListCmd.Flags().Lookup(name) == nil {
+			t.Errorf("Expected --%s flag to be defined", name)
+		}
+	}
+}
+
+func TestOrdersQuery(t *testing.T) {
+	// Use a fresh command so Changed() reflects only this test's flags
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().IntVar(&ordersLimit, "limit", 10, "")
+		cmd.Flags().StringVar(&ordersStatus, "status", "", "")
+		cmd.Flags().BoolVar(&ordersAll, "all", false, "")
+		cmd.Flags().StringVar(&ordersSince, "since", "", "")
+		cmd.Flags().StringVar(&ordersUntil, "until", "", "")
+		return cmd
+	}
+	defer func() {
+		ordersLimit, ordersStatus, ordersAll, ordersSince, ordersUntil = 10, "", false, "", ""
+	}()
+
+	tests := []struct {
+		name      string
+		args      []string
+		wantLimit int
+		wantErr   bool
+	}{
+		{name: "default", args: nil, wantLimit: 10},
+		{name: "all removes limit", args: []string{"--all"}, wantLimit: 0},
+		{name: "all with explicit limit", args: []string{"--all", "--limit", "50"}, wantLimit: 50},
+		{name: "date range", args: []string{"--since", "2020-01-01", "--until", "2024-12-31"}, wantLimit: 10},
+		{name: "invalid since", args: []string{"--since", "01/01/2020"}, wantErr: true},
+		{name: "invalid until", args: []string{"--until", "yesterday"}, wantErr: true},
+		{name: "since after until", args: []string{"--since", "2025-01-01", "--until", "2024-01-01"}, wantErr: true},
+		{name: "negative limit", args: []string{"--limit", "-1"}, wantErr: true},
+		{name: "status", args: []string{"--status", "out_for_delivery"}, wantLimit: 10},
+		{name: "unknown status", args: []string{"--status", "delivred"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newCmd()
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("ParseFlags() error = %v", err)
+			}
+
+			query, err := ordersQuery(cmd)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ordersQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && query.Limit != tt.wantLimit {
+				t.Errorf("Expected limit %d, got %d", tt.wantLimit, query.Limit)
+			}
+		})
+	}
+
+	cmd := newCmd()
+	_ = cmd.ParseFlags([]string{"--since", "2020-01-01", "--until", "2024-12-31"})
+	query, _ := ordersQuery(cmd)
+	if query.Since.Year() != 2020 || query.Until.Year() != 2024 {
+		t.Errorf("Expected range 2020-2024, got %v - %v", query.Since, query.Until)
+	}
+}
+
+func TestOrdersSearchQuery(t *testing.T) {
+	defer func() {
+		searchOrdersASIN, searchOrdersStatus, searchOrdersSince, searchOrdersUntil = "", "", "", ""
+		searchOrdersMinTotal, searchOrdersMaxTotal, searchOrdersLimit = 0, 0, 0
+	}()
+
+	// Text alone is a valid search
+	query, err := ordersSearchQuery([]string{"  headphones "})
+	if err != nil {
+		t.Fatalf("ordersSearchQuery() error = %v", err)
+	}
+	if query.Text != "headphones" {
+		t.Errorf("Expected trimmed text, got %q", query.Text)
+	}
+
+	// Filters alone are a valid search
+	searchOrdersASIN = "b08n5wrwnw"
+	searchOrdersMinTotal = 10
+	searchOrdersMaxTotal = 100
+	searchOrdersSince = "2023-01-01"
+	query, err = ordersSearchQuery(nil)
+	if err != nil {
+		t.Fatalf("ordersSearchQuery() error = %v", err)
+	}
+	if query.ASIN != "B08N5WRWNW" || query.Since.Year() != 2023 {
+		t.Errorf("Unexpected query: %+v", query)
+	}
+
+	// Invalid combinations are rejected
+	searchOrdersMinTotal = 200
+	if _, err := ordersSearchQuery(nil); err == nil {
+		t.Error("Expected error when --min-total exceeds --max-total")
+	}
+	searchOrdersMinTotal = 0
+
+	searchOrdersASIN = "bad"
+	if _, err := ordersSearchQuery(nil); err == nil {
+		t.Error("Expected error for invalid ASIN")
+	}
+	searchOrdersASIN = ""
+
+	searchOrdersUntil = "2022-01-01"
+	if _, err := ordersSearchQuery(nil); err == nil {
+		t.Error("Expected error when --since is after --until")
+	}
+	searchOrdersSince, searchOrdersUntil, searchOrdersMaxTotal = "", "", 0
+
+	searchOrdersStatus = "shipped"
+	if _, err := ordersSearchQuery([]string{"lamp"}); err == nil {
+		t.Error("Expected error for an unknown status")
+	}
+	searchOrdersStatus = ""
+
+	if _, err := ordersSearchQuery(nil); err == nil {
+		t.Error("Expected error without text or filters")
+	}
+}
+
+func TestOrdersCmd_OfflineFlag(t *testing.T) {
+	for _, c := range []*cobra.Command{ordersListCmd, ordersGetCmd, ordersSearchCmd, ordersHistoryCmd} {
+		if c.Flags().Lookup("offline") == nil {
+			t.Errorf("Expected --offline flag on orders %s", c.Name())
+		}
+	}
+	if ordersSyncCmd.Flags().Lookup("full") == nil {
+		t.Error("Expected --full flag on orders sync")
+	}
+}
+
+func TestOpenOrderIndex_UsesProfile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	defer viper.Set("profile", "")
+
+	viper.Set("profile", "work")
+	idx, err := openOrderIndex()
+	if err != nil {
+		t.Fatalf("openOrderIndex() error = %v", err)
+	}
+	if idx.Profile() != "work" {
+		t.Errorf("Expected profile work, got %s", idx.Profile())
+	}
+	if expected := filepath.Join(home, ".amazon-cli", "index", "work.json"); idx.Path() != expected {
+		t.Errorf("Expected index path %s, got %s", expected, idx.Path())
+	}
+
+	viper.Set("profile", "../escape")
+	if _, err := openOrderIndex(); err == nil {
+		t.Error("Expected error for invalid profile name")
+	}
+}
+
+func TestOrdersInvoiceCmd_Flags(t *testing.T) {
+	if ordersInvoiceCmd.Flags().Lookup("out") == nil || ordersInvoiceCmd.Flags().Lookup("text") == nil {
+		t.Error("Expected --out and --text flags on orders invoice")
+	}
+	for _, name := range []string{"year", "dir", "text"} {
+		if ordersInvoicesCmd.Flags().Lookup(name) == nil {
+			t.Errorf("Expected --%s flag on orders invoices", name)
+		}
+	}
+	if dir := ordersInvoicesCmd.Flags().Lookup("dir").DefValue; dir != "invoices" {
+		t.Errorf("Expected default directory invoices, got %s", dir)
+	}
+}
+
+func TestYearDateRange(t *testing.T) {
+	defer func() {
+		exportYear, exportSince, exportUntil = 0, "", ""
+	}()
+
+	exportYear = 2024
+	since, until, err := yearDateRange(exportYear, exportSince, exportUntil)
+	if err != nil {
+		t.Fatalf("yearDateRange(exportYear, exportSince, exportUntil) error = %v", err)
+	}
+	if since.Format("2006-01-02") != "2024-01-01" || until.Format("2006-01-02") != "2024-12-31" {
+		t.Errorf("Expected the 2024 calendar year, got %v to %v", since, until)
+	}
+
+	exportSince = "2024-03-01"
+	if _, _, err := yearDateRange(exportYear, exportSince, exportUntil); err == nil {
+		t.Error("Expected error combining --year with --since")
+	}
+
+	exportYear = 0
+	since, until, err = yearDateRange(exportYear, exportSince, exportUntil)
+	if err != nil || since.Format("2006-01-02") != "2024-03-01" || !until.IsZero() {
+		t.Errorf("Expected --since alone to be used, got %v to %v (%v)", since, until, err)
+	}
+
+	if ordersExportCmd.Flags().Lookup("format").DefValue != "beancount" {
+		t.Error("Expected beancount as the default export format")
+	}
+}
+
+func TestOrdersStatsCmd_Flags(t *testing.T) {
+	for _, name := range []string{"year", "since", "until", "group-by", "top", "offline"} {
+		if ordersStatsCmd.Flags().Lookup(name) == nil {
+			t.Errorf("Expected --%s flag on orders stats", name)
+		}
+	}
+	if groupBy := ordersStatsCmd.Flags().Lookup("group-by").DefValue; groupBy != "month" {
+		t.Errorf("Expected default grouping month, got %s", groupBy)
+	}
+}
+
+func TestOrdersCancelCmd_Flags(t *testing.T) {
+	if ordersCancelCmd.Flags().Lookup("item") == nil {
+		t.Error("Expected --item flag on orders cancel")
+	}
+	confirm := ordersCancelCmd.Flags().Lookup("confirm")
+	if confirm == nil || confirm.DefValue != "false" {
+		t.Error("Expected --confirm flag defaulting to false (dry run)")
+	}
+}
+
+func TestOrdersCmd_TypeFlags(t *testing.T) {
+	for _, c := range []*cobra.Command{ordersListCmd, ordersHistoryCmd} {
+		flag := c.Flags().Lookup("type")
+		if flag == nil || flag.DefValue != models.OrderTypePhysical {
+			t.Errorf("Expected --type flag on orders %s defaulting to physical", c.Name())
+		}
+	}
+}
+
+func TestOrdersReorderCmd_Flags(t *testing.T) {
+	if ordersReorderCmd.Flags().Lookup("item") == nil {
+		t.Error("Expected --item flag on orders reorder")
+	}
+	for _, name := range []string{"confirm", "checkout"} {
+		flag := ordersReorderCmd.Flags().Lookup(name)
+		if flag == nil || flag.DefValue != "false" {
+			t.Errorf("Expected --%s flag defaulting to false", name)
+		}
+	}
+}
+
+func TestOrdersTrackCmd_WatchFlags(t *testing.T) {
+	watch := ordersTrackCmd.Flags().Lookup("watch")
+	if watch == nil || watch.DefValue != "false" {
+		t.Error("Expected --watch flag defaulting to false")
+	}
+	interval := ordersTrackCmd.Flags().Lookup("interval")
+	if interval == nil || interval.DefValue != "5m0s" {
+		t.Error("Expected --interval flag defaulting to 5m")
+	}
+}
+
+func TestOrdersTrackCmd_PackageFlags(t *testing.T) {
+	if ordersTrackCmd.Flags().Lookup("shipment") == nil {
+		t.Error("Expected --shipment flag on orders track")
+	}
+	packages := ordersTrackCmd.Flags().Lookup("packages")
+	if packages == nil || packages.DefValue != "false" {
+		t.Error("Expected --packages flag defaulting to false")
+	}
+}
@@ -1,9 +1,16 @@
 package cmd
 
 import (
+	"fmt"
+	"math"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/zkwentz/amazon-cli/internal/amazon"
 	"github.com/zkwentz/amazon-cli/internal/output"
 	"github.com/zkwentz/amazon-cli/pkg/models"
 )
@@ -11,6 +18,9 @@ import (
 var (
 	returnsReason  string
 	returnsConfirm bool
+	returnsMethod  string
+
+	returnsExpiringWithin string
 )
 
 // returnsCmd represents the returns command
@@ -20,12 +30,116 @@ var returnsCmd = &cobra.Command{
 	Long:  `List returnable items, get return options, and create returns.`,
 }
 
+// returnsListCmd represents the returns list command
+var returnsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List returnable items",
+	Long: `List the items in the returns center whose return window is still open, the
+ones closing soonest first. Each item shows the last day a return can be started
+(return_window, YYYY-MM-DD) and the days left until then.
+
+Use --expiring-within to only list items whose window closes within a number of
+days, e.g. 7d (0d lists the items that must be returned today).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		days := -1
+		if returnsExpiringWithin != "" {
+			var err error
+			if days, err = parseDays(returnsExpiringWithin); err != nil {
+				_ = output.Error(models.ErrInvalidInput, "invalid --expiring-within: "+err.Error(), nil)
+				os.Exit(models.ExitInvalidArgs)
+			}
+		}
+
+		c := getClient()
+
+		items, err := c.GetReturnableItems()
+		if err != nil {
+			_ = output.Error(models.ErrAmazonError, err.Error(), nil)
+			os.Exit(models.ExitGeneralError)
+		}
+		if days >= 0 {
+			items = amazon.ExpiringReturns(items, days)
+		}
+
+		_ = output.NewPrinter(viper.GetString("output"), false).Print(&models.ReturnableItemsResponse{
+			Items:      items,
+			TotalCount: len(items),
+		})
+	},
+}
+
+// parseDays parses a number of days written as "7d", "7" or a duration such as
+// "36h", which is rounded up to whole days
+func parseDays(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil {
+		if days < 0 {
+			return 0, fmt.Errorf("%q must not be negative", value)
+		}
+		return days, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("%q is not a number of days such as 7d", value)
+	}
+	return int(math.Ceil(duration.Hours() / 24)), nil
+}
+
+// returnsOptionsCmd represents the returns options command
+var returnsOptionsCmd = &cobra.Command{
+	Use:   "options <order-id> <item-id>",
+	Short: "List return methods for an item",
+	Long: `List the ways an item can be returned: the method, its fee and drop-off location,
+and whether the refund goes to the original payment method or a gift card and when.
+
+Methods include ups_dropoff, whole_foods, kohls, pickup and mail; pass one to
+'returns create --method' to choose it.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		orderID := args[0]
+		itemID := args[1]
+
+		c := getClient()
+
+		options, err := c.GetReturnOptions(orderID, itemID)
+		if err != nil {
+			exitReturnError(err)
+		}
+
+		_ = output.NewPrinter(viper.GetString("output"), false).Print(&models.ReturnOptionsResponse{
+			OrderID:    orderID,
+			ItemID:     itemID,
+			Options:    options,
+			TotalCount: len(options),
+		})
+	},
+}
+
+// exitReturnError reports a return options or creation error with the matching error code and exits
+func exitReturnError(err error) {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "invalid order ID format"), strings.Contains(msg, "cannot be empty"),
+		strings.Contains(msg, "invalid return reason"), strings.Contains(msg, "is not available for this item"):
+		_ = output.Error(models.ErrInvalidInput, msg, nil)
+		os.Exit(models.ExitInvalidArgs)
+	case strings.Contains(msg, "no return options"):
+		_ = output.Error(models.ErrNotFound, msg, nil)
+		os.Exit(models.ExitNotFound)
+	default:
+		_ = output.Error(models.ErrAmazonError, msg, nil)
+		os.Exit(models.ExitGeneralError)
+	}
+}
+
 // returnsCreateCmd represents the returns create command
 var returnsCreateCmd = &cobra.Command{
 	Use:   "create <order-id> <item-id>",
 	Short: "Create a return",
 	Long: `Create a return request for an order item.
 Requires --reason flag with one of: defective, wrong_item, not_as_described, no_longer_needed, better_price, other.
+Use --method to choose how the item is sent back from the methods listed by 'returns options'.
 Without --confirm, shows a preview of the return. With --confirm, submits the return.`,
 	Args: cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
@@ -54,21 +168,35 @@ Without --confirm, shows a preview of the return. With --confirm, submits the re
 
 		if !returnsConfirm {
 			// Dry run - show preview
-			_ = output.JSON(map[string]interface{}{
+			preview := map[string]interface{}{
 				"dry_run":  true,
 				"order_id": orderID,
 				"item_id":  itemID,
 				"reason":   returnsReason,
 				"message":  "Add --confirm to submit the return",
-			})
+			}
+
+			// Show the chosen method's fee and refund
+			if returnsMethod != "" {
+				options, err := c.GetReturnOptions(orderID, itemID)
+				if err != nil {
+					exitReturnError(err)
+				}
+				option, err := amazon.SelectReturnOption(options, returnsMethod)
+				if err != nil {
+					exitReturnError(err)
+				}
+				preview["method"] = option
+			}
+
+			_ = output.JSON(preview)
 			return
 		}
 
 		// Execute return creation
-		ret, err := c.CreateReturn(orderID, itemID, returnsReason)
+		ret, err := c.CreateReturnWithMethod(orderID, itemID, returnsReason, returnsMethod)
 		if err != nil {
-			_ = output.Error(models.ErrInvalidInput, err.Error(), nil)
-			os.Exit(models.ExitInvalidArgs)
+			exitReturnError(err)
 		}
 
 		_ = output.JSON(ret)
@@ -135,12 +263,18 @@ func init() {
 	rootCmd.AddCommand(returnsCmd)
 
 	// Add subcommands
+	returnsCmd.AddCommand(returnsListCmd)
+	returnsCmd.AddCommand(returnsOptionsCmd)
 	returnsCmd.AddCommand(returnsCreateCmd)
 	returnsCmd.AddCommand(returnsLabelCmd)
 	returnsCmd.AddCommand(returnsStatusCmd)
 
+	// Flags for returns list
+	returnsListCmd.Flags().StringVar(&returnsExpiringWithin, "expiring-within", "", "Only items whose return window closes within this many days (e.g. 7d)")
+
 	// Flags for returns create
 	returnsCreateCmd.Flags().StringVar(&returnsReason, "reason", "", "Return reason (required): defective, wrong_item, not_as_described, no_longer_needed, better_price, other")
 	returnsCreateCmd.Flags().BoolVar(&returnsConfirm, "confirm", false, "Confirm the return creation")
+	returnsCreateCmd.Flags().StringVar(&returnsMethod, "method", "", "Return method from 'returns options', e.g. ups_dropoff, whole_foods, kohls, pickup, mail")
 	_ = returnsCreateCmd.MarkFlagRequired("reason")
 }
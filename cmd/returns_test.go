@@ -86,15 +86,23 @@ func TestReturnsCmd_Subcommands(t *testing.T) {
 	// Test that all subcommands are registered
 	commands := returnsCmd.Commands()
 
-	if len(commands) != 3 {
-		t.Errorf("Expected 3 subcommands, got %d", len(commands))
+	if len(commands) != 5 {
+		t.Errorf("Expected 5 subcommands, got %d", len(commands))
 	}
 
 	// Check that create subcommand exists
+	foundList := false
+	foundOptions := false
 	foundCreate := false
 	foundLabel := false
 	foundStatus := false
 	for _, cmd := range commands {
+		if cmd.Use == "list" {
+			foundList = true
+		}
+		if cmd.Use == "options <order-id> <item-id>" {
+			foundOptions = true
+		}
 		if cmd.Use == "create <order-id> <item-id>" {
 			foundCreate = true
 		}
@@ -105,6 +113,12 @@ func TestReturnsCmd_Subcommands(t *testing.T) {
 			foundStatus = true
 		}
 	}
+	if !foundList {
+		t.Error("Expected 'list' subcommand not found")
+	}
+	if !foundOptions {
+		t.Error("Expected 'options' subcommand not found")
+	}
 	if !foundCreate {
 		t.Error("Expected 'create' subcommand not found")
 	}
@@ -116,6 +130,35 @@ func TestReturnsCmd_Subcommands(t *testing.T) {
 	}
 }
 
+func TestReturnsListCmd_Flags(t *testing.T) {
+	flag := returnsListCmd.Flags().Lookup("expiring-within")
+	if flag == nil || flag.DefValue != "" {
+		t.Error("Expected --expiring-within flag defaulting to empty")
+	}
+}
+
+func TestReturnsCreateCmd_MethodFlag(t *testing.T) {
+	flag := returnsCreateCmd.Flags().Lookup("method")
+	if flag == nil || flag.DefValue != "" {
+		t.Error("Expected --method flag defaulting to empty")
+	}
+}
+
+func TestParseDays(t *testing.T) {
+	tests := map[string]int{"7d": 7, "7": 7, "0d": 0, "36h": 2, "24h": 1}
+	for value, expected := range tests {
+		days, err := parseDays(value)
+		if err != nil || days != expected {
+			t.Errorf("parseDays(%q) = %d, %v; want %d", value, days, err, expected)
+		}
+	}
+	for _, value := range []string{"", "-1d", "week", "-2h"} {
+		if _, err := parseDays(value); err == nil {
+			t.Errorf("parseDays(%q) expected an error", value)
+		}
+	}
+}
+
 func TestReturnsCmd_VariablesInitialized(t *testing.T) {
 	// Test that package-level variables are initialized
 	// Save original values
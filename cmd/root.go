@@ -2,10 +2,17 @@ package cmd
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/zkwentz/amazon-cli/internal/amazon"
+	"github.com/zkwentz/amazon-cli/internal/marketplace"
+	"github.com/zkwentz/amazon-cli/internal/orderindex"
+	"github.com/zkwentz/amazon-cli/internal/ratelimit"
 )
 
 var (
@@ -14,6 +21,9 @@ var (
 	quiet        bool
 	verbose      bool
 	noColor      bool
+	recordDir    string
+	replayDir    string
+	harFile      string
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -55,16 +65,33 @@ func init() {
 	// will be global for your application.
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.amazon-cli/config.json)")
-	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "json", "Output format: json, table, raw")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "json", "Output format: json, table, raw, ndjson, csv")
 	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Suppress non-essential output")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose logging")
 	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	rootCmd.PersistentFlags().StringVar(&recordDir, "record", "", "Record HTTP traffic (scrubbed of cookies and PII) to this directory")
+	rootCmd.PersistentFlags().StringVar(&harFile, "har", "", "Write an HTTP Archive (HAR 1.2) of this command's requests to a file")
+	rootCmd.PersistentFlags().StringVar(&replayDir, "replay", "", "Replay HTTP traffic previously recorded to this directory instead of contacting Amazon")
+	rootCmd.PersistentFlags().String("proxy", "", "Proxy URL (http://, https://, socks5://)")
+	rootCmd.PersistentFlags().String("ca-file", "", "PEM bundle of additional trusted CA certificates")
+	rootCmd.PersistentFlags().Duration("timeout", 30*time.Second, "Per-request HTTP timeout")
+	rootCmd.PersistentFlags().Bool("no-keep-alive", false, "Disable HTTP connection reuse")
+	rootCmd.PersistentFlags().Bool("no-http2", false, "Disable HTTP/2 and use HTTP/1.1 only")
+	rootCmd.PersistentFlags().String("marketplace", marketplace.DefaultCode, "Amazon marketplace: "+strings.Join(marketplace.Codes(), ", "))
+	rootCmd.PersistentFlags().String("profile", orderindex.DefaultProfile, "Profile whose local order index is used by orders sync and --offline")
 
 	// Bind flags to viper
 	_ = viper.BindPFlag("output", rootCmd.PersistentFlags().Lookup("output"))
 	_ = viper.BindPFlag("quiet", rootCmd.PersistentFlags().Lookup("quiet"))
 	_ = viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
 	_ = viper.BindPFlag("no-color", rootCmd.PersistentFlags().Lookup("no-color"))
+	_ = viper.BindPFlag("marketplace", rootCmd.PersistentFlags().Lookup("marketplace"))
+	_ = viper.BindPFlag("profile", rootCmd.PersistentFlags().Lookup("profile"))
+	_ = viper.BindPFlag("network.proxy_url", rootCmd.PersistentFlags().Lookup("proxy"))
+	_ = viper.BindPFlag("network.ca_file", rootCmd.PersistentFlags().Lookup("ca-file"))
+	_ = viper.BindPFlag("network.timeout", rootCmd.PersistentFlags().Lookup("timeout"))
+	_ = viper.BindPFlag("network.disable_keep_alives", rootCmd.PersistentFlags().Lookup("no-keep-alive"))
+	_ = viper.BindPFlag("network.disable_http2", rootCmd.PersistentFlags().Lookup("no-http2"))
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -96,3 +123,87 @@ func initConfig() {
 		}
 	}
 }
+
+// transportConfig builds the client transport settings from flags and the config file.
+// Flags take precedence over the "network" section of the config file.
+func transportConfig() amazon.TransportConfig {
+	return amazon.TransportConfig{
+		ProxyURL:          viper.GetString("network.proxy_url"),
+		CAFile:            viper.GetString("network.ca_file"),
+		Timeout:           viper.GetDuration("network.timeout"),
+		DialTimeout:       viper.GetDuration("network.dial_timeout"),
+		KeepAlive:         viper.GetDuration("network.keep_alive"),
+		DisableKeepAlives: viper.GetBool("network.disable_keep_alives"),
+		DisableHTTP2:      viper.GetBool("network.disable_http2"),
+	}
+}
+
+// clientOptions builds the Amazon client options from flags and the config file
+func clientOptions() ([]amazon.Option, error) {
+	httpClient, err := amazon.NewHTTPClient(transportConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []amazon.Option{
+		amazon.WithHTTPClient(httpClient),
+		amazon.WithProfile(viper.GetString("profile")),
+	}
+
+	// Select the regional storefront before any base URL override
+	if code := viper.GetString("marketplace"); code != "" {
+		m, err := marketplace.Get(code)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, amazon.WithMarketplace(m))
+	}
+
+	if baseURL := viper.GetString("base_url"); baseURL != "" {
+		opts = append(opts, amazon.WithBaseURL(baseURL))
+	}
+
+	// Override the default rate limits if configured
+	if viper.IsSet("rate_limiting.min_delay_ms") {
+		minDelay, maxDelay, maxRetries, err := rateLimitSettings(viper.GetViper())
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, amazon.WithRateLimiter(ratelimit.NewRateLimiter(minDelay, maxDelay, maxRetries)))
+	}
+
+	if verbose {
+		handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})
+		opts = append(opts, amazon.WithLogger(slog.New(handler)))
+	}
+
+	return opts, nil
+}
+
+// rateLimitSettings reads the rate_limiting section of the config. The backoff
+// cap defaults to the rate limiter's own cap (or the minimum delay, if larger) when
+// only min_delay_ms is set, and a cap below the minimum delay is rejected.
+func rateLimitSettings(v *viper.Viper) (minDelay, maxDelay time.Duration, maxRetries int, err error) {
+	minDelay = time.Duration(v.GetInt("rate_limiting.min_delay_ms")) * time.Millisecond
+	maxDelay = ratelimit.DefaultMaxBackoff
+	if minDelay > maxDelay {
+		maxDelay = minDelay
+	}
+	if v.IsSet("rate_limiting.max_delay_ms") {
+		maxDelay = time.Duration(v.GetInt("rate_limiting.max_delay_ms")) * time.Millisecond
+	}
+	maxRetries = 3
+	if v.IsSet("rate_limiting.max_retries") {
+		maxRetries = v.GetInt("rate_limiting.max_retries")
+	}
+
+	if minDelay < 0 || maxRetries < 0 {
+		return 0, 0, 0, fmt.Errorf("rate_limiting.min_delay_ms and rate_limiting.max_retries must not be negative")
+	}
+	if maxDelay < minDelay {
+		return 0, 0, 0, fmt.Errorf("rate_limiting.max_delay_ms (%d) must not be less than rate_limiting.min_delay_ms (%d)",
+			maxDelay.Milliseconds(), minDelay.Milliseconds())
+	}
+
+	return minDelay, maxDelay, maxRetries, nil
+}
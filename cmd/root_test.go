@@ -4,6 +4,10 @@ import (
 	"bytes"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/zkwentz/amazon-cli/internal/ratelimit"
 )
 
 func TestSetVersion(t *testing.T) {
@@ -40,3 +44,41 @@ func TestVersionFlag(t *testing.T) {
 		t.Errorf("Expected output to contain version %s, got: %s", testVersion, output)
 	}
 }
+
+func TestRateLimitSettings(t *testing.T) {
+	tests := []struct {
+		name        string
+		settings    map[string]int
+		wantMin     time.Duration
+		wantMax     time.Duration
+		wantRetries int
+		wantErr     bool
+	}{
+		{"min only", map[string]int{"min_delay_ms": 1500}, 1500 * time.Millisecond, ratelimit.DefaultMaxBackoff, 3, false},
+		{"min above default cap", map[string]int{"min_delay_ms": 90000}, 90 * time.Second, 90 * time.Second, 3, false},
+		{"all set", map[string]int{"min_delay_ms": 1000, "max_delay_ms": 5000, "max_retries": 5}, time.Second, 5 * time.Second, 5, false},
+		{"max below min", map[string]int{"min_delay_ms": 2000, "max_delay_ms": 500}, 0, 0, 0, true},
+		{"negative min", map[string]int{"min_delay_ms": -1}, 0, 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := viper.New()
+			for key, value := range tt.settings {
+				v.Set("rate_limiting."+key, value)
+			}
+
+			minDelay, maxDelay, maxRetries, err := rateLimitSettings(v)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("rateLimitSettings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if minDelay != tt.wantMin || maxDelay != tt.wantMax || maxRetries != tt.wantRetries {
+				t.Errorf("rateLimitSettings() = %v, %v, %d; want %v, %v, %d",
+					minDelay, maxDelay, maxRetries, tt.wantMin, tt.wantMax, tt.wantRetries)
+			}
+		})
+	}
+}
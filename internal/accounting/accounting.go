@@ -0,0 +1,281 @@
+package accounting
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zkwentz/amazon-cli/internal/marketplace"
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+// Default accounts used when the configuration leaves them empty
+const (
+	DefaultPayee             = "Amazon"
+	DefaultPaymentAccount    = "Liabilities:CreditCard"
+	DefaultExpenseAccount    = "Expenses:Shopping:Amazon"
+	DefaultTaxAccount        = "Expenses:Taxes:Sales"
+	DefaultShippingAccount   = "Expenses:Shipping"
+	DefaultPromotionsAccount = "Income:Amazon:Promotions"
+	DefaultGiftCardAccount   = "Assets:Amazon:GiftCard"
+)
+
+// Rule maps order items to an account. Category, Seller and ASIN are case-insensitive
+// regular expressions; every pattern that is set must match the item.
+type Rule struct {
+	Category string `json:"category,omitempty"`
+	Seller   string `json:"seller,omitempty"`
+	ASIN     string `json:"asin,omitempty"`
+	Account  string `json:"account"`
+}
+
+// Config holds the account mappings for exports
+type Config struct {
+	Payee             string `json:"payee,omitempty"`
+	PaymentAccount    string `json:"payment_account,omitempty"`
+	DefaultAccount    string `json:"default_account,omitempty"`
+	TaxAccount        string `json:"tax_account,omitempty"`
+	ShippingAccount   string `json:"shipping_account,omitempty"`
+	PromotionsAccount string `json:"promotions_account,omitempty"`
+	GiftCardAccount   string `json:"gift_card_account,omitempty"`
+	Rules             []Rule `json:"rules,omitempty"`
+}
+
+// Posting is one leg of a transaction; the postings of a transaction sum to zero
+type Posting struct {
+	Account string
+	Amount  float64
+	Memo    string
+}
+
+// Transaction is an order's purchase or refund ready to be written in an accounting format
+type Transaction struct {
+	Date      time.Time
+	OrderID   string
+	Payee     string
+	Narration string
+	Currency  string
+	Refund    bool
+	Postings  []Posting
+}
+
+// Amount returns the amount charged to the payment account: negative for purchases,
+// positive for refunds
+func (t Transaction) Amount() float64 {
+	if len(t.Postings) == 0 {
+		return 0
+	}
+	return t.Postings[len(t.Postings)-1].Amount
+}
+
+// compiledRule is a Rule with its patterns compiled
+type compiledRule struct {
+	category *regexp.Regexp
+	seller   *regexp.Regexp
+	asin     *regexp.Regexp
+	account  string
+}
+
+// Exporter converts orders to transactions using the configured account mappings
+type Exporter struct {
+	config Config
+	rules  []compiledRule
+}
+
+// NewExporter validates the configuration, fills in default accounts and compiles the rules
+func NewExporter(config Config) (*Exporter, error) {
+	setDefault(&config.Payee, DefaultPayee)
+	setDefault(&config.PaymentAccount, DefaultPaymentAccount)
+	setDefault(&config.DefaultAccount, DefaultExpenseAccount)
+	setDefault(&config.TaxAccount, DefaultTaxAccount)
+	setDefault(&config.ShippingAccount, DefaultShippingAccount)
+	setDefault(&config.PromotionsAccount, DefaultPromotionsAccount)
+	setDefault(&config.GiftCardAccount, DefaultGiftCardAccount)
+
+	e := &Exporter{config: config}
+	for i, rule := range config.Rules {
+		if rule.Account == "" {
+			return nil, fmt.Errorf("account rule %d: account is required", i+1)
+		}
+		if rule.Category == "" && rule.Seller == "" && rule.ASIN == "" {
+			return nil, fmt.Errorf("account rule %d: set at least one of category, seller or asin", i+1)
+		}
+
+		compiled := compiledRule{account: rule.Account}
+		var err error
+		if compiled.category, err = compilePattern(rule.Category); err != nil {
+			return nil, fmt.Errorf("account rule %d: invalid category pattern: %w", i+1, err)
+		}
+		if compiled.seller, err = compilePattern(rule.Seller); err != nil {
+			return nil, fmt.Errorf("account rule %d: invalid seller pattern: %w", i+1, err)
+		}
+		if compiled.asin, err = compilePattern(rule.ASIN); err != nil {
+			return nil, fmt.Errorf("account rule %d: invalid asin pattern: %w", i+1, err)
+		}
+		e.rules = append(e.rules, compiled)
+	}
+
+	return e, nil
+}
+
+// Account returns the account of the first rule matching the item, or the default account
+func (e *Exporter) Account(item models.OrderItem) string {
+	for _, rule := range e.rules {
+		if matchPattern(rule.category, item.Category) &&
+			matchPattern(rule.seller, item.Seller) &&
+			matchPattern(rule.asin, item.ASIN) {
+			return rule.account
+		}
+	}
+	return e.config.DefaultAccount
+}
+
+// Transactions converts orders to transactions in date order. Items are posted to
+// their mapped accounts, tax, shipping, promotions and gift card to their own accounts,
+// and the total to the payment account. Any amount the items don't account for is
+// posted to the default account. Every order is posted as a purchase; an order with
+// a known refund amount is followed by a refund transaction on the same date, because
+// Amazon does not report when the refund was issued. Cancelled orders and orders
+// without a total are skipped. Dates are parsed in the given marketplace's format
+// (nil means US).
+func (e *Exporter) Transactions(orders []models.Order, m *marketplace.Marketplace) ([]Transaction, error) {
+	if m == nil {
+		m = marketplace.Default()
+	}
+
+	transactions := []Transaction{}
+	for _, order := range orders {
+		if order.Status == models.OrderStatusCancelled || order.Total == 0 {
+			continue
+		}
+
+		date, err := m.ParseDate(order.Date)
+		if err != nil {
+			return nil, fmt.Errorf("order %s: %w", order.OrderID, err)
+		}
+
+		purchase := e.transaction(order, date, m)
+		transactions = append(transactions, purchase)
+		if refund := order.RefundAmount(); refund > 0 {
+			transactions = append(transactions, e.refund(purchase, refund))
+		}
+	}
+
+	sort.SliceStable(transactions, func(i, j int) bool {
+		if !transactions[i].Date.Equal(transactions[j].Date) {
+			return transactions[i].Date.Before(transactions[j].Date)
+		}
+		return transactions[i].OrderID < transactions[j].OrderID
+	})
+
+	return transactions, nil
+}
+
+// transaction builds the balanced transaction of one order
+func (e *Exporter) transaction(order models.Order, date time.Time, m *marketplace.Marketplace) Transaction {
+	t := Transaction{
+		Date:      date,
+		OrderID:   order.OrderID,
+		Payee:     e.config.Payee,
+		Narration: "Order " + order.OrderID,
+		Currency:  order.Currency,
+	}
+	if t.Currency == "" {
+		t.Currency = m.Currency
+	}
+
+	// Post each item to its mapped account
+	itemized := 0.0
+	for _, item := range order.Items {
+		if item.Price == 0 {
+			continue
+		}
+		quantity := item.Quantity
+		if quantity == 0 {
+			quantity = 1
+		}
+		amount := round(item.Price * float64(quantity))
+		t.Postings = append(t.Postings, Posting{Account: e.Account(item), Amount: amount, Memo: item.Title})
+		itemized += amount
+	}
+
+	// Split the charges into their own postings
+	var tax, shipping, promotions, giftCard float64
+	if order.Charges != nil {
+		tax, shipping = order.Charges.Tax, order.Charges.Shipping
+		promotions, giftCard = order.Charges.Promotions, order.Charges.GiftCard
+	}
+	charges := []Posting{
+		{Account: e.config.ShippingAccount, Amount: round(shipping), Memo: "Shipping"},
+		{Account: e.config.TaxAccount, Amount: round(tax), Memo: "Tax"},
+		{Account: e.config.PromotionsAccount, Amount: -round(promotions), Memo: "Promotions"},
+		{Account: e.config.GiftCardAccount, Amount: -round(giftCard), Memo: "Gift card"},
+	}
+
+	// Post whatever the items and charges don't cover so the transaction balances
+	remainder := round(order.Total + giftCard + promotions - tax - shipping - itemized)
+	if remainder != 0 {
+		t.Postings = append(t.Postings, Posting{Account: e.config.DefaultAccount, Amount: remainder, Memo: "Unitemized"})
+	}
+	for _, posting := range charges {
+		if posting.Amount != 0 {
+			t.Postings = append(t.Postings, posting)
+		}
+	}
+	t.Postings = append(t.Postings, Posting{Account: e.config.PaymentAccount, Amount: -round(order.Total)})
+
+	return t
+}
+
+// refund builds the transaction returning amount to the payment account. A full
+// refund reverses every posting of the purchase; a partial refund can't be matched
+// to items, so it is credited to the default account.
+func (e *Exporter) refund(purchase Transaction, amount float64) Transaction {
+	t := purchase
+	t.Narration = "Refund for order " + purchase.OrderID
+	t.Refund = true
+
+	amount = round(amount)
+	if amount >= -purchase.Amount() {
+		t.Postings = make([]Posting, len(purchase.Postings))
+		for i, posting := range purchase.Postings {
+			posting.Amount = -posting.Amount
+			t.Postings[i] = posting
+		}
+		return t
+	}
+
+	t.Postings = []Posting{
+		{Account: e.config.DefaultAccount, Amount: -amount, Memo: "Partial refund"},
+		{Account: e.config.PaymentAccount, Amount: amount},
+	}
+	return t
+}
+
+// setDefault sets an empty string to its default value
+func setDefault(value *string, def string) {
+	if strings.TrimSpace(*value) == "" {
+		*value = def
+	}
+}
+
+// compilePattern compiles a case-insensitive pattern; empty patterns match anything
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	return regexp.Compile("(?i)" + pattern)
+}
+
+// matchPattern reports whether a compiled pattern matches the value; nil matches anything
+func matchPattern(pattern *regexp.Regexp, value string) bool {
+	return pattern == nil || pattern.MatchString(value)
+}
+
+// round rounds an amount to cents
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,196 @@
+package accounting
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+// sampleOrder is a $54.02 order of two items with shipping, tax and a promotion
+func sampleOrder() models.Order {
+	return models.Order{
+		OrderID: "123-4567890-1234567",
+		Date:    "January 15, 2024",
+		Total:   54.02,
+		Status:  models.OrderStatusDelivered,
+		Items: []models.OrderItem{
+			{ASIN: "B08N5WRWNW", Title: "Echo Dot", Quantity: 1, Price: 24.99, Category: "Electronics"},
+			{ASIN: "B000FILTER", Title: "Water Filter", Quantity: 2, Price: 12.50, Seller: "Acme Supplies"},
+		},
+		Charges: &models.OrderCharges{Subtotal: 49.99, Shipping: 5.99, Tax: 3.04, Promotions: 5.00, Total: 54.02},
+	}
+}
+
+func TestNewExporter_Validation(t *testing.T) {
+	if _, err := NewExporter(Config{Rules: []Rule{{ASIN: "B0"}}}); err == nil {
+		t.Error("Expected error for rule without account")
+	}
+	if _, err := NewExporter(Config{Rules: []Rule{{Account: "Expenses:Misc"}}}); err == nil {
+		t.Error("Expected error for rule without patterns")
+	}
+	if _, err := NewExporter(Config{Rules: []Rule{{Seller: "(", Account: "Expenses:Misc"}}}); err == nil {
+		t.Error("Expected error for invalid pattern")
+	}
+}
+
+func TestExporter_Account(t *testing.T) {
+	exporter, err := NewExporter(Config{
+		DefaultAccount: "Expenses:Misc",
+		Rules: []Rule{
+			{Category: "^electronics$", Account: "Expenses:Electronics"},
+			{Seller: "acme", Account: "Expenses:Household"},
+			{ASIN: "^B0BOOK", Account: "Expenses:Books"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+
+	tests := []struct {
+		item     models.OrderItem
+		expected string
+	}{
+		{models.OrderItem{Category: "Electronics"}, "Expenses:Electronics"},
+		{models.OrderItem{Seller: "ACME Supplies"}, "Expenses:Household"},
+		{models.OrderItem{ASIN: "B0BOOK1234"}, "Expenses:Books"},
+		{models.OrderItem{ASIN: "B08N5WRWNW", Seller: "Amazon.com"}, "Expenses:Misc"},
+	}
+	for _, tt := range tests {
+		if got := exporter.Account(tt.item); got != tt.expected {
+			t.Errorf("Account(%+v) = %s, expected %s", tt.item, got, tt.expected)
+		}
+	}
+}
+
+func TestExporter_TransactionsSplitCharges(t *testing.T) {
+	exporter, _ := NewExporter(Config{Rules: []Rule{{Category: "electronics", Account: "Expenses:Electronics"}}})
+
+	transactions, err := exporter.Transactions([]models.Order{sampleOrder()}, nil)
+	if err != nil {
+		t.Fatalf("Transactions() error = %v", err)
+	}
+	if len(transactions) != 1 {
+		t.Fatalf("Expected 1 transaction, got %d", len(transactions))
+	}
+
+	tx := transactions[0]
+	if tx.Date.Format("2006-01-02") != "2024-01-15" || tx.Currency != "USD" {
+		t.Errorf("Unexpected date or currency: %v %s", tx.Date, tx.Currency)
+	}
+
+	expected := []Posting{
+		{Account: "Expenses:Electronics", Amount: 24.99, Memo: "Echo Dot"},
+		{Account: DefaultExpenseAccount, Amount: 25.00, Memo: "Water Filter"},
+		{Account: DefaultShippingAccount, Amount: 5.99, Memo: "Shipping"},
+		{Account: DefaultTaxAccount, Amount: 3.04, Memo: "Tax"},
+		{Account: DefaultPromotionsAccount, Amount: -5.00, Memo: "Promotions"},
+		{Account: DefaultPaymentAccount, Amount: -54.02},
+	}
+	if len(tx.Postings) != len(expected) {
+		t.Fatalf("Expected %d postings, got %+v", len(expected), tx.Postings)
+	}
+	for i, posting := range expected {
+		if tx.Postings[i] != posting {
+			t.Errorf("Posting %d: expected %+v, got %+v", i, posting, tx.Postings[i])
+		}
+	}
+	assertBalanced(t, tx)
+}
+
+func TestExporter_TransactionsRefundsAndRemainder(t *testing.T) {
+	exporter, _ := NewExporter(Config{})
+
+	refunded := sampleOrder()
+	refunded.Status = models.OrderStatusRefunded
+	refunded.Date = "2024-02-01"
+	refunded.Charges.Refund = 54.02
+
+	// Without items or charges, the whole total goes to the default account
+	unitemized := models.Order{OrderID: "111-0000000-0000001", Date: "2024-01-01", Total: 10, Status: models.OrderStatusDelivered}
+	cancelled := models.Order{OrderID: "111-0000000-0000002", Date: "2024-01-02", Total: 15, Status: models.OrderStatusCancelled}
+
+	transactions, err := exporter.Transactions([]models.Order{refunded, unitemized, cancelled}, nil)
+	if err != nil {
+		t.Fatalf("Transactions() error = %v", err)
+	}
+	if len(transactions) != 3 {
+		t.Fatalf("Expected unitemized, purchase and refund transactions, got %d", len(transactions))
+	}
+
+	// Transactions are in date order
+	if transactions[0].OrderID != unitemized.OrderID {
+		t.Errorf("Expected oldest transaction first, got %s", transactions[0].OrderID)
+	}
+	if len(transactions[0].Postings) != 2 || transactions[0].Postings[0].Account != DefaultExpenseAccount || transactions[0].Postings[0].Amount != 10 {
+		t.Errorf("Expected a single unitemized expense posting, got %+v", transactions[0].Postings)
+	}
+
+	// The refunded order is still posted as a purchase, followed by its reversal
+	purchase, refund := transactions[1], transactions[2]
+	if purchase.Refund || purchase.Amount() != -54.02 {
+		t.Errorf("Expected the original purchase of 54.02, got %+v", purchase)
+	}
+	if !refund.Refund || refund.Amount() != 54.02 || !refund.Date.Equal(purchase.Date) {
+		t.Errorf("Expected a positive refund of 54.02, got %+v", refund)
+	}
+	if refund.Postings[0].Amount != -24.99 || purchase.Postings[0].Amount != 24.99 {
+		t.Errorf("Expected negated item postings, got %+v", refund.Postings[0])
+	}
+	assertBalanced(t, purchase)
+	assertBalanced(t, refund)
+
+	if _, err := exporter.Transactions([]models.Order{{OrderID: "1", Date: "someday", Total: 1}}, nil); err == nil {
+		t.Error("Expected error for unparseable date")
+	}
+}
+
+func TestExporter_TransactionsPartialRefund(t *testing.T) {
+	exporter, _ := NewExporter(Config{})
+
+	partial := sampleOrder()
+	partial.Status = models.OrderStatusReturned
+	partial.Charges.Refund = 24.99
+
+	// A return without a known refund amount is only a purchase
+	pending := sampleOrder()
+	pending.OrderID = "123-9999999-0000009"
+	pending.Status = models.OrderStatusReturned
+
+	transactions, err := exporter.Transactions([]models.Order{partial, pending}, nil)
+	if err != nil {
+		t.Fatalf("Transactions() error = %v", err)
+	}
+	if len(transactions) != 3 {
+		t.Fatalf("Expected two purchases and one refund, got %+v", transactions)
+	}
+
+	refund := transactions[1]
+	expected := []Posting{
+		{Account: DefaultExpenseAccount, Amount: -24.99, Memo: "Partial refund"},
+		{Account: DefaultPaymentAccount, Amount: 24.99},
+	}
+	if !refund.Refund || len(refund.Postings) != len(expected) {
+		t.Fatalf("Expected a partial refund transaction, got %+v", refund)
+	}
+	for i, posting := range expected {
+		if refund.Postings[i] != posting {
+			t.Errorf("Posting %d: expected %+v, got %+v", i, posting, refund.Postings[i])
+		}
+	}
+	assertBalanced(t, refund)
+	if transactions[2].OrderID != pending.OrderID || transactions[2].Refund {
+		t.Errorf("Expected only a purchase for the return without refund amount, got %+v", transactions[2])
+	}
+}
+
+func assertBalanced(t *testing.T, tx Transaction) {
+	t.Helper()
+	sum := 0.0
+	for _, posting := range tx.Postings {
+		sum += posting.Amount
+	}
+	if math.Abs(sum) > 0.001 {
+		t.Errorf("Expected balanced transaction, postings sum to %.2f", sum)
+	}
+}
@@ -0,0 +1,201 @@
+package accounting
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Export formats
+const (
+	FormatBeancount = "beancount"
+	FormatLedger    = "ledger"
+	FormatHledger   = "hledger"
+	FormatOFX       = "ofx"
+	FormatQIF       = "qif"
+)
+
+// Formats returns the supported export formats
+func Formats() []string {
+	return []string{FormatBeancount, FormatLedger, FormatHledger, FormatOFX, FormatQIF}
+}
+
+// ValidateFormat checks that a format is supported
+func ValidateFormat(format string) error {
+	for _, f := range Formats() {
+		if format == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported export format %q: use %s", format, strings.Join(Formats(), ", "))
+}
+
+// Write writes the transactions in the given format
+func Write(w io.Writer, format string, transactions []Transaction) error {
+	bw := bufio.NewWriter(w)
+
+	switch format {
+	case FormatBeancount:
+		writeBeancount(bw, transactions)
+	case FormatLedger:
+		writeLedger(bw, transactions)
+	case FormatHledger:
+		writeHledger(bw, transactions)
+	case FormatOFX:
+		writeOFX(bw, transactions)
+	case FormatQIF:
+		writeQIF(bw, transactions)
+	default:
+		return ValidateFormat(format)
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write %s export: %w", format, err)
+	}
+	return nil
+}
+
+// writeBeancount writes transactions as beancount entries with the order ID as metadata
+func writeBeancount(w *bufio.Writer, transactions []Transaction) {
+	for i, t := range transactions {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s * %s %s\n", t.Date.Format("2006-01-02"), quote(t.Payee), quote(t.Narration))
+		fmt.Fprintf(w, "  order_id: %s\n", quote(t.OrderID))
+		for _, p := range t.Postings {
+			fmt.Fprintf(w, "  %-40s %s %s%s\n", p.Account, formatAmount(p.Amount), t.Currency, comment(p.Memo))
+		}
+	}
+}
+
+// writeLedger writes transactions as ledger-cli entries with the order ID as a metadata tag
+func writeLedger(w *bufio.Writer, transactions []Transaction) {
+	for i, t := range transactions {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s * %s\n", t.Date.Format("2006/01/02"), t.Payee)
+		fmt.Fprintf(w, "    ; %s\n", t.Narration)
+		fmt.Fprintf(w, "    ; order_id: %s\n", t.OrderID)
+		for _, p := range t.Postings {
+			fmt.Fprintf(w, "    %-40s  %s %s%s\n", p.Account, formatAmount(p.Amount), t.Currency, comment(p.Memo))
+		}
+	}
+}
+
+// writeHledger writes transactions as hledger entries with payee|note descriptions and order_id tags
+func writeHledger(w *bufio.Writer, transactions []Transaction) {
+	for i, t := range transactions {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s * %s | %s  ; order_id:%s\n", t.Date.Format("2006-01-02"), t.Payee, t.Narration, t.OrderID)
+		for _, p := range t.Postings {
+			fmt.Fprintf(w, "    %-40s  %s %s%s\n", p.Account, formatAmount(p.Amount), t.Currency, comment(p.Memo))
+		}
+	}
+}
+
+// writeOFX writes transactions as an OFX 2.2 credit card statement. OFX carries one
+// amount per transaction, so only the charged total of each order is included.
+func writeOFX(w *bufio.Writer, transactions []Transaction) {
+	currency := "USD"
+	start, end := "", ""
+	if len(transactions) > 0 {
+		currency = transactions[0].Currency
+		start = transactions[0].Date.Format("20060102")
+		end = transactions[len(transactions)-1].Date.Format("20060102")
+	}
+
+	fmt.Fprintln(w, `<?xml version="1.0" encoding="UTF-8" standalone="no"?>`)
+	fmt.Fprintln(w, `<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>`)
+	fmt.Fprintln(w, "<OFX>")
+	fmt.Fprintln(w, "  <SIGNONMSGSRSV1><SONRS>")
+	fmt.Fprintln(w, "    <STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>")
+	fmt.Fprintf(w, "    <DTSERVER>%s</DTSERVER><LANGUAGE>ENG</LANGUAGE>\n", end)
+	fmt.Fprintln(w, "  </SONRS></SIGNONMSGSRSV1>")
+	fmt.Fprintln(w, "  <CREDITCARDMSGSRSV1><CCSTMTTRNRS>")
+	fmt.Fprintln(w, "    <TRNUID>0</TRNUID>")
+	fmt.Fprintln(w, "    <STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>")
+	fmt.Fprintln(w, "    <CCSTMTRS>")
+	fmt.Fprintf(w, "      <CURDEF>%s</CURDEF>\n", escapeXML(currency))
+	fmt.Fprintln(w, "      <CCACCTFROM><ACCTID>amazon</ACCTID></CCACCTFROM>")
+	fmt.Fprintf(w, "      <BANKTRANLIST><DTSTART>%s</DTSTART><DTEND>%s</DTEND>\n", start, end)
+	for _, t := range transactions {
+		trnType, fitID := "DEBIT", t.OrderID
+		if t.Refund {
+			trnType, fitID = "CREDIT", t.OrderID+"-refund"
+		}
+		fmt.Fprintln(w, "        <STMTTRN>")
+		fmt.Fprintf(w, "          <TRNTYPE>%s</TRNTYPE>\n", trnType)
+		fmt.Fprintf(w, "          <DTPOSTED>%s</DTPOSTED>\n", t.Date.Format("20060102"))
+		fmt.Fprintf(w, "          <TRNAMT>%s</TRNAMT>\n", formatAmount(t.Amount()))
+		fmt.Fprintf(w, "          <FITID>%s</FITID>\n", escapeXML(fitID))
+		fmt.Fprintf(w, "          <NAME>%s</NAME>\n", escapeXML(t.Payee))
+		fmt.Fprintf(w, "          <MEMO>%s</MEMO>\n", escapeXML(t.Narration))
+		fmt.Fprintln(w, "        </STMTTRN>")
+	}
+	fmt.Fprintln(w, "      </BANKTRANLIST>")
+	fmt.Fprintf(w, "      <LEDGERBAL><BALAMT>0.00</BALAMT><DTASOF>%s</DTASOF></LEDGERBAL>\n", end)
+	fmt.Fprintln(w, "    </CCSTMTRS>")
+	fmt.Fprintln(w, "  </CCSTMTTRNRS></CREDITCARDMSGSRSV1>")
+	fmt.Fprintln(w, "</OFX>")
+}
+
+// writeQIF writes transactions as QIF credit card entries with one split per posting.
+// Split amounts use the QIF sign convention: expenses are negative.
+func writeQIF(w *bufio.Writer, transactions []Transaction) {
+	fmt.Fprintln(w, "!Type:CCard")
+	for _, t := range transactions {
+		fmt.Fprintf(w, "D%s\n", t.Date.Format("01/02/2006"))
+		fmt.Fprintf(w, "T%s\n", formatAmount(t.Amount()))
+		fmt.Fprintf(w, "N%s\n", t.OrderID)
+		fmt.Fprintf(w, "P%s\n", t.Payee)
+		fmt.Fprintf(w, "M%s\n", t.Narration)
+		for _, p := range t.Postings[:len(t.Postings)-1] {
+			fmt.Fprintf(w, "S%s\n", p.Account)
+			if p.Memo != "" {
+				fmt.Fprintf(w, "E%s\n", oneLine(p.Memo))
+			}
+			fmt.Fprintf(w, "$%s\n", formatAmount(-p.Amount))
+		}
+		fmt.Fprintln(w, "^")
+	}
+}
+
+// formatAmount formats an amount with two decimals
+func formatAmount(amount float64) string {
+	formatted := fmt.Sprintf("%.2f", amount)
+	if formatted == "-0.00" {
+		return "0.00"
+	}
+	return formatted
+}
+
+// comment formats a memo as a trailing comment
+func comment(memo string) string {
+	if memo == "" {
+		return ""
+	}
+	return "  ; " + oneLine(memo)
+}
+
+// quote formats a beancount string literal
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(oneLine(s)) + `"`
+}
+
+// oneLine collapses whitespace so text stays on a single line
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// escapeXML escapes text for an XML element
+func escapeXML(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,91 @@
+package accounting
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+func sampleTransactions(t *testing.T) []Transaction {
+	t.Helper()
+	exporter, _ := NewExporter(Config{Rules: []Rule{{Category: "electronics", Account: "Expenses:Electronics"}}})
+
+	refunded := sampleOrder()
+	refunded.OrderID = "123-0000000-0000002"
+	refunded.Date = "2024-02-01"
+	refunded.Status = models.OrderStatusReturned
+	refunded.Charges.Refund = 54.02
+
+	transactions, err := exporter.Transactions([]models.Order{sampleOrder(), refunded}, nil)
+	if err != nil {
+		t.Fatalf("Transactions() error = %v", err)
+	}
+	return transactions
+}
+
+func TestWrite_Formats(t *testing.T) {
+	transactions := sampleTransactions(t)
+
+	tests := map[string][]string{
+		FormatBeancount: {
+			`2024-01-15 * "Amazon" "Order 123-4567890-1234567"`,
+			`  order_id: "123-4567890-1234567"`,
+			"Expenses:Electronics                     24.99 USD  ; Echo Dot",
+			"Liabilities:CreditCard                   -54.02 USD",
+			`2024-02-01 * "Amazon" "Refund for order 123-0000000-0000002"`,
+			"Liabilities:CreditCard                   54.02 USD",
+		},
+		FormatLedger: {
+			"2024/01/15 * Amazon",
+			"    ; order_id: 123-4567890-1234567",
+			"    Expenses:Taxes:Sales                      3.04 USD  ; Tax",
+		},
+		FormatHledger: {
+			"2024-01-15 * Amazon | Order 123-4567890-1234567  ; order_id:123-4567890-1234567",
+			"    Income:Amazon:Promotions                  -5.00 USD  ; Promotions",
+		},
+		FormatOFX: {
+			"<TRNTYPE>DEBIT</TRNTYPE>",
+			"<TRNAMT>-54.02</TRNAMT>",
+			"<FITID>123-4567890-1234567</FITID>",
+			"<TRNTYPE>CREDIT</TRNTYPE>",
+			"<FITID>123-0000000-0000002</FITID>",
+			"<FITID>123-0000000-0000002-refund</FITID>",
+			"<DTSTART>20240115</DTSTART><DTEND>20240201</DTEND>",
+		},
+		FormatQIF: {
+			"!Type:CCard\nD01/15/2024\nT-54.02\nN123-4567890-1234567\nPAmazon\n",
+			"SExpenses:Electronics\nEEcho Dot\n$-24.99\n",
+			"SExpenses:Shipping\nEShipping\n$-5.99\n",
+			"SIncome:Amazon:Promotions\nEPromotions\n$5.00\n^\n",
+			"D02/01/2024\nT54.02\n",
+		},
+	}
+
+	for format, expected := range tests {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, format, transactions); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			for _, fragment := range expected {
+				if !strings.Contains(buf.String(), fragment) {
+					t.Errorf("Expected output to contain %q, got:\n%s", fragment, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestWrite_UnsupportedFormat(t *testing.T) {
+	if err := Write(&bytes.Buffer{}, "csv", nil); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+	for _, format := range Formats() {
+		if err := ValidateFormat(format); err != nil {
+			t.Errorf("Expected %s to be valid: %v", format, err)
+		}
+	}
+}
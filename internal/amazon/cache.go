@@ -0,0 +1,68 @@
+package amazon
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache stores response bodies keyed by request URL
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte)
+}
+
+// memoryCacheEntry is a cached value with its expiry time
+type memoryCacheEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// MemoryCache is an in-process Cache whose entries expire after a fixed TTL
+type MemoryCache struct {
+	ttl     time.Duration
+	clock   Clock
+	entries map[string]memoryCacheEntry
+	mu      sync.Mutex
+}
+
+// NewMemoryCache creates a MemoryCache with the given TTL.
+// If clock is nil, the system clock is used.
+func NewMemoryCache(ttl time.Duration, clock Clock) *MemoryCache {
+	if clock == nil {
+		clock = systemClock{}
+	}
+	return &MemoryCache{
+		ttl:     ttl,
+		clock:   clock,
+		entries: make(map[string]memoryCacheEntry),
+	}
+}
+
+// Get returns the cached value for key if present and not expired
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, exists := m.entries[key]
+	if !exists {
+		return nil, false
+	}
+
+	if !m.clock.Now().Before(entry.expiresAt) {
+		delete(m.entries, key)
+		return nil, false
+	}
+
+	return entry.value, true
+}
+
+// Set stores value under key until the TTL elapses
+func (m *MemoryCache) Set(key string, value []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries[key] = memoryCacheEntry{
+		value:     value,
+		expiresAt: m.clock.Now().Add(m.ttl),
+	}
+}
@@ -0,0 +1,37 @@
+package amazon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryCache_GetSet(t *testing.T) {
+	cache := NewMemoryCache(time.Minute, nil)
+
+	if _, ok := cache.Get("missing"); ok {
+		t.Error("expected miss for unknown key")
+	}
+
+	cache.Set("key", []byte("value"))
+	value, ok := cache.Get("key")
+	if !ok || string(value) != "value" {
+		t.Errorf("expected cached value, got %q (ok=%v)", value, ok)
+	}
+}
+
+func TestMemoryCache_Expiry(t *testing.T) {
+	clock := &fixedClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	cache := NewMemoryCache(time.Minute, clock)
+
+	cache.Set("key", []byte("value"))
+
+	clock.now = clock.now.Add(59 * time.Second)
+	if _, ok := cache.Get("key"); !ok {
+		t.Error("expected entry to be valid before TTL elapses")
+	}
+
+	clock.now = clock.now.Add(time.Second)
+	if _, ok := cache.Get("key"); ok {
+		t.Error("expected entry to expire after TTL")
+	}
+}
@@ -0,0 +1,326 @@
+package amazon
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+// cancelPagePath is the order edit page that lists cancellable items
+const cancelPagePath = "/gp/your-account/order-edit.html"
+
+// cancelForm is the parsed cancellation form of an order
+type cancelForm struct {
+	action string
+	fields url.Values
+	items  []cancelFormItem
+}
+
+// cancelFormItem is an item row of the cancellation form and the checkbox that selects it
+type cancelFormItem struct {
+	item  models.CancellableItem
+	field string
+	value string
+}
+
+// GetCancellableItems lists an order's items and whether each can still be cancelled
+func (c *Client) GetCancellableItems(orderID string) (*models.OrderCancellation, error) {
+	form, err := c.fetchCancelForm(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	cancellation := &models.OrderCancellation{
+		OrderID: orderID,
+		Items:   make([]models.CancellableItem, len(form.items)),
+	}
+	for i, formItem := range form.items {
+		cancellation.Items[i] = formItem.item
+	}
+
+	return cancellation, nil
+}
+
+// CancelOrder cancels the items with the given ASINs, or every cancellable item when
+// none are given. It fails without submitting anything if a requested item is not in
+// the order or can no longer be cancelled.
+func (c *Client) CancelOrder(orderID string, asins []string) (*models.CancelResult, error) {
+	form, err := c.fetchCancelForm(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	selected, err := selectCancelItems(orderID, form.items, asins)
+	if err != nil {
+		return nil, err
+	}
+
+	// Submit the form with the selected items checked
+	values := url.Values{}
+	for name, vals := range form.fields {
+		values[name] = append([]string(nil), vals...)
+	}
+	selectedASINs := make(map[string]bool)
+	for _, formItem := range selected {
+		values.Add(formItem.field, formItem.value)
+		selectedASINs[formItem.item.ASIN] = true
+	}
+
+	req, err := http.NewRequest(http.MethodPost, form.action, strings.NewReader(values.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	body, err := c.fetchCancelPage(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit cancellation: %w", err)
+	}
+
+	status, message, err := parseCancelConfirmation(body)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.CancelResult{
+		OrderID:   orderID,
+		Status:    status,
+		Cancelled: []models.CancellableItem{},
+		Remaining: []models.CancellableItem{},
+		Message:   message,
+	}
+	for _, formItem := range form.items {
+		if selectedASINs[formItem.item.ASIN] {
+			item := formItem.item
+			item.Cancellable = false
+			result.Cancelled = append(result.Cancelled, item)
+		} else {
+			result.Remaining = append(result.Remaining, formItem.item)
+		}
+	}
+
+	return result, nil
+}
+
+// SelectCancellableItems picks the items of a cancellation preview that CancelOrder
+// would cancel for the given ASINs, failing the same way it does
+func SelectCancellableItems(cancellation *models.OrderCancellation, asins []string) ([]models.CancellableItem, error) {
+	items := make([]cancelFormItem, len(cancellation.Items))
+	for i, item := range cancellation.Items {
+		items[i] = cancelFormItem{item: item}
+	}
+
+	selected, err := selectCancelItems(cancellation.OrderID, items, asins)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]models.CancellableItem, len(selected))
+	for i, formItem := range selected {
+		result[i] = formItem.item
+	}
+	return result, nil
+}
+
+// selectCancelItems picks the form items to cancel: the requested ASINs, or every
+// cancellable item when none are requested
+func selectCancelItems(orderID string, items []cancelFormItem, asins []string) ([]cancelFormItem, error) {
+	if len(asins) == 0 {
+		var selected []cancelFormItem
+		for _, formItem := range items {
+			if formItem.item.Cancellable {
+				selected = append(selected, formItem)
+			}
+		}
+		if len(selected) == 0 {
+			return nil, fmt.Errorf("order %s has no items that can still be cancelled", orderID)
+		}
+		return selected, nil
+	}
+
+	var selected []cancelFormItem
+	seen := make(map[string]bool)
+	for _, asin := range asins {
+		asin = strings.ToUpper(strings.TrimSpace(asin))
+		if seen[asin] {
+			continue
+		}
+		seen[asin] = true
+
+		found := false
+		for _, formItem := range items {
+			if formItem.item.ASIN != asin {
+				continue
+			}
+			found = true
+			if !formItem.item.Cancellable {
+				reason := formItem.item.Reason
+				if reason == "" {
+					reason = "not eligible for cancellation"
+				}
+				return nil, fmt.Errorf("item %s can no longer be cancelled: %s", asin, reason)
+			}
+			selected = append(selected, formItem)
+			break
+		}
+		if !found {
+			return nil, fmt.Errorf("item %s is not in order %s", asin, orderID)
+		}
+	}
+
+	return selected, nil
+}
+
+// fetchCancelForm loads and parses the cancellation page of an order
+func (c *Client) fetchCancelForm(orderID string) (*cancelForm, error) {
+	if orderID == "" {
+		return nil, fmt.Errorf("order ID cannot be empty")
+	}
+	if !c.marketplace.ValidOrderID(orderID) {
+		return nil, fmt.Errorf("invalid order ID format: expected XXX-XXXXXXX-XXXXXXX, got %s", orderID)
+	}
+
+	params := url.Values{}
+	params.Set("orderID", orderID)
+	params.Set("type", "e")
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+cancelPagePath+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	body, err := c.fetchCancelPage(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch cancellation page: %w", err)
+	}
+
+	form, err := parseCancelForm(body)
+	if err != nil {
+		return nil, err
+	}
+	if len(form.items) == 0 {
+		return nil, fmt.Errorf("order not found: %s", orderID)
+	}
+
+	// Resolve the form action against the page it came from
+	action, err := req.URL.Parse(form.action)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cancellation form action %q: %w", form.action, err)
+	}
+	form.action = action.String()
+
+	return form, nil
+}
+
+// fetchCancelPage executes a cancellation page request and returns the body
+func (c *Client) fetchCancelPage(req *http.Request) ([]byte, error) {
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	body := &bytes.Buffer{}
+	if _, err := body.ReadFrom(resp.Body); err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if c.detectCAPTCHA(body.Bytes()) {
+		return nil, fmt.Errorf("CAPTCHA detected - Amazon is blocking automated access")
+	}
+
+	return body.Bytes(), nil
+}
+
+// parseCancelForm parses the items of a cancellation page and the form that submits it.
+// Items whose checkbox is missing or disabled can no longer be cancelled.
+func parseCancelForm(html []byte) (*cancelForm, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(html))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	form := &cancelForm{
+		action: cancelPagePath,
+		fields: url.Values{},
+	}
+
+	formSel := doc.Find("form#cancel-items-form").First()
+	if action, exists := formSel.Attr("action"); exists && action != "" {
+		form.action = action
+	}
+
+	// Keep hidden fields such as the order ID and anti-CSRF token
+	formSel.Find(`input[type="hidden"]`).Each(func(i int, s *goquery.Selection) {
+		if name, exists := s.Attr("name"); exists && name != "" {
+			form.fields.Add(name, s.AttrOr("value", ""))
+		}
+	})
+	formSel.Find(`input[type="submit"][name]`).First().Each(func(i int, s *goquery.Selection) {
+		form.fields.Set(s.AttrOr("name", ""), s.AttrOr("value", ""))
+	})
+
+	doc.Find(".cancel-item").Each(func(i int, s *goquery.Selection) {
+		formItem := cancelFormItem{
+			item: models.CancellableItem{
+				ASIN:     s.AttrOr("data-asin", ""),
+				Title:    strings.TrimSpace(s.Find(".item-title").Text()),
+				Quantity: 1,
+			},
+		}
+		if formItem.item.ASIN == "" {
+			if match := productLinkRegex.FindStringSubmatch(s.Find("a[href]").AttrOr("href", "")); match != nil {
+				formItem.item.ASIN = match[1]
+			}
+		}
+		if quantity, err := strconv.Atoi(strings.TrimSpace(s.Find(".item-quantity").Text())); err == nil && quantity > 0 {
+			formItem.item.Quantity = quantity
+		}
+
+		checkbox := s.Find(`input[type="checkbox"]`).First()
+		_, disabled := checkbox.Attr("disabled")
+		if checkbox.Length() > 0 && !disabled && formSel.Length() > 0 {
+			formItem.item.Cancellable = true
+			formItem.field = checkbox.AttrOr("name", "")
+			formItem.value = checkbox.AttrOr("value", "on")
+		} else {
+			formItem.item.Reason = strings.TrimSpace(s.Find(".cancel-unavailable").Text())
+		}
+
+		if formItem.item.ASIN != "" {
+			form.items = append(form.items, formItem)
+		}
+	})
+
+	return form, nil
+}
+
+// parseCancelConfirmation reads the outcome of a submitted cancellation
+func parseCancelConfirmation(html []byte) (string, string, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(html))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	message := collapseWhitespace(doc.Find(".cancel-confirmation").Text())
+	if message == "" {
+		if alert := collapseWhitespace(doc.Find(".cancel-error, .a-alert-error").Text()); alert != "" {
+			return "", "", fmt.Errorf("cancellation failed: %s", alert)
+		}
+		return "", "", fmt.Errorf("cancellation was not confirmed by Amazon")
+	}
+
+	lower := strings.ToLower(message)
+	if strings.Contains(lower, "request") || strings.Contains(lower, "attempt") {
+		return models.CancelStatusRequested, message, nil
+	}
+	return models.CancelStatusCancelled, message, nil
+}
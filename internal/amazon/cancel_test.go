@@ -0,0 +1,232 @@
+package amazon
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+// cancelMockServer is a stateful order edit page: items are cancellable until they
+// are cancelled or marked shipped, and submissions must carry the CSRF token
+type cancelMockServer struct {
+	*httptest.Server
+	mu          sync.Mutex
+	orderID     string
+	items       []string
+	cancelled   map[string]bool
+	shipped     map[string]bool
+	submissions int
+}
+
+func newCancelMockServer(orderID string, items ...string) *cancelMockServer {
+	mock := &cancelMockServer{
+		orderID:   orderID,
+		items:     items,
+		cancelled: make(map[string]bool),
+		shipped:   make(map[string]bool),
+	}
+	mock.Server = httptest.NewServer(http.HandlerFunc(mock.handle))
+	return mock
+}
+
+func (m *cancelMockServer) handle(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if r.URL.Path != cancelPagePath {
+		http.NotFound(w, r)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		_ = r.ParseForm()
+		if r.PostForm.Get("anti-csrftoken-a2z") != "csrf-token" || r.PostForm.Get("orderID") != m.orderID {
+			fmt.Fprint(w, `<html><body><div class="a-alert-error">Your session has expired</div></body></html>`)
+			return
+		}
+		m.submissions++
+		for i, asin := range m.items {
+			if r.PostForm.Get(fmt.Sprintf("cancel.item.%d", i)) != "" {
+				m.cancelled[asin] = true
+			}
+		}
+		fmt.Fprint(w, `<html><body><div class="cancel-confirmation">The checked items have been cancelled.</div></body></html>`)
+		return
+	}
+
+	if r.URL.Query().Get("orderID") != m.orderID {
+		fmt.Fprint(w, `<html><body>We're unable to find this order.</body></html>`)
+		return
+	}
+
+	var html strings.Builder
+	fmt.Fprintf(&html, `<html><body><form id="cancel-items-form" method="post" action="%s">`, cancelPagePath)
+	fmt.Fprintf(&html, `<input type="hidden" name="orderID" value="%s"><input type="hidden" name="anti-csrftoken-a2z" value="csrf-token">`, m.orderID)
+	for i, asin := range m.items {
+		fmt.Fprintf(&html, `<div class="cancel-item" data-asin="%s"><span class="item-title">Item %d</span>`, asin, i)
+		switch {
+		case m.cancelled[asin]:
+			html.WriteString(`<span class="cancel-unavailable">Cancelled</span>`)
+		case m.shipped[asin]:
+			html.WriteString(`<span class="cancel-unavailable">This item has shipped</span>`)
+		default:
+			fmt.Fprintf(&html, `<input type="checkbox" name="cancel.item.%d" value="on">`, i)
+		}
+		html.WriteString(`</div>`)
+	}
+	html.WriteString(`<input type="submit" name="cancel.submit" value="Cancel checked items"></form></body></html>`)
+	_, _ = w.Write([]byte(html.String()))
+}
+
+func TestParseCancelForm(t *testing.T) {
+	fixtureData, err := os.ReadFile(filepath.Join("..", "..", "testdata", "orders", "order_cancel_sample.html"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+
+	form, err := parseCancelForm(fixtureData)
+	if err != nil {
+		t.Fatalf("parseCancelForm() error = %v", err)
+	}
+
+	if form.action != "/gp/your-account/order-edit.html" {
+		t.Errorf("Unexpected form action %s", form.action)
+	}
+	if form.fields.Get("anti-csrftoken-a2z") != "csrf-token-123" || form.fields.Get("cancel.submit") == "" {
+		t.Errorf("Expected hidden and submit fields, got %v", form.fields)
+	}
+	if len(form.items) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(form.items))
+	}
+
+	echo := form.items[0]
+	if echo.item.ASIN != "B08N5WRWNW" || echo.item.Quantity != 2 || !echo.item.Cancellable || echo.field != "cancel.item.0" {
+		t.Errorf("Unexpected cancellable item %+v", echo)
+	}
+	cable := form.items[1]
+	if cable.item.ASIN != "B0C1H2J3K4" || cable.item.Cancellable || !strings.Contains(cable.item.Reason, "shipped") {
+		t.Errorf("Expected shipped item to be not cancellable, got %+v", cable.item)
+	}
+}
+
+func TestCancelOrder_SelectedItems(t *testing.T) {
+	orderID := "123-4567890-1234567"
+	server := newCancelMockServer(orderID, "B08N5WRWNW", "B0C1H2J3K4", "B0D9Z8Y7X6")
+	defer server.Close()
+	server.shipped["B0D9Z8Y7X6"] = true
+
+	client := newSyncTestClient(server.URL)
+
+	preview, err := client.GetCancellableItems(orderID)
+	if err != nil {
+		t.Fatalf("GetCancellableItems() error = %v", err)
+	}
+	if len(preview.Items) != 3 || !preview.Items[0].Cancellable || preview.Items[2].Cancellable {
+		t.Errorf("Unexpected preview %+v", preview.Items)
+	}
+
+	// A shipped item cannot be cancelled and nothing is submitted
+	if _, err := client.CancelOrder(orderID, []string{"B0D9Z8Y7X6"}); err == nil || !strings.Contains(err.Error(), "can no longer be cancelled") {
+		t.Errorf("Expected not cancellable error, got %v", err)
+	}
+	if _, err := client.CancelOrder(orderID, []string{"B000000000"}); err == nil || !strings.Contains(err.Error(), "is not in order") {
+		t.Errorf("Expected unknown item error, got %v", err)
+	}
+	if server.submissions != 0 {
+		t.Errorf("Expected no submissions for rejected requests, got %d", server.submissions)
+	}
+
+	result, err := client.CancelOrder(orderID, []string{"b08n5wrwnw"})
+	if err != nil {
+		t.Fatalf("CancelOrder() error = %v", err)
+	}
+	if result.Status != models.CancelStatusCancelled || len(result.Cancelled) != 1 || result.Cancelled[0].ASIN != "B08N5WRWNW" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if len(result.Remaining) != 2 {
+		t.Errorf("Expected 2 remaining items, got %+v", result.Remaining)
+	}
+	if !server.cancelled["B08N5WRWNW"] || server.cancelled["B0C1H2J3K4"] {
+		t.Errorf("Expected only the selected item to be cancelled, got %v", server.cancelled)
+	}
+
+	// The cancelled item is no longer cancellable
+	preview, _ = client.GetCancellableItems(orderID)
+	if preview.Items[0].Cancellable || preview.Items[0].Reason != "Cancelled" {
+		t.Errorf("Expected cancelled item to be reported, got %+v", preview.Items[0])
+	}
+}
+
+func TestSelectCancellableItems(t *testing.T) {
+	cancellation := &models.OrderCancellation{
+		OrderID: "123-4567890-1234567",
+		Items: []models.CancellableItem{
+			{ASIN: "B08N5WRWNW", Cancellable: true},
+			{ASIN: "B0C1H2J3K4", Reason: "This item has shipped"},
+		},
+	}
+
+	all, err := SelectCancellableItems(cancellation, nil)
+	if err != nil || len(all) != 1 || all[0].ASIN != "B08N5WRWNW" {
+		t.Errorf("Expected every cancellable item, got %+v (%v)", all, err)
+	}
+	selected, err := SelectCancellableItems(cancellation, []string{"b08n5wrwnw "})
+	if err != nil || len(selected) != 1 || selected[0].ASIN != "B08N5WRWNW" {
+		t.Errorf("Expected a case-insensitive ASIN match, got %+v (%v)", selected, err)
+	}
+	if _, err := SelectCancellableItems(cancellation, []string{"B000000000"}); err == nil || !strings.Contains(err.Error(), "is not in order") {
+		t.Errorf("Expected unknown item error, got %v", err)
+	}
+	if _, err := SelectCancellableItems(cancellation, []string{"B0C1H2J3K4"}); err == nil || !strings.Contains(err.Error(), "has shipped") {
+		t.Errorf("Expected not cancellable error, got %v", err)
+	}
+}
+
+func TestCancelOrder_AllCancellableItems(t *testing.T) {
+	orderID := "123-4567890-1234567"
+	server := newCancelMockServer(orderID, "B08N5WRWNW", "B0C1H2J3K4")
+	defer server.Close()
+
+	client := newSyncTestClient(server.URL)
+
+	result, err := client.CancelOrder(orderID, nil)
+	if err != nil {
+		t.Fatalf("CancelOrder() error = %v", err)
+	}
+	if len(result.Cancelled) != 2 || len(result.Remaining) != 0 {
+		t.Errorf("Expected every item to be cancelled, got %+v", result)
+	}
+
+	// Nothing is left to cancel
+	if _, err := client.CancelOrder(orderID, nil); err == nil || !strings.Contains(err.Error(), "no items that can still be cancelled") {
+		t.Errorf("Expected error once every item is cancelled, got %v", err)
+	}
+
+	if _, err := client.GetCancellableItems("123-0000000-0000000"); err == nil || !strings.Contains(err.Error(), "order not found") {
+		t.Errorf("Expected order not found, got %v", err)
+	}
+	if _, err := client.CancelOrder("bad", nil); err == nil {
+		t.Error("Expected error for invalid order ID")
+	}
+}
+
+func TestParseCancelConfirmation(t *testing.T) {
+	status, _, err := parseCancelConfirmation([]byte(`<div class="cancel-confirmation">We've received your cancellation request.</div>`))
+	if err != nil || status != models.CancelStatusRequested {
+		t.Errorf("Expected requested status, got %q (%v)", status, err)
+	}
+
+	if _, _, err := parseCancelConfirmation([]byte(`<div class="a-alert-error">Your session has expired</div>`)); err == nil || !strings.Contains(err.Error(), "session has expired") {
+		t.Errorf("Expected Amazon's error message, got %v", err)
+	}
+	if _, _, err := parseCancelConfirmation([]byte(`<html><body>Your Orders</body></html>`)); err == nil {
+		t.Error("Expected error without a confirmation")
+	}
+}
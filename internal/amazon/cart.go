@@ -2,10 +2,13 @@ package amazon
 
 import (
 	"fmt"
+	"log/slog"
 	"net/http"
 	"regexp"
 	"time"
 
+	"github.com/zkwentz/amazon-cli/internal/marketplace"
+	"github.com/zkwentz/amazon-cli/internal/orderindex"
 	"github.com/zkwentz/amazon-cli/internal/ratelimit"
 	"github.com/zkwentz/amazon-cli/pkg/models"
 )
@@ -15,22 +18,31 @@ import (
 type Client struct {
 	httpClient  *http.Client
 	baseURL     string
+	marketplace *marketplace.Marketplace
 	cart        *models.Cart // In-memory cart for testing/development
 	rateLimiter *ratelimit.RateLimiter
-	maxRetries  int
+	cache       Cache
+	clock       Clock
+	logger      *slog.Logger
+	orderIndex  *orderindex.Index
+	notifier    func(models.HookEvent)
+	profile     string
 }
 
-// NewClient creates a new Amazon API client with default rate limiting
-func NewClient() *Client {
+// NewClient creates a new Amazon API client with default rate limiting.
+// Options override the defaults, e.g. WithBaseURL to point at a mock server.
+func NewClient(opts ...Option) *Client {
 	minDelay := 2 * time.Second
 	maxDelay := 60 * time.Second
 	maxRetries := 3
 
-	return &Client{
+	c := &Client{
 		httpClient:  &http.Client{Timeout: 30 * time.Second},
-		baseURL:     "https://www.amazon.com",
+		baseURL:     marketplace.Default().BaseURL(),
+		marketplace: marketplace.Default(),
 		rateLimiter: ratelimit.NewRateLimiter(minDelay, maxDelay, maxRetries),
-		maxRetries:  maxRetries,
+		clock:       systemClock{},
+		logger:      slog.New(slog.DiscardHandler),
 		cart: &models.Cart{
 			Items:        []models.CartItem{},
 			Subtotal:     0,
@@ -39,6 +51,12 @@ func NewClient() *Client {
 			ItemCount:    0,
 		},
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
 }
 
 // ValidateASIN validates that an ASIN is in the correct format
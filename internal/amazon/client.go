@@ -3,9 +3,9 @@ package amazon
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"math/rand"
 	"net/http"
-	"time"
 )
 
 // userAgents contains a list of common browser User-Agent strings
@@ -33,16 +33,11 @@ var userAgents = []string{
 	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0",
 }
 
-var rng *rand.Rand
-
-func init() {
-	// Initialize random number generator with current time as seed
-	rng = rand.New(rand.NewSource(time.Now().UnixNano()))
-}
-
-// getRandomUserAgent returns a random User-Agent string from the userAgents slice
+// getRandomUserAgent returns a random User-Agent string from the userAgents slice.
+// It uses the top-level math/rand functions, which are safe for the concurrent
+// requests made when expanding orders.
 func getRandomUserAgent() string {
-	return userAgents[rng.Intn(len(userAgents))]
+	return userAgents[rand.Intn(len(userAgents))]
 }
 
 // Do executes an HTTP request with rate limiting, retries, and proper headers
@@ -50,19 +45,30 @@ func getRandomUserAgent() string {
 // requests that fail with 429 (Too Many Requests) or 503 (Service Unavailable)
 // status codes using exponential backoff.
 func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	// Serve GET requests from the cache when one is configured
+	cacheKey := c.cacheKey(req.URL.String())
+	if c.cache != nil && req.Method == http.MethodGet {
+		if body, ok := c.cache.Get(cacheKey); ok {
+			c.logger.Debug("cache hit", "url", req.URL.String())
+			return cachedResponse(req, body), nil
+		}
+	}
+
 	// Enforce rate limiting before making the request
 	c.rateLimiter.Wait()
 
 	// Set headers to mimic a real browser request
 	req.Header.Set("User-Agent", getRandomUserAgent())
 	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8")
-	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	req.Header.Set("Accept-Language", c.marketplace.AcceptLanguage())
 
 	// Execute the initial request
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		c.logger.Debug("request failed", "method", req.Method, "url", req.URL.String(), "error", err)
 		return nil, fmt.Errorf("network request failed: %w", err)
 	}
+	c.logger.Debug("request", "method", req.Method, "url", req.URL.String(), "status", resp.StatusCode)
 
 	// Check if we should retry based on status code
 	attempt := 0
@@ -77,16 +83,53 @@ func (c *Client) Do(req *http.Request) (*http.Response, error) {
 		// Set a new random User-Agent for the retry to avoid detection
 		req.Header.Set("User-Agent", getRandomUserAgent())
 
-		// Retry the request
-		resp, err = c.httpClient.Do(req)
+		// Rewind the body so retried form submissions resend it
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("failed to rewind request body: %w", err)
+			}
+			req.Body = body
+		}
+
+		// Retry the request, tagging it with the attempt number for HAR export
+		resp, err = c.httpClient.Do(withAttempt(req, attempt))
 		if err != nil {
+			c.logger.Debug("retry failed", "method", req.Method, "url", req.URL.String(), "attempt", attempt, "error", err)
 			return nil, fmt.Errorf("network request failed: %w", err)
 		}
+		c.logger.Debug("retry", "method", req.Method, "url", req.URL.String(), "attempt", attempt, "status", resp.StatusCode)
+	}
+
+	// Store successful GET responses in the cache
+	if c.cache != nil && req.Method == http.MethodGet && resp.StatusCode == http.StatusOK {
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+		c.cache.Set(cacheKey, body)
+		resp.Body = io.NopCloser(bytes.NewReader(body))
 	}
 
 	return resp, nil
 }
 
+// cachedResponse builds a 200 OK response for a body served from the cache
+func cachedResponse(req *http.Request, body []byte) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"text/html; charset=utf-8"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
 // detectCAPTCHA checks if the response body contains CAPTCHA indicators
 // It looks for common CAPTCHA-related strings and Amazon-specific CAPTCHA patterns
 func (c *Client) detectCAPTCHA(body []byte) bool {
@@ -0,0 +1,29 @@
+package amazon_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/zkwentz/amazon-cli/internal/amazon"
+	"github.com/zkwentz/amazon-cli/internal/ratelimit"
+	"github.com/zkwentz/amazon-cli/internal/testutil"
+)
+
+func TestNewClient_WithBaseURLAgainstMockServer(t *testing.T) {
+	server := testutil.NewMockAmazonServer()
+	defer server.Server.Close()
+	server.ServeFixture("/gp/your-account/order-history", filepath.Join("..", "..", "testdata", "orders", "order_list_sample.html"))
+
+	c := amazon.NewClient(
+		amazon.WithBaseURL(server.Server.URL),
+		amazon.WithRateLimiter(ratelimit.NewRateLimiter(0, 0, 0)),
+	)
+
+	resp, err := c.GetOrders(10, "")
+	if err != nil {
+		t.Fatalf("GetOrders() error = %v", err)
+	}
+	if resp.TotalCount != 3 {
+		t.Errorf("expected 3 orders from fixture, got %d", resp.TotalCount)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/zkwentz/amazon-cli/internal/ratelimit"
 )
 
 func TestGetRandomUserAgent(t *testing.T) {
@@ -248,6 +250,34 @@ func TestDo_RetryOn503(t *testing.T) {
 	}
 }
 
+func TestDo_RetryResendsPostBody(t *testing.T) {
+	var bodies []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithRateLimiter(ratelimit.NewRateLimiter(0, 0, 3)))
+	req, _ := http.NewRequest("POST", server.URL, strings.NewReader("orderID=123"))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do() failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if len(bodies) != 2 || bodies[1] != "orderID=123" {
+		t.Errorf("Expected the retry to resend the body, got %q", bodies)
+	}
+}
+
 func TestDo_StopsAfterMaxRetries(t *testing.T) {
 	attemptCount := 0
 
@@ -0,0 +1,196 @@
+package amazon
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/zkwentz/amazon-cli/internal/marketplace"
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+// deliveryLookbackDays is how far back orders are checked for packages still on their way
+const deliveryLookbackDays = 30
+
+// GetUpcomingDeliveries lists what is expected to arrive in the next days: packages of
+// recent orders that haven't been delivered, tracked through their detail and tracking
+// pages, and the next shipment of active Subscribe & Save subscriptions, sorted by
+// expected date
+func (c *Client) GetUpcomingDeliveries(days int) (*models.DeliveriesResponse, error) {
+	if days <= 0 {
+		return nil, fmt.Errorf("days must be positive")
+	}
+
+	now := c.now()
+	orders, err := c.ListOrders(OrderQuery{Since: now.AddDate(0, 0, -deliveryLookbackDays)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recent orders: %w", err)
+	}
+	if err := c.trackUndelivered(orders.Orders); err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := c.GetSubscriptions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+
+	return upcomingDeliveries(orders.Orders, subscriptions.Subscriptions, now, days, c.marketplace), nil
+}
+
+// trackUndelivered fills in the packages and tracking of the orders still awaiting
+// delivery, which the order history doesn't show. Orders are tracked concurrently,
+// bounded like --expand; requests still pass through the client's rate limiter.
+func (c *Client) trackUndelivered(orders []models.Order) error {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, expandConcurrency)
+
+	for i := range orders {
+		if !awaitingDelivery(orders[i].Status) || !c.marketplace.ValidOrderID(orders[i].OrderID) {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(order *models.Order) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			tracking, err := c.GetOrderShipmentsTracking(order.OrderID)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to track order %s: %w", order.OrderID, err)
+				}
+				mu.Unlock()
+				return
+			}
+			if len(tracking.Shipments) == 0 {
+				return
+			}
+			order.Shipments = tracking.Shipments
+			if order.Tracking == nil {
+				order.Tracking = tracking.Shipments[0].Tracking
+			}
+		}(&orders[i])
+	}
+
+	wg.Wait()
+	return firstErr
+}
+
+// upcomingDeliveries collects the deliveries expected on or before the last day of the
+// window. Undelivered packages are included even when overdue or without an expected
+// date, which sort last; subscriptions are included when their next shipment falls
+// within the window.
+func upcomingDeliveries(orders []models.Order, subscriptions []models.Subscription, now time.Time, days int, m *marketplace.Marketplace) *models.DeliveriesResponse {
+	m = marketplaceOrDefault(m)
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	until := today.AddDate(0, 0, days)
+	deliveries := []models.Delivery{}
+
+	// Collect packages still on their way
+	for _, order := range orders {
+		if !awaitingDelivery(order.Status) {
+			continue
+		}
+
+		shipments := order.Shipments
+		if len(shipments) == 0 {
+			shipments = []models.Shipment{{Status: order.Status, Items: order.Items, Tracking: order.Tracking}}
+		}
+		for _, shipment := range shipments {
+			if !awaitingDelivery(shipment.Status) {
+				continue
+			}
+
+			expected := ""
+			if shipment.Tracking != nil {
+				if date, ok := parseDeliveryDate(shipment.Tracking.DeliveryDate, m); ok {
+					if date.After(until) {
+						continue
+					}
+					expected = date.Format("2006-01-02")
+				}
+			}
+
+			deliveries = append(deliveries, models.Delivery{
+				ExpectedDate: expected,
+				Source:       models.DeliverySourceOrder,
+				OrderID:      order.OrderID,
+				ShipmentID:   shipment.ShipmentID,
+				Status:       shipment.Status,
+				Items:        shipment.Items,
+				Tracking:     shipment.Tracking,
+			})
+		}
+	}
+
+	// Collect subscription shipments due within the window
+	for _, sub := range subscriptions {
+		if sub.Status != "active" || sub.NextDelivery.IsZero() {
+			continue
+		}
+		next := time.Date(sub.NextDelivery.Year(), sub.NextDelivery.Month(), sub.NextDelivery.Day(), 0, 0, 0, 0, time.UTC)
+		if next.Before(today) || next.After(until) {
+			continue
+		}
+
+		quantity := sub.Quantity
+		if quantity <= 0 {
+			quantity = 1
+		}
+		deliveries = append(deliveries, models.Delivery{
+			ExpectedDate:   next.Format("2006-01-02"),
+			Source:         models.DeliverySourceSubscription,
+			SubscriptionID: sub.ID,
+			Status:         models.OrderStatusPending,
+			Items:          []models.OrderItem{{ASIN: sub.ASIN, Title: sub.Title, Quantity: quantity, Price: sub.Price}},
+		})
+	}
+
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		a, b := deliveries[i].ExpectedDate, deliveries[j].ExpectedDate
+		if (a == "") != (b == "") {
+			return b == ""
+		}
+		return a < b
+	})
+
+	return &models.DeliveriesResponse{
+		Days:       days,
+		Until:      until.Format("2006-01-02"),
+		Deliveries: deliveries,
+		TotalCount: len(deliveries),
+	}
+}
+
+// awaitingDelivery reports whether an order or shipment status means the package is still coming
+func awaitingDelivery(status string) bool {
+	switch status {
+	case models.OrderStatusPending, models.OrderStatusOutForDelivery, models.OrderStatusDelayed:
+		return true
+	}
+	return false
+}
+
+// parseDeliveryDate parses a tracking delivery date, which is YYYY-MM-DD when the
+// marketplace format was recognised and the raw page text otherwise
+func parseDeliveryDate(text string, m *marketplace.Marketplace) (time.Time, bool) {
+	if text == "" {
+		return time.Time{}, false
+	}
+	if date, err := time.Parse("2006-01-02", text); err == nil {
+		return date, true
+	}
+	if date, err := m.ParseDate(text); err == nil {
+		return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC), true
+	}
+	return time.Time{}, false
+}
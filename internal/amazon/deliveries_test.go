@@ -0,0 +1,163 @@
+package amazon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zkwentz/amazon-cli/internal/marketplace"
+	"github.com/zkwentz/amazon-cli/internal/ratelimit"
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+func TestUpcomingDeliveries(t *testing.T) {
+	now := time.Date(2026, time.March, 2, 15, 0, 0, 0, time.UTC)
+	item := func(title string) []models.OrderItem {
+		return []models.OrderItem{{ASIN: "B0" + title, Title: title, Quantity: 1}}
+	}
+
+	orders := []models.Order{
+		// Arriving in the window
+		{OrderID: "111-0000000-0000001", Status: models.OrderStatusPending, Items: item("Lamp"),
+			Tracking: &models.Tracking{Carrier: "UPS", DeliveryDate: "2026-03-05"}},
+		// Arriving after the window
+		{OrderID: "111-0000000-0000002", Status: models.OrderStatusPending, Items: item("Desk"),
+			Tracking: &models.Tracking{DeliveryDate: "2026-03-20"}},
+		// Already delivered
+		{OrderID: "111-0000000-0000003", Status: models.OrderStatusDelivered, Items: item("Mug"),
+			Tracking: &models.Tracking{DeliveryDate: "2026-03-01"}},
+		// No expected date yet, and a raw date the marketplace format parses
+		{OrderID: "111-0000000-0000004", Status: models.OrderStatusPending, Items: item("Chair")},
+		{OrderID: "111-0000000-0000005", Status: models.OrderStatusOutForDelivery, Items: item("Pens"),
+			Tracking: &models.Tracking{DeliveryDate: "March 2, 2026"}},
+		// Split into packages; only the one still coming is listed
+		{OrderID: "111-0000000-0000006", Status: models.OrderStatusPending, Shipments: []models.Shipment{
+			{ShipmentID: "A", Status: models.OrderStatusDelivered, Items: item("Cable")},
+			{ShipmentID: "B", Status: models.OrderStatusPending, Items: item("Charger"), Tracking: &models.Tracking{DeliveryDate: "2026-03-04"}},
+		}},
+		{OrderID: "111-0000000-0000007", Status: models.OrderStatusCancelled, Items: item("Toy")},
+	}
+	subscriptions := []models.Subscription{
+		{ID: "sub1", Title: "Coffee", Status: "active", Quantity: 2, NextDelivery: now.AddDate(0, 0, 3)},
+		{ID: "sub2", Title: "Soap", Status: "active", NextDelivery: now.AddDate(0, 0, 30)},
+		{ID: "sub3", Title: "Tea", Status: "paused", NextDelivery: now.AddDate(0, 0, 1)},
+	}
+
+	result := upcomingDeliveries(orders, subscriptions, now, 7, marketplace.Default())
+
+	if result.Days != 7 || result.Until != "2026-03-09" {
+		t.Errorf("Unexpected window: %d days until %s", result.Days, result.Until)
+	}
+
+	expected := []struct {
+		date  string
+		id    string
+		title string
+	}{
+		{"2026-03-02", "111-0000000-0000005", "Pens"},
+		{"2026-03-04", "111-0000000-0000006", "Charger"},
+		{"2026-03-05", "111-0000000-0000001", "Lamp"},
+		{"2026-03-05", "sub1", "Coffee"},
+		{"", "111-0000000-0000004", "Chair"},
+	}
+	if result.TotalCount != len(expected) || len(result.Deliveries) != len(expected) {
+		t.Fatalf("Expected %d deliveries, got %+v", len(expected), result.Deliveries)
+	}
+	for i, want := range expected {
+		got := result.Deliveries[i]
+		id := got.OrderID
+		if got.Source == models.DeliverySourceSubscription {
+			id = got.SubscriptionID
+		}
+		if got.ExpectedDate != want.date || id != want.id || got.Items[0].Title != want.title {
+			t.Errorf("Delivery %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+
+	if result.Deliveries[1].ShipmentID != "B" {
+		t.Errorf("Expected the package's shipment ID, got %q", result.Deliveries[1].ShipmentID)
+	}
+	if sub := result.Deliveries[3]; sub.Items[0].Quantity != 2 || sub.Status != models.OrderStatusPending {
+		t.Errorf("Unexpected subscription delivery: %+v", sub)
+	}
+
+	rows := result.Rows()
+	if len(rows) != len(expected) || rows[2][4] != "UPS" || rows[3][5] != "Coffee x2" {
+		t.Errorf("Unexpected table rows: %v", rows)
+	}
+}
+
+func TestGetUpcomingDeliveries_InvalidDays(t *testing.T) {
+	if _, err := NewClient().GetUpcomingDeliveries(0); err == nil {
+		t.Error("Expected an error for a non-positive number of days")
+	}
+}
+
+func TestGetUpcomingDeliveries_TracksUndeliveredOrders(t *testing.T) {
+	detail, err := os.ReadFile(filepath.Join("..", "..", "testdata", "orders", "order_detail_shipments_sample.html"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture file: %v", err)
+	}
+
+	var (
+		mu      sync.Mutex
+		details []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gp/your-account/order-history":
+			_, _ = w.Write([]byte(`<html><body><div id="ordersContainer">
+<div class="order" data-order-id="114-3141592-6535897"><span class="order-date">March 1, 2026</span><span class="order-total">$94.93</span><span class="delivery-status">Shipped</span></div>
+<div class="order" data-order-id="111-0000000-0000001"><span class="order-date">February 20, 2026</span><span class="order-total">$9.99</span><span class="delivery-status">Delivered</span></div>
+</div></body></html>`))
+		case "/gp/your-account/order-details":
+			mu.Lock()
+			details = append(details, r.URL.Query().Get("orderID"))
+			mu.Unlock()
+			_, _ = w.Write(detail)
+		case "/progress-tracker/package/ref=ppx_yo_dt_b_track_package":
+			if r.URL.Query().Get("shipmentId") == "DmF7kq3Lz" {
+				_, _ = w.Write([]byte(trackingPage("Delivered")))
+				return
+			}
+			_, _ = w.Write([]byte(`<html><body><div class="tracking-number"><span class="value">9400111899223344556677</span></div><div class="tracking-status"><span class="value">Out for delivery</span></div><div class="delivery-date"><span class="value">March 3, 2026</span></div></body></html>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	clock := &fixedClock{now: time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)}
+	client := NewClient(WithBaseURL(server.URL), WithClock(clock), WithRateLimiter(ratelimit.NewRateLimiter(0, 0, 0)))
+
+	result, err := client.GetUpcomingDeliveries(7)
+	if err != nil {
+		t.Fatalf("GetUpcomingDeliveries() error = %v", err)
+	}
+
+	// Only the undelivered order is tracked
+	if len(details) != 1 || details[0] != "114-3141592-6535897" {
+		t.Errorf("Expected only the undelivered order to be tracked, got %v", details)
+	}
+
+	var packages []models.Delivery
+	for _, delivery := range result.Deliveries {
+		if delivery.Source == models.DeliverySourceOrder {
+			packages = append(packages, delivery)
+		}
+	}
+	if len(packages) != 1 {
+		t.Fatalf("Expected the package still on its way, got %+v", result.Deliveries)
+	}
+	got := packages[0]
+	if got.OrderID != "114-3141592-6535897" || got.ShipmentID != "Dq9Rt2Wxy" || got.ExpectedDate != "2026-03-03" {
+		t.Errorf("Unexpected delivery: %+v", got)
+	}
+	if got.Tracking == nil || got.Tracking.TrackingNumber != "9400111899223344556677" || got.Items[0].ASIN != "B0BBBB2222" {
+		t.Errorf("Expected the package's tracking and items, got %+v", got)
+	}
+}
@@ -0,0 +1,183 @@
+package amazon
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+// OrderTypeAll selects both physical and digital orders
+const OrderTypeAll = "all"
+
+// digitalOrderDetailPath is the order summary page of a digital order
+const digitalOrderDetailPath = "/gp/digital/your-account/order-summary.html"
+
+// OrderTypes returns the order types accepted by OrderQuery.Type
+func OrderTypes() []string {
+	return []string{models.OrderTypePhysical, models.OrderTypeDigital, OrderTypeAll}
+}
+
+// ValidateOrderType checks an order type; empty means physical
+func ValidateOrderType(orderType string) error {
+	if orderType == "" {
+		return nil
+	}
+	for _, t := range OrderTypes() {
+		if orderType == t {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported order type %q: use %s", orderType, strings.Join(OrderTypes(), ", "))
+}
+
+// orderType returns the type of the orders a query selects, defaulting to physical
+func (q OrderQuery) orderType() string {
+	if q.Type == "" {
+		return models.OrderTypePhysical
+	}
+	return q.Type
+}
+
+// orderTypeOf returns the type of an order from its ID: digital order IDs start with "D"
+func orderTypeOf(orderID string) string {
+	if strings.HasPrefix(orderID, "D") {
+		return models.OrderTypeDigital
+	}
+	return models.OrderTypePhysical
+}
+
+// digitalOrderHistoryURL builds the digital order history URL for a time filter and start index
+func (c *Client) digitalOrderHistoryURL(timeFilter string, startIndex int) string {
+	params := url.Values{}
+	params.Set("digitalOrders", "1")
+	params.Set("unifiedOrders", "0")
+	if timeFilter != "" {
+		params.Set("timeFilter", timeFilter)
+	}
+	if startIndex > 0 {
+		params.Set("startIndex", strconv.Itoa(startIndex))
+	}
+
+	return fmt.Sprintf("%s/gp/your-account/order-history?%s", c.baseURL, params.Encode())
+}
+
+// orderDetailURL returns the detail page of an order; digital orders have their own page
+func (c *Client) orderDetailURL(orderID string) string {
+	if orderTypeOf(orderID) == models.OrderTypeDigital {
+		return fmt.Sprintf("%s%s?orderID=%s", c.baseURL, digitalOrderDetailPath, orderID)
+	}
+	return fmt.Sprintf("%s/gp/your-account/order-details?orderID=%s", c.baseURL, orderID)
+}
+
+// walkAllOrders walks the physical and digital order histories side by side, calling
+// fn with their orders merged newest first (physical first on the same day). Each
+// history is only read as far as the merge needs, so orders stream like a single
+// walk. The query's limit applies to the merged orders.
+func (c *Client) walkAllOrders(query OrderQuery, fn func(models.Order) error) error {
+	type stream struct {
+		orderType string
+		next      func() (models.Order, bool)
+		stop      func()
+		head      *models.Order
+		err       error
+	}
+
+	var streams []*stream
+	for _, orderType := range []string{models.OrderTypePhysical, models.OrderTypeDigital} {
+		s := &stream{orderType: orderType}
+		typed := query
+		typed.Type = orderType
+		s.next, s.stop = iter.Pull(func(yield func(models.Order) bool) {
+			s.err = c.WalkOrders(typed, func(order models.Order) error {
+				if !yield(order) {
+					return errStopWalk
+				}
+				return nil
+			})
+		})
+		defer s.stop()
+		streams = append(streams, s)
+	}
+
+	// advance reads the next order of a stream, or reports why it has none
+	advance := func(s *stream) error {
+		order, ok := s.next()
+		if !ok {
+			s.head = nil
+			if s.err != nil && !errors.Is(s.err, errStopWalk) {
+				return fmt.Errorf("failed to list %s orders: %w", s.orderType, s.err)
+			}
+			return nil
+		}
+		s.head = &order
+		return nil
+	}
+	for _, s := range streams {
+		if err := advance(s); err != nil {
+			return err
+		}
+	}
+
+	for count := 0; query.Limit <= 0 || count < query.Limit; count++ {
+		var newest *stream
+		for _, s := range streams {
+			if s.head != nil && (newest == nil || c.newerOrder(*s.head, *newest.head)) {
+				newest = s
+			}
+		}
+		if newest == nil {
+			return nil
+		}
+
+		if err := fn(*newest.head); err != nil {
+			return err
+		}
+		if err := advance(newest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newerOrder reports whether order a was placed after order b; orders without a
+// recognizable date sort after dated ones
+func (c *Client) newerOrder(a, b models.Order) bool {
+	dateA, errA := c.marketplace.ParseDate(a.Date)
+	dateB, errB := c.marketplace.ParseDate(b.Date)
+	if errA != nil || errB != nil {
+		return errA == nil && errB != nil
+	}
+	return dateA.After(dateB)
+}
+
+// sortOrdersByDate sorts orders newest first; orders without a recognizable date go last
+func (c *Client) sortOrdersByDate(orders []models.Order) {
+	sort.SliceStable(orders, func(i, j int) bool {
+		return c.newerOrder(orders[i], orders[j])
+	})
+}
+
+// digitalLabelRegex matches the label in front of a digital item field, e.g. "Delivered to:"
+var digitalLabelRegex = regexp.MustCompile(`(?i)^(format|delivered to|sent to|license(\s+key)?)\s*:\s*`)
+
+// parseDigitalItemFields fills in the format, delivery device and license a digital
+// item row shows. Physical item rows have none of them.
+func parseDigitalItemFields(s *goquery.Selection, item *models.OrderItem) {
+	item.Format = digitalItemField(s, ".item-format, .digital-format")
+	item.DeliveredTo = digitalItemField(s, ".item-delivered-to, .digital-delivered-to")
+	item.License = digitalItemField(s, ".item-license, .digital-license")
+}
+
+// digitalItemField returns the text of the first element matching selector without its label
+func digitalItemField(s *goquery.Selection, selector string) string {
+	text := collapseWhitespace(s.Find(selector).First().Text())
+	return strings.TrimSpace(digitalLabelRegex.ReplaceAllString(text, ""))
+}
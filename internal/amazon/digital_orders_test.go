@@ -0,0 +1,244 @@
+package amazon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zkwentz/amazon-cli/internal/marketplace"
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+// digitalHistoryServer serves the physical order history views, or the digital ones
+// when the request asks for digital orders
+func digitalHistoryServer(t *testing.T, physical, digital map[string][]models.Order) *httptest.Server {
+	t.Helper()
+
+	var physicalRequests, digitalRequests []string
+	physicalServer := orderHistoryServer(t, physical, []int{2026}, &physicalRequests)
+	t.Cleanup(physicalServer.Close)
+	digitalServer := orderHistoryServer(t, digital, []int{2026}, &digitalRequests)
+	t.Cleanup(digitalServer.Close)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("digitalOrders") == "1" {
+			digitalServer.Config.Handler.ServeHTTP(w, r)
+			return
+		}
+		physicalServer.Config.Handler.ServeHTTP(w, r)
+	}))
+}
+
+func TestParseOrdersHTML_DigitalOrders(t *testing.T) {
+	fixtureData, err := os.ReadFile(filepath.Join("..", "..", "testdata", "orders", "digital_order_list_sample.html"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture file: %v", err)
+	}
+
+	orders, err := parseOrdersHTML(fixtureData, marketplace.Default())
+	if err != nil {
+		t.Fatalf("parseOrdersHTML failed: %v", err)
+	}
+	if len(orders) != 2 {
+		t.Fatalf("Expected 2 digital orders, got %d", len(orders))
+	}
+
+	book := orders[0]
+	if book.Type != models.OrderTypeDigital || book.Status != models.OrderStatusDelivered {
+		t.Errorf("Expected a delivered digital order without a status, got type %q status %q", book.Type, book.Status)
+	}
+	expected := models.OrderItem{
+		ASIN:        "B0BOOK1234",
+		Title:       "The Pragmatic Programmer",
+		Quantity:    1,
+		Price:       12.99,
+		Format:      "Kindle Edition",
+		DeliveredTo: "Alex's Kindle Paperwhite",
+	}
+	if len(book.Items) != 1 || book.Items[0] != expected {
+		t.Errorf("Expected %+v, got %+v", expected, book.Items)
+	}
+
+	software := orders[1]
+	if software.Status != models.OrderStatusRefunded {
+		t.Errorf("Expected the listed status to be kept, got %q", software.Status)
+	}
+	if item := software.Items[0]; item.Format != "Software Download" || item.License != "ABCD-EFGH-IJKL-MNOP" || item.DeliveredTo != "" {
+		t.Errorf("Unexpected software item: %+v", item)
+	}
+}
+
+func TestParseOrdersHTML_PhysicalOrderType(t *testing.T) {
+	fixtureData, err := os.ReadFile(filepath.Join("..", "..", "testdata", "orders", "order_list_sample.html"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture file: %v", err)
+	}
+
+	orders, err := parseOrdersHTML(fixtureData, marketplace.Default())
+	if err != nil {
+		t.Fatalf("parseOrdersHTML failed: %v", err)
+	}
+	for _, order := range orders {
+		if order.Type != models.OrderTypePhysical {
+			t.Errorf("Expected order %s to be physical, got %q", order.OrderID, order.Type)
+		}
+	}
+}
+
+func TestGetOrder_DigitalOrder(t *testing.T) {
+	fixtureData, err := os.ReadFile(filepath.Join("..", "..", "testdata", "orders", "digital_order_detail_sample.html"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture file: %v", err)
+	}
+
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		_, _ = w.Write(fixtureData)
+	}))
+	defer server.Close()
+
+	order, err := newSyncTestClient(server.URL).GetOrder("D01-1234567-7654321")
+	if err != nil {
+		t.Fatalf("GetOrder() error = %v", err)
+	}
+
+	if path != digitalOrderDetailPath {
+		t.Errorf("Expected the digital order summary page, got %s", path)
+	}
+	if order.Type != models.OrderTypeDigital || order.Status != models.OrderStatusDelivered || order.Date != "2026-03-03" {
+		t.Errorf("Unexpected order: %+v", order)
+	}
+	if len(order.Items) != 1 {
+		t.Fatalf("Expected 1 item, got %+v", order.Items)
+	}
+	if item := order.Items[0]; item.Format != "Kindle Edition" || item.DeliveredTo != "Alex's Kindle Paperwhite" || item.Seller != "Amazon.com Services LLC" {
+		t.Errorf("Unexpected item: %+v", item)
+	}
+}
+
+func TestListOrders_ByType(t *testing.T) {
+	physical := generateOrders("111", time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), 3)
+	digital := generateOrders("D01", time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC), 3)
+	server := digitalHistoryServer(t, map[string][]models.Order{"": physical}, map[string][]models.Order{"": digital})
+	defer server.Close()
+	client := newSyncTestClient(server.URL)
+
+	response, err := client.ListOrders(OrderQuery{})
+	if err != nil {
+		t.Fatalf("ListOrders() error = %v", err)
+	}
+	if response.TotalCount != 3 || response.Orders[0].Type != models.OrderTypePhysical {
+		t.Errorf("Expected the physical orders by default, got %+v", response.Orders)
+	}
+
+	response, err = client.ListOrders(OrderQuery{Type: models.OrderTypeDigital})
+	if err != nil {
+		t.Fatalf("ListOrders() error = %v", err)
+	}
+	if response.TotalCount != 3 || response.Orders[0].OrderID != digital[0].OrderID || response.Orders[0].Type != models.OrderTypeDigital {
+		t.Errorf("Expected the digital orders, got %+v", response.Orders)
+	}
+
+	// All orders are merged newest first, physical first on the same day, and the
+	// limit applies to the merged list
+	response, err = client.ListOrders(OrderQuery{Type: OrderTypeAll, Limit: 4})
+	if err != nil {
+		t.Fatalf("ListOrders() error = %v", err)
+	}
+	var ids []string
+	for _, order := range response.Orders {
+		ids = append(ids, order.OrderID)
+	}
+	expected := []string{digital[0].OrderID, physical[0].OrderID, digital[1].OrderID, physical[1].OrderID}
+	if strings.Join(ids, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v, got %v", expected, ids)
+	}
+
+	// Walking all orders visits the same merged list
+	var walked []string
+	err = client.WalkOrders(OrderQuery{Type: OrderTypeAll, Limit: 4}, func(order models.Order) error {
+		walked = append(walked, order.OrderID)
+		return nil
+	})
+	if err != nil || strings.Join(walked, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected WalkOrders to visit %v, got %v (%v)", expected, walked, err)
+	}
+}
+
+func TestWalkOrders_AllStreamsBothHistories(t *testing.T) {
+	physical := generateOrders("111", time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), 3*orderHistoryPageSize)
+	digital := generateOrders("D01", time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC), 3*orderHistoryPageSize)
+	history := digitalHistoryServer(t, map[string][]models.Order{"": physical}, map[string][]models.Order{"": digital})
+	defer history.Close()
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		history.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	client := newSyncTestClient(server.URL)
+
+	// The first orders arrive after one page of each history, not after both are read
+	var firstRequests, walked int
+	err := client.WalkOrders(OrderQuery{Type: OrderTypeAll}, func(order models.Order) error {
+		if walked == 0 {
+			firstRequests = requests
+		}
+		walked++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WalkOrders() error = %v", err)
+	}
+	if firstRequests != 2 {
+		t.Errorf("Expected the first order after 2 page requests, got %d", firstRequests)
+	}
+	if walked != len(physical)+len(digital) {
+		t.Errorf("Expected %d orders, got %d", len(physical)+len(digital), walked)
+	}
+}
+
+func TestGetOrderHistoryByType(t *testing.T) {
+	physical := generateOrders("111", time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), 2)
+	digital := generateOrders("D01", time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), 2)
+	server := digitalHistoryServer(t, map[string][]models.Order{"year-2026": physical}, map[string][]models.Order{"year-2026": digital})
+	defer server.Close()
+	client := newSyncTestClient(server.URL)
+
+	response, err := client.GetOrderHistoryByType(2026, models.OrderTypeDigital)
+	if err != nil {
+		t.Fatalf("GetOrderHistoryByType() error = %v", err)
+	}
+	if response.TotalCount != 2 || response.Orders[0].OrderID != digital[0].OrderID {
+		t.Errorf("Expected the digital orders of 2026, got %+v", response.Orders)
+	}
+
+	response, err = client.GetOrderHistoryByType(2026, OrderTypeAll)
+	if err != nil {
+		t.Fatalf("GetOrderHistoryByType() error = %v", err)
+	}
+	if response.TotalCount != 4 || response.Orders[1].OrderID != physical[1].OrderID || response.Orders[2].OrderID != digital[0].OrderID {
+		t.Errorf("Expected physical and digital orders merged by date, got %+v", response.Orders)
+	}
+
+	if _, err := client.GetOrderHistoryByType(2026, "kindle"); err == nil {
+		t.Error("Expected an error for an unsupported order type")
+	}
+}
+
+func TestValidateOrderType(t *testing.T) {
+	for _, orderType := range []string{"", "physical", "digital", "all"} {
+		if err := ValidateOrderType(orderType); err != nil {
+			t.Errorf("ValidateOrderType(%q) error = %v", orderType, err)
+		}
+	}
+	if err := ValidateOrderType("ebooks"); err == nil || !strings.Contains(err.Error(), "physical, digital, all") {
+		t.Errorf("Expected an error listing the order types, got %v", err)
+	}
+}
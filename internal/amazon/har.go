@@ -0,0 +1,381 @@
+package amazon
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// harRedacted replaces the value of sensitive headers and cookies in HAR output
+const harRedacted = "REDACTED"
+
+// attemptContextKey is the context key Client.Do uses to tag retry attempts
+type attemptContextKey struct{}
+
+// withAttempt returns a copy of req tagged with the given retry attempt number
+func withAttempt(req *http.Request, attempt int) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), attemptContextKey{}, attempt))
+}
+
+// attemptFromRequest returns the retry attempt number recorded by Client.Do
+func attemptFromRequest(req *http.Request) int {
+	if attempt, ok := req.Context().Value(attemptContextKey{}).(int); ok {
+		return attempt
+	}
+	return 0
+}
+
+// HAR 1.2 document structures (http://www.softwareishard.com/blog/har-12-spec/)
+type harDocument struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Attempt         int         `json:"_attempt"`
+	Error           string      `json:"_error,omitempty"`
+	Comment         string      `json:"comment,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+type harContent struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	SSL     float64 `json:"ssl"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// HARTransport is an http.RoundTripper that logs every request it executes,
+// including each retry made by Client.Do, to an HTTP Archive 1.2 file.
+// Cookies and credential headers are redacted. Each entry is appended to the
+// file in place of the archive's closing brackets, so the file stays a complete
+// archive even if the command exits early, and each request costs one write of
+// its own entry rather than a rewrite of the whole archive.
+type HARTransport struct {
+	file *os.File
+	base http.RoundTripper
+	// tail is the offset of the closing brackets that the next entry replaces
+	tail    int64
+	entries int
+	mu      sync.Mutex
+}
+
+// harTail closes the entries array and the document after the last entry
+const harTail = "\n    ]\n  }\n}\n"
+
+// NewHARTransport creates a HARTransport writing to path and forwarding
+// requests to base. If base is nil, http.DefaultTransport is used.
+func NewHARTransport(path string, base http.RoundTripper, version string) (*HARTransport, error) {
+	if path == "" {
+		return nil, fmt.Errorf("HAR file path cannot be empty")
+	}
+
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return nil, fmt.Errorf("failed to create HAR directory: %w", err)
+		}
+	}
+
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	if version == "" {
+		version = "dev"
+	}
+
+	creator, err := json.Marshal(harCreator{Name: "amazon-cli", Version: version})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal HAR: %w", err)
+	}
+	head := fmt.Sprintf("{\n  \"log\": {\n    \"version\": \"1.2\",\n    \"creator\": %s,\n    \"entries\": [", creator)
+
+	// Write an empty archive up front so the file exists even without traffic
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HAR file: %w", err)
+	}
+	if _, err := file.WriteString(head + harTail); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to write HAR file: %w", err)
+	}
+
+	return &HARTransport{
+		file: file,
+		base: base,
+		tail: int64(len(head)),
+	}, nil
+}
+
+// Close closes the archive file; entries already written stay in it
+func (t *HARTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.file.Close()
+}
+
+// RoundTrip executes the request and appends a HAR entry describing it
+func (t *HARTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var (
+		dnsStart, dnsDone         time.Time
+		connectStart, connectDone time.Time
+		tlsStart, tlsDone         time.Time
+		gotConn, wroteRequest     time.Time
+		firstByte                 time.Time
+	)
+
+	trace := &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { dnsStart = time.Now() },
+		DNSDone:              func(httptrace.DNSDoneInfo) { dnsDone = time.Now() },
+		ConnectStart:         func(string, string) { connectStart = time.Now() },
+		ConnectDone:          func(string, string, error) { connectDone = time.Now() },
+		TLSHandshakeStart:    func() { tlsStart = time.Now() },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { tlsDone = time.Now() },
+		GotConn:              func(httptrace.GotConnInfo) { gotConn = time.Now() },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { wroteRequest = time.Now() },
+		GotFirstResponseByte: func() { firstByte = time.Now() },
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+
+	entry := harEntry{
+		StartedDateTime: start.UTC().Format(time.RFC3339Nano),
+		Request:         harRequestFrom(req),
+		Attempt:         attemptFromRequest(req),
+	}
+
+	if err != nil {
+		entry.Error = err.Error()
+		entry.Time = millis(start, time.Now())
+		entry.Timings = harTimings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1, Send: 0, Wait: 0, Receive: 0}
+		t.append(entry)
+		return nil, err
+	}
+
+	// The request went out in the protocol the response came back in (e.g. HTTP/2.0),
+	// which http.NewRequest doesn't know when it sets req.Proto
+	if resp.Proto != "" {
+		entry.Request.HTTPVersion = resp.Proto
+	}
+
+	// Read the body to measure receive time and capture content
+	body, readErr := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	end := time.Now()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	entry.Response = harResponseFrom(resp, body)
+	entry.Time = millis(start, end)
+	entry.Timings = harTimings{
+		Blocked: optionalMillis(start, gotConn),
+		DNS:     optionalMillis(dnsStart, dnsDone),
+		Connect: optionalMillis(connectStart, connectDone),
+		SSL:     optionalMillis(tlsStart, tlsDone),
+		Send:    0,
+		Wait:    0,
+		Receive: 0,
+	}
+	if !wroteRequest.IsZero() && !gotConn.IsZero() {
+		entry.Timings.Send = millis(gotConn, wroteRequest)
+	}
+	if !firstByte.IsZero() {
+		if !wroteRequest.IsZero() {
+			entry.Timings.Wait = millis(wroteRequest, firstByte)
+		}
+		entry.Timings.Receive = millis(firstByte, end)
+	}
+
+	// Keep the entry with whatever body arrived when reading the rest failed
+	if readErr != nil {
+		entry.Error = readErr.Error()
+		entry.Comment = "failed to read response body: " + readErr.Error()
+		t.append(entry)
+		return nil, fmt.Errorf("failed to read response body for HAR: %w", readErr)
+	}
+
+	t.append(entry)
+	return resp, nil
+}
+
+// append writes an entry over the archive's closing brackets and closes the
+// archive again after it. Writes are serialized so entries never interleave.
+func (t *HARTransport) append(entry harEntry) {
+	// A failure to write the archive must not fail the command itself
+	data, err := json.MarshalIndent(entry, "      ", "  ")
+	if err != nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	separator := "\n      "
+	if t.entries > 0 {
+		separator = ",\n      "
+	}
+	chunk := separator + string(data)
+	if _, err := t.file.WriteAt([]byte(chunk+harTail), t.tail); err != nil {
+		return
+	}
+	t.tail += int64(len(chunk))
+	t.entries++
+}
+
+// harRequestFrom converts an http.Request into its redacted HAR representation
+func harRequestFrom(req *http.Request) harRequest {
+	r := harRequest{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		HTTPVersion: req.Proto,
+		Cookies:     []harNameValue{},
+		Headers:     harHeaders(req.Header),
+		QueryString: []harNameValue{},
+		HeadersSize: -1,
+		BodySize:    req.ContentLength,
+	}
+
+	if r.HTTPVersion == "" {
+		r.HTTPVersion = "HTTP/1.1"
+	}
+
+	for _, cookie := range req.Cookies() {
+		r.Cookies = append(r.Cookies, harNameValue{Name: cookie.Name, Value: harRedacted})
+	}
+
+	for name, values := range req.URL.Query() {
+		for _, value := range values {
+			r.QueryString = append(r.QueryString, harNameValue{Name: name, Value: value})
+		}
+	}
+
+	return r
+}
+
+// harResponseFrom converts an http.Response into its redacted HAR representation
+func harResponseFrom(resp *http.Response, body []byte) harResponse {
+	r := harResponse{
+		Status:      resp.StatusCode,
+		StatusText:  http.StatusText(resp.StatusCode),
+		HTTPVersion: resp.Proto,
+		Cookies:     []harNameValue{},
+		Headers:     harHeaders(resp.Header),
+		Content: harContent{
+			Size:     int64(len(body)),
+			MimeType: resp.Header.Get("Content-Type"),
+			Text:     string(scrubPII(body)),
+		},
+		RedirectURL: resp.Header.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    int64(len(body)),
+	}
+
+	if r.HTTPVersion == "" {
+		r.HTTPVersion = "HTTP/1.1"
+	}
+
+	for _, cookie := range resp.Cookies() {
+		r.Cookies = append(r.Cookies, harNameValue{Name: cookie.Name, Value: harRedacted})
+	}
+
+	return r
+}
+
+// harHeaders converts headers to HAR name/value pairs, redacting sensitive values
+func harHeaders(h http.Header) []harNameValue {
+	headers := []harNameValue{}
+	for name, values := range h {
+		for _, value := range values {
+			if isSensitiveHeader(name) {
+				value = harRedacted
+			}
+			headers = append(headers, harNameValue{Name: name, Value: value})
+		}
+	}
+	return headers
+}
+
+// isSensitiveHeader reports whether a header carries cookies or credentials
+func isSensitiveHeader(name string) bool {
+	for _, sensitive := range sensitiveHeaders {
+		if strings.EqualFold(name, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// millis returns the duration between two times in milliseconds
+func millis(from, to time.Time) float64 {
+	return float64(to.Sub(from)) / float64(time.Millisecond)
+}
+
+// optionalMillis returns the duration in milliseconds, or -1 if the phase did not occur
+func optionalMillis(from, to time.Time) float64 {
+	if from.IsZero() || to.IsZero() {
+		return -1
+	}
+	return millis(from, to)
+}
@@ -0,0 +1,319 @@
+package amazon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func readHAR(t *testing.T, path string) harDocument {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read HAR file: %v", err)
+	}
+
+	var doc harDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("HAR file is not valid JSON: %v", err)
+	}
+	return doc
+}
+
+func TestNewHARTransport_WritesEmptyArchive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.har")
+	if _, err := NewHARTransport(path, nil, "1.0.0"); err != nil {
+		t.Fatalf("NewHARTransport() error = %v", err)
+	}
+
+	doc := readHAR(t, path)
+	if doc.Log.Version != "1.2" {
+		t.Errorf("expected HAR version 1.2, got %s", doc.Log.Version)
+	}
+	if doc.Log.Creator.Name != "amazon-cli" || doc.Log.Creator.Version != "1.0.0" {
+		t.Errorf("unexpected creator: %+v", doc.Log.Creator)
+	}
+	if len(doc.Log.Entries) != 0 {
+		t.Errorf("expected no entries, got %d", len(doc.Log.Entries))
+	}
+}
+
+func TestHARTransport_RedactsCookiesAndHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session-token", Value: "server-secret"})
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("<html>ok</html>"))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "trace.har")
+	rt, err := NewHARTransport(path, nil, "")
+	if err != nil {
+		t.Fatalf("NewHARTransport() error = %v", err)
+	}
+
+	req, _ := http.NewRequest("GET", server.URL+"/s?k=coffee", nil)
+	req.Header.Set("Cookie", "session-token=client-secret")
+	req.Header.Set("Authorization", "Bearer client-token")
+	resp, err := (&http.Client{Transport: rt}).Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+
+	data, _ := os.ReadFile(path)
+	for _, secret := range []string{"server-secret", "client-secret", "client-token"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("HAR file should not contain %q", secret)
+		}
+	}
+
+	doc := readHAR(t, path)
+	if len(doc.Log.Entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(doc.Log.Entries))
+	}
+
+	entry := doc.Log.Entries[0]
+	if entry.Request.Method != "GET" {
+		t.Errorf("expected method GET, got %s", entry.Request.Method)
+	}
+	if len(entry.Request.QueryString) != 1 || entry.Request.QueryString[0].Value != "coffee" {
+		t.Errorf("unexpected query string: %+v", entry.Request.QueryString)
+	}
+	if entry.Response.Status != http.StatusOK {
+		t.Errorf("expected status 200, got %d", entry.Response.Status)
+	}
+	if entry.Response.Content.Text != "<html>ok</html>" {
+		t.Errorf("unexpected content: %q", entry.Response.Content.Text)
+	}
+	if len(entry.Response.Cookies) != 1 || entry.Response.Cookies[0].Value != harRedacted {
+		t.Errorf("expected redacted response cookie, got %+v", entry.Response.Cookies)
+	}
+	if entry.Time < 0 {
+		t.Errorf("expected non-negative total time, got %f", entry.Time)
+	}
+}
+
+func TestHARTransport_RecordsRetriesFromDo(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "trace.har")
+	client := NewClient()
+	rt, err := NewHARTransport(path, client.Transport(), "")
+	if err != nil {
+		t.Fatalf("NewHARTransport() error = %v", err)
+	}
+	client.SetTransport(rt)
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+
+	doc := readHAR(t, path)
+	if len(doc.Log.Entries) != 2 {
+		t.Fatalf("expected 2 entries (original + retry), got %d", len(doc.Log.Entries))
+	}
+	if doc.Log.Entries[0].Attempt != 0 || doc.Log.Entries[0].Response.Status != http.StatusServiceUnavailable {
+		t.Errorf("unexpected first entry: attempt=%d status=%d", doc.Log.Entries[0].Attempt, doc.Log.Entries[0].Response.Status)
+	}
+	if doc.Log.Entries[1].Attempt != 1 || doc.Log.Entries[1].Response.Status != http.StatusOK {
+		t.Errorf("unexpected retry entry: attempt=%d status=%d", doc.Log.Entries[1].Attempt, doc.Log.Entries[1].Response.Status)
+	}
+}
+
+func TestHARTransport_RecordsNetworkErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.har")
+	rt, err := NewHARTransport(path, nil, "")
+	if err != nil {
+		t.Fatalf("NewHARTransport() error = %v", err)
+	}
+
+	_, err = (&http.Client{Transport: rt}).Get("http://127.0.0.1:1/unreachable")
+	if err == nil {
+		t.Fatal("expected network error")
+	}
+
+	doc := readHAR(t, path)
+	if len(doc.Log.Entries) != 1 || doc.Log.Entries[0].Error == "" {
+		t.Errorf("expected failed entry with error, got %+v", doc.Log.Entries)
+	}
+}
+
+func TestHARTransport_ConcurrentRequestsKeepEveryEntry(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("<html>ok</html>"))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "trace.har")
+	rt, err := NewHARTransport(path, nil, "1.0.0")
+	if err != nil {
+		t.Fatalf("NewHARTransport() error = %v", err)
+	}
+
+	const requests = 20
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := (&http.Client{Transport: rt}).Get(fmt.Sprintf("%s/page/%d", server.URL, i))
+			if err != nil {
+				t.Errorf("Get() error = %v", err)
+				return
+			}
+			resp.Body.Close()
+		}(i)
+	}
+	wg.Wait()
+
+	doc := readHAR(t, path)
+	if len(doc.Log.Entries) != requests {
+		t.Errorf("expected %d entries after concurrent requests, got %d", requests, len(doc.Log.Entries))
+	}
+
+	files, _ := os.ReadDir(dir)
+	if len(files) != 1 {
+		t.Errorf("expected only the HAR file in %s, got %d files", dir, len(files))
+	}
+}
+
+func TestHARTransport_RecordsNegotiatedProtocol(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "trace.har")
+	rt, err := NewHARTransport(path, server.Client().Transport, "")
+	if err != nil {
+		t.Fatalf("NewHARTransport() error = %v", err)
+	}
+	defer rt.Close()
+
+	resp, err := (&http.Client{Transport: rt}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+
+	doc := readHAR(t, path)
+	if len(doc.Log.Entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(doc.Log.Entries))
+	}
+	entry := doc.Log.Entries[0]
+	if entry.Request.HTTPVersion != "HTTP/2.0" || entry.Response.HTTPVersion != "HTTP/2.0" {
+		t.Errorf("expected HTTP/2.0 request and response, got %s and %s", entry.Request.HTTPVersion, entry.Response.HTTPVersion)
+	}
+}
+
+// failingBody returns some content and then a read error
+type failingBody struct{ read bool }
+
+func (b *failingBody) Read(p []byte) (int, error) {
+	if b.read {
+		return 0, fmt.Errorf("connection reset by peer")
+	}
+	b.read = true
+	return copy(p, "<html>partial"), nil
+}
+
+func (b *failingBody) Close() error { return nil }
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestHARTransport_RecordsBodyReadErrors(t *testing.T) {
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Proto: "HTTP/1.1", Header: http.Header{}, Body: &failingBody{}, Request: req}, nil
+	})
+
+	path := filepath.Join(t.TempDir(), "trace.har")
+	rt, err := NewHARTransport(path, base, "")
+	if err != nil {
+		t.Fatalf("NewHARTransport() error = %v", err)
+	}
+	defer rt.Close()
+
+	req, _ := http.NewRequest("GET", "https://www.amazon.com/gp/your-account/order-history", nil)
+	if _, err := rt.RoundTrip(req); err == nil {
+		t.Fatal("expected the body read error to be returned")
+	}
+
+	doc := readHAR(t, path)
+	if len(doc.Log.Entries) != 1 {
+		t.Fatalf("expected the failed read to be recorded, got %d entries", len(doc.Log.Entries))
+	}
+	entry := doc.Log.Entries[0]
+	if !strings.Contains(entry.Comment, "connection reset by peer") || entry.Error == "" {
+		t.Errorf("expected an error comment, got comment=%q error=%q", entry.Comment, entry.Error)
+	}
+	if entry.Response.Status != http.StatusOK || entry.Response.Content.Text != "<html>partial" {
+		t.Errorf("expected the partial response, got %+v", entry.Response)
+	}
+}
+
+func TestHARTransport_AppendsWithoutRewriting(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<html>ok</html>"))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "trace.har")
+	rt, err := NewHARTransport(path, nil, "")
+	if err != nil {
+		t.Fatalf("NewHARTransport() error = %v", err)
+	}
+	defer rt.Close()
+	client := &http.Client{Transport: rt}
+
+	get := func(page string) {
+		resp, err := client.Get(server.URL + page)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	get("/first")
+	first, _ := os.ReadFile(path)
+	get("/second")
+	second, _ := os.ReadFile(path)
+
+	// The first entry is left in place; only the closing brackets are overwritten
+	prefix := first[:len(first)-len(harTail)]
+	if !strings.HasPrefix(string(second), string(prefix)) {
+		t.Error("expected the second entry to be appended after the first")
+	}
+
+	doc := readHAR(t, path)
+	if len(doc.Log.Entries) != 2 || !strings.HasSuffix(doc.Log.Entries[1].Request.URL, "/second") {
+		t.Errorf("expected two entries in request order, got %+v", doc.Log.Entries)
+	}
+}
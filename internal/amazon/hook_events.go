@@ -0,0 +1,143 @@
+package amazon
+
+import "github.com/zkwentz/amazon-cli/pkg/models"
+
+// WithNotifier sets a function that receives the changes detected by SyncOrders and
+// the tracking watchers, such as status transitions and new tracking events
+func WithNotifier(fn func(models.HookEvent)) Option {
+	return func(c *Client) {
+		c.notifier = fn
+	}
+}
+
+// notify sends events to the notifier, if one is set
+func (c *Client) notify(source string, events []models.HookEvent) {
+	if c.notifier == nil {
+		return
+	}
+	for _, event := range events {
+		event.Source = source
+		event.Time = c.now()
+		c.notifier(event)
+	}
+}
+
+// orderChangeEvents compares a stored order with its freshly synced copy and returns
+// the status and tracking changes. Packages are compared by shipment ID when both
+// copies identify their packages; otherwise the order-level tracking is compared.
+func orderChangeEvents(stored, synced models.Order) []models.HookEvent {
+	var events []models.HookEvent
+	order := &synced
+
+	if stored.Status != "" && synced.Status != "" && stored.Status != synced.Status {
+		events = append(events, models.HookEvent{
+			Type:     models.HookEventStatusChanged,
+			OrderID:  synced.OrderID,
+			Previous: stored.Status,
+			Current:  synced.Status,
+			Order:    order,
+			Tracking: synced.Tracking,
+		})
+	}
+
+	storedShipments := make(map[string]models.Shipment)
+	for _, shipment := range stored.Shipments {
+		if shipment.ShipmentID != "" {
+			storedShipments[shipment.ShipmentID] = shipment
+		}
+	}
+
+	compared := false
+	for _, shipment := range synced.Shipments {
+		previous, exists := storedShipments[shipment.ShipmentID]
+		if shipment.ShipmentID == "" || !exists {
+			continue
+		}
+		compared = true
+
+		if previous.Status != "" && shipment.Status != "" && previous.Status != shipment.Status {
+			events = append(events, models.HookEvent{
+				Type:       models.HookEventStatusChanged,
+				OrderID:    synced.OrderID,
+				ShipmentID: shipment.ShipmentID,
+				Previous:   previous.Status,
+				Current:    shipment.Status,
+				Order:      order,
+				Tracking:   shipment.Tracking,
+			})
+		}
+		for _, event := range trackingChangeEvents(synced.OrderID, shipment.ShipmentID, previous.Tracking, shipment.Tracking) {
+			if event.Type != models.HookEventStatusChanged {
+				event.Order = order
+				events = append(events, event)
+			}
+		}
+	}
+
+	if !compared {
+		for _, event := range trackingChangeEvents(synced.OrderID, "", stored.Tracking, synced.Tracking) {
+			if event.Type != models.HookEventStatusChanged {
+				event.Order = order
+				events = append(events, event)
+			}
+		}
+	}
+
+	return events
+}
+
+// trackingChangeEvents compares two tracking snapshots of a package and returns its
+// status change, delivery date change and new tracking events, in that order
+func trackingChangeEvents(orderID, shipmentID string, previous, current *models.Tracking) []models.HookEvent {
+	if previous == nil || current == nil {
+		return nil
+	}
+
+	var events []models.HookEvent
+	if previous.Status != "" && current.Status != "" && previous.Status != current.Status {
+		events = append(events, models.HookEvent{
+			Type:       models.HookEventStatusChanged,
+			OrderID:    orderID,
+			ShipmentID: shipmentID,
+			Previous:   previous.Status,
+			Current:    current.Status,
+			Tracking:   current,
+		})
+	}
+	if current.DeliveryDate != "" && previous.DeliveryDate != current.DeliveryDate {
+		events = append(events, models.HookEvent{
+			Type:       models.HookEventDeliveryDateChanged,
+			OrderID:    orderID,
+			ShipmentID: shipmentID,
+			Previous:   previous.DeliveryDate,
+			Current:    current.DeliveryDate,
+			Tracking:   current,
+		})
+	}
+
+	seen := make(map[string]bool)
+	for _, event := range previous.Events {
+		seen[trackingEventKey(event)] = true
+	}
+	for _, event := range current.Events {
+		if seen[trackingEventKey(event)] {
+			continue
+		}
+		event := event
+		events = append(events, models.HookEvent{
+			Type:          models.HookEventTrackingEvent,
+			OrderID:       orderID,
+			ShipmentID:    shipmentID,
+			Current:       current.Status,
+			Tracking:      current,
+			TrackingEvent: &event,
+		})
+	}
+
+	return events
+}
+
+// trackingEventKey identifies a tracking event across polls
+func trackingEventKey(event models.TrackingEvent) string {
+	return event.Timestamp + "|" + event.Location + "|" + event.Status
+}
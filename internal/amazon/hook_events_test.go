@@ -0,0 +1,133 @@
+package amazon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+func TestTrackingChangeEvents(t *testing.T) {
+	previous := &models.Tracking{
+		Status:       "in transit",
+		DeliveryDate: "2026-03-05",
+		Events:       []models.TrackingEvent{{Location: "Seattle, WA", Status: "Shipped"}},
+	}
+	current := &models.Tracking{
+		Status:       "delayed",
+		DeliveryDate: "2026-03-07",
+		Events: []models.TrackingEvent{
+			{Location: "Seattle, WA", Status: "Shipped"},
+			{Location: "Portland, OR", Status: "Weather delay"},
+		},
+	}
+
+	events := trackingChangeEvents("111-2222222-3333333", "DmF7kq3Lz", previous, current)
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events, got %+v", events)
+	}
+	if e := events[0]; e.Type != models.HookEventStatusChanged || e.Previous != "in transit" || e.Current != "delayed" || e.ShipmentID != "DmF7kq3Lz" {
+		t.Errorf("Unexpected status event: %+v", e)
+	}
+	if e := events[1]; e.Type != models.HookEventDeliveryDateChanged || e.Previous != "2026-03-05" || e.Current != "2026-03-07" {
+		t.Errorf("Unexpected delivery date event: %+v", e)
+	}
+	if e := events[2]; e.Type != models.HookEventTrackingEvent || e.TrackingEvent == nil || e.TrackingEvent.Status != "Weather delay" {
+		t.Errorf("Unexpected tracking event: %+v", e)
+	}
+
+	if events := trackingChangeEvents("111-2222222-3333333", "", nil, current); len(events) != 0 {
+		t.Errorf("Expected no events without a previous snapshot, got %+v", events)
+	}
+	if events := trackingChangeEvents("111-2222222-3333333", "", current, current); len(events) != 0 {
+		t.Errorf("Expected no events for an unchanged snapshot, got %+v", events)
+	}
+}
+
+func TestOrderChangeEvents(t *testing.T) {
+	stored := models.Order{
+		OrderID: "114-3141592-6535897",
+		Status:  models.OrderStatusPending,
+		Shipments: []models.Shipment{
+			{ShipmentID: "A", Status: models.OrderStatusPending, Tracking: &models.Tracking{Carrier: "UPS", DeliveryDate: "2026-03-05"}},
+			{ShipmentID: "B", Status: models.OrderStatusPending, Tracking: &models.Tracking{Carrier: "USPS", DeliveryDate: "2026-03-06"}},
+		},
+	}
+	synced := models.Order{
+		OrderID: "114-3141592-6535897",
+		Status:  models.OrderStatusDelayed,
+		Shipments: []models.Shipment{
+			{ShipmentID: "A", Status: models.OrderStatusDelivered, Tracking: &models.Tracking{Carrier: "UPS", DeliveryDate: "2026-03-05"}},
+			{ShipmentID: "B", Status: models.OrderStatusDelayed, Tracking: &models.Tracking{Carrier: "USPS", DeliveryDate: "2026-03-09"}},
+		},
+	}
+
+	events := orderChangeEvents(stored, synced)
+
+	expected := []struct {
+		typ        string
+		shipmentID string
+		current    string
+	}{
+		{models.HookEventStatusChanged, "", models.OrderStatusDelayed},
+		{models.HookEventStatusChanged, "A", models.OrderStatusDelivered},
+		{models.HookEventStatusChanged, "B", models.OrderStatusDelayed},
+		{models.HookEventDeliveryDateChanged, "B", "2026-03-09"},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %+v", len(expected), events)
+	}
+	for i, want := range expected {
+		got := events[i]
+		if got.Type != want.typ || got.ShipmentID != want.shipmentID || got.Current != want.current {
+			t.Errorf("Event %d: expected %+v, got %+v", i, want, got)
+		}
+		if got.Order == nil || got.Order.OrderID != synced.OrderID {
+			t.Errorf("Event %d: expected the synced order as payload", i)
+		}
+	}
+
+	// Without identified packages the order-level tracking is compared
+	stored = models.Order{OrderID: "111-2222222-3333333", Status: models.OrderStatusPending, Tracking: &models.Tracking{DeliveryDate: "2026-03-05"}}
+	synced = models.Order{OrderID: "111-2222222-3333333", Status: models.OrderStatusPending, Tracking: &models.Tracking{DeliveryDate: "2026-03-04"}}
+	events = orderChangeEvents(stored, synced)
+	if len(events) != 1 || events[0].Type != models.HookEventDeliveryDateChanged || events[0].Previous != "2026-03-05" {
+		t.Errorf("Expected an order-level delivery date change, got %+v", events)
+	}
+}
+
+func TestWatchOrderTracking_Notifies(t *testing.T) {
+	server, _ := trackingSequenceServer(
+		trackingPage("Shipped", "Package left the facility"),
+		trackingPage("Delivered", "Package left the facility", "Delivered to front door"),
+	)
+	defer server.Close()
+
+	var notified []models.HookEvent
+	client := newSyncTestClient(server.URL)
+	WithNotifier(func(event models.HookEvent) {
+		notified = append(notified, event)
+	})(client)
+
+	_, err := client.WatchOrderTracking(context.Background(), "123-4567890-1234567", time.Millisecond, func(models.TrackingUpdate) error { return nil })
+	if err != nil {
+		t.Fatalf("WatchOrderTracking() error = %v", err)
+	}
+
+	// The first poll is the baseline; the second brings a status change and an event
+	if len(notified) != 2 {
+		t.Fatalf("Expected 2 notifications, got %+v", notified)
+	}
+	if notified[0].Type != models.HookEventStatusChanged || notified[0].Previous != "shipped" || notified[0].Current != "delivered" {
+		t.Errorf("Unexpected status notification: %+v", notified[0])
+	}
+	if notified[1].Type != models.HookEventTrackingEvent || notified[1].TrackingEvent.Status != "Delivered to front door" {
+		t.Errorf("Unexpected tracking notification: %+v", notified[1])
+	}
+	for _, event := range notified {
+		if event.Source != models.HookSourceWatch || event.Time.IsZero() {
+			t.Errorf("Expected watch source and a timestamp, got %+v", event)
+		}
+	}
+}
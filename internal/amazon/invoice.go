@@ -0,0 +1,246 @@
+package amazon
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/zkwentz/amazon-cli/internal/atomicfile"
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+// invoiceBlockSelector lists the elements rendered as separate lines of a plain-text receipt
+const invoiceBlockSelector = "h1, h2, h3, h4, h5, h6, p, li, tr, div, center, table, tbody"
+
+// whitespaceRegex matches runs of whitespace collapsed in plain-text receipts
+var whitespaceRegex = regexp.MustCompile(`\s+`)
+
+// Invoice is the printable invoice page of an order
+type Invoice struct {
+	OrderID string
+	URL     string
+	HTML    []byte
+}
+
+// GetInvoice fetches the printable invoice page of an order
+func (c *Client) GetInvoice(orderID string) (*Invoice, error) {
+	if !c.marketplace.ValidOrderID(orderID) {
+		return nil, fmt.Errorf("invalid order ID format: expected XXX-XXXXXXX-XXXXXXX, got %s", orderID)
+	}
+
+	pageURL := invoiceURL(c.baseURL, orderID)
+	req, err := http.NewRequest("GET", pageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch invoice: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("invoice not found for order %s", orderID)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	body := &bytes.Buffer{}
+	if _, err := body.ReadFrom(resp.Body); err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if c.detectCAPTCHA(body.Bytes()) {
+		return nil, fmt.Errorf("CAPTCHA detected - Amazon is blocking automated access")
+	}
+
+	// Amazon serves a generic page instead of a 404 for orders that aren't on the account
+	if !bytes.Contains(body.Bytes(), []byte(orderID)) {
+		return nil, fmt.Errorf("invoice not found for order %s", orderID)
+	}
+
+	return &Invoice{
+		OrderID: orderID,
+		URL:     pageURL,
+		HTML:    body.Bytes(),
+	}, nil
+}
+
+// SaveInvoice downloads an order's invoice to path as HTML. With text, a plain-text
+// receipt is written next to it with a .txt extension.
+func (c *Client) SaveInvoice(orderID, path string, text bool) (*models.InvoiceFile, error) {
+	invoice, err := c.GetInvoice(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &models.InvoiceFile{
+		OrderID: orderID,
+		URL:     invoice.URL,
+		Path:    path,
+	}
+	if err := writeFileAtomic(path, invoice.HTML); err != nil {
+		return nil, err
+	}
+
+	if text {
+		file.TextPath = invoiceTextPath(path)
+		if err := writeInvoiceText(file.TextPath, invoice.HTML); err != nil {
+			return nil, err
+		}
+	}
+
+	return file, nil
+}
+
+// DownloadInvoices saves the invoices of every order placed in a year to dir, named
+// <date>_<order-id>.html. Invoices already in dir are skipped, so an interrupted
+// download can be rerun. A failed invoice is recorded and the remaining orders continue.
+func (c *Client) DownloadInvoices(year int, dir string, text bool) (*models.InvoicesResponse, error) {
+	if year <= 0 {
+		year = c.now().Year()
+	}
+
+	history, err := c.GetOrderHistory(year)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create invoice directory: %w", err)
+	}
+
+	response := &models.InvoicesResponse{
+		Year:     year,
+		Dir:      dir,
+		Invoices: []models.InvoiceFile{},
+	}
+
+	for _, order := range history.Orders {
+		date := ""
+		if parsed, err := c.marketplace.ParseDate(order.Date); err == nil {
+			date = parsed.Format("2006-01-02")
+		}
+		path := filepath.Join(dir, invoiceFileName(date, order.OrderID))
+
+		// Skip invoices downloaded by an earlier run
+		if existing, err := os.ReadFile(path); err == nil {
+			file := models.InvoiceFile{OrderID: order.OrderID, Date: date, Path: path, Skipped: true}
+			if text {
+				file.TextPath = invoiceTextPath(path)
+				if _, err := os.Stat(file.TextPath); os.IsNotExist(err) {
+					if err := writeInvoiceText(file.TextPath, existing); err != nil {
+						return nil, err
+					}
+				}
+			}
+			response.Invoices = append(response.Invoices, file)
+			response.Skipped++
+			continue
+		}
+
+		file, err := c.SaveInvoice(order.OrderID, path, text)
+		if err != nil {
+			response.Invoices = append(response.Invoices, models.InvoiceFile{
+				OrderID: order.OrderID,
+				Date:    date,
+				Path:    path,
+				Error:   err.Error(),
+			})
+			response.Failed++
+			continue
+		}
+		file.Date = date
+		response.Invoices = append(response.Invoices, *file)
+		response.Downloaded++
+	}
+
+	return response, nil
+}
+
+// InvoiceText converts an invoice page to a plain-text receipt, one line per block
+func InvoiceText(html []byte) (string, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(html))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse HTML: %w", err)
+	}
+	doc.Find("head, script, style, noscript").Remove()
+
+	var lines []string
+	doc.Find(invoiceBlockSelector).Each(func(i int, s *goquery.Selection) {
+		// Only innermost blocks carry text of their own; outer blocks would repeat it
+		if s.Find(invoiceBlockSelector).Length() > 0 {
+			return
+		}
+
+		var line string
+		if goquery.NodeName(s) == "tr" {
+			var cells []string
+			s.Find("td, th").Each(func(j int, cell *goquery.Selection) {
+				if text := collapseWhitespace(cell.Text()); text != "" {
+					cells = append(cells, text)
+				}
+			})
+			line = strings.Join(cells, "  ")
+		} else {
+			line = collapseWhitespace(s.Text())
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	})
+
+	if len(lines) == 0 {
+		return "", nil
+	}
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// invoiceURL returns the printable invoice URL of an order
+func invoiceURL(baseURL, orderID string) string {
+	return fmt.Sprintf("%s/gp/css/summary/print.html?orderID=%s", baseURL, url.QueryEscape(orderID))
+}
+
+// invoiceFileName names an invoice file by order date and ID; the date is omitted when unknown
+func invoiceFileName(date, orderID string) string {
+	if date == "" {
+		return orderID + ".html"
+	}
+	return date + "_" + orderID + ".html"
+}
+
+// invoiceTextPath returns the plain-text receipt path for an invoice file
+func invoiceTextPath(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".txt"
+}
+
+// writeInvoiceText writes the plain-text receipt of an invoice page
+func writeInvoiceText(path string, html []byte) error {
+	text, err := InvoiceText(html)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, []byte(text))
+}
+
+// collapseWhitespace trims text and replaces whitespace runs with single spaces
+func collapseWhitespace(text string) string {
+	return strings.TrimSpace(whitespaceRegex.ReplaceAllString(text, " "))
+}
+
+// writeFileAtomic writes data through a temporary file so an interrupted download
+// never leaves a partial file that a later run would skip
+func writeFileAtomic(path string, data []byte) error {
+	if err := atomicfile.Write(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
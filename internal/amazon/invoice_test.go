@@ -0,0 +1,150 @@
+package amazon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+func TestInvoiceText(t *testing.T) {
+	fixtureData, err := os.ReadFile(filepath.Join("..", "..", "testdata", "orders", "invoice_sample.html"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+
+	text, err := InvoiceText(fixtureData)
+	if err != nil {
+		t.Fatalf("InvoiceText() error = %v", err)
+	}
+
+	expectedLines := []string{
+		"Final Details for Order #123-4567890-1234567",
+		"Order Placed:  January 15, 2024",
+		"Item(s) Subtotal:  $24.99",
+		"Grand Total:  $29.99",
+		"Payment Method: Visa ending in 4242",
+	}
+	for _, line := range expectedLines {
+		if !strings.Contains(text, line+"\n") {
+			t.Errorf("Expected receipt to contain line %q, got:\n%s", line, text)
+		}
+	}
+	if strings.Contains(text, "window.print") || strings.Contains(text, "font-family") {
+		t.Errorf("Expected scripts and styles to be dropped, got:\n%s", text)
+	}
+}
+
+func TestGetInvoice(t *testing.T) {
+	fixtureData, _ := os.ReadFile(filepath.Join("..", "..", "testdata", "orders", "invoice_sample.html"))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/gp/css/summary/print.html" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("orderID") != "123-4567890-1234567" {
+			_, _ = w.Write([]byte("<html><body>Your Orders</body></html>"))
+			return
+		}
+		_, _ = w.Write(fixtureData)
+	}))
+	defer server.Close()
+
+	client := newSyncTestClient(server.URL)
+
+	invoice, err := client.GetInvoice("123-4567890-1234567")
+	if err != nil {
+		t.Fatalf("GetInvoice() error = %v", err)
+	}
+	if !strings.HasSuffix(invoice.URL, "/gp/css/summary/print.html?orderID=123-4567890-1234567") {
+		t.Errorf("Unexpected invoice URL %s", invoice.URL)
+	}
+
+	// A page that doesn't mention the order is not its invoice
+	if _, err := client.GetInvoice("123-0000000-0000000"); err == nil || !strings.Contains(err.Error(), "invoice not found") {
+		t.Errorf("Expected invoice not found error, got %v", err)
+	}
+	if _, err := client.GetInvoice("bad-id"); err == nil {
+		t.Error("Expected error for invalid order ID")
+	}
+
+	// SaveInvoice writes the HTML and the plain-text receipt
+	path := filepath.Join(t.TempDir(), "invoice.html")
+	file, err := client.SaveInvoice("123-4567890-1234567", path, true)
+	if err != nil {
+		t.Fatalf("SaveInvoice() error = %v", err)
+	}
+	if file.TextPath != strings.TrimSuffix(path, ".html")+".txt" {
+		t.Errorf("Unexpected text path %s", file.TextPath)
+	}
+	if saved, _ := os.ReadFile(path); string(saved) != string(fixtureData) {
+		t.Error("Expected saved HTML to match the invoice page")
+	}
+	if text, _ := os.ReadFile(file.TextPath); !strings.Contains(string(text), "Grand Total:  $29.99") {
+		t.Errorf("Expected plain-text receipt, got %q", text)
+	}
+}
+
+func TestDownloadInvoices_SkipsExisting(t *testing.T) {
+	var requests []string
+	orders := generateOrders("111", time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC), 3)
+	history := orderHistoryServer(t, map[string][]models.Order{"year-2025": orders}, []int{2025}, &requests)
+	defer history.Close()
+
+	var invoiceRequests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/gp/css/summary/print.html" {
+			history.Config.Handler.ServeHTTP(w, r)
+			return
+		}
+		orderID := r.URL.Query().Get("orderID")
+		invoiceRequests = append(invoiceRequests, orderID)
+		if orderID == orders[2].OrderID {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte("<html><body><h1>Invoice " + orderID + "</h1></body></html>"))
+	}))
+	defer server.Close()
+
+	dir := filepath.Join(t.TempDir(), "invoices")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	// The first order's invoice was downloaded by an earlier run
+	existing := filepath.Join(dir, "2025-06-30_"+orders[0].OrderID+".html")
+	_ = os.WriteFile(existing, []byte("<html><body><p>Invoice "+orders[0].OrderID+"</p></body></html>"), 0600)
+
+	client := newSyncTestClient(server.URL)
+	response, err := client.DownloadInvoices(2025, dir, true)
+	if err != nil {
+		t.Fatalf("DownloadInvoices() error = %v", err)
+	}
+
+	if response.Downloaded != 1 || response.Skipped != 1 || response.Failed != 1 {
+		t.Errorf("Expected 1 downloaded, 1 skipped and 1 failed, got %+v", response)
+	}
+	for _, orderID := range invoiceRequests {
+		if orderID == orders[0].OrderID {
+			t.Error("Expected the existing invoice not to be downloaded again")
+		}
+	}
+
+	// Files are named by order date and ID
+	downloaded := filepath.Join(dir, "2025-06-29_"+orders[1].OrderID+".html")
+	if _, err := os.Stat(downloaded); err != nil {
+		t.Errorf("Expected %s to be written: %v", downloaded, err)
+	}
+	// The skipped invoice still gets its missing text receipt
+	if text, _ := os.ReadFile(strings.TrimSuffix(existing, ".html") + ".txt"); string(text) != "Invoice "+orders[0].OrderID+"\n" {
+		t.Errorf("Expected receipt for the existing invoice, got %q", text)
+	}
+	if response.Invoices[2].Error == "" {
+		t.Errorf("Expected the failed invoice to record its error, got %+v", response.Invoices[2])
+	}
+}
@@ -0,0 +1,128 @@
+package amazon
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/zkwentz/amazon-cli/internal/marketplace"
+	"github.com/zkwentz/amazon-cli/internal/orderindex"
+	"github.com/zkwentz/amazon-cli/internal/ratelimit"
+)
+
+// Option configures a Client created by NewClient
+type Option func(*Client)
+
+// Clock provides the current time. It allows tests to control time-dependent behavior.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the default Clock backed by time.Now
+type systemClock struct{}
+
+// Now returns the current wall-clock time
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// WithBaseURL sets the Amazon site the client talks to (e.g. a mock server URL in tests)
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		if baseURL != "" {
+			c.baseURL = strings.TrimRight(baseURL, "/")
+		}
+	}
+}
+
+// WithMarketplace selects the regional storefront (domain, locale and formats).
+// It also sets the base URL; apply WithBaseURL afterwards to override it.
+func WithMarketplace(m *marketplace.Marketplace) Option {
+	return func(c *Client) {
+		if m != nil {
+			c.marketplace = m
+			c.baseURL = m.BaseURL()
+		}
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for all requests
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
+	}
+}
+
+// WithRateLimiter sets the rate limiter used to pace requests and retries
+func WithRateLimiter(rl *ratelimit.RateLimiter) Option {
+	return func(c *Client) {
+		if rl != nil {
+			c.rateLimiter = rl
+		}
+	}
+}
+
+// WithCache enables caching of successful GET responses
+func WithCache(cache Cache) Option {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// WithProfile sets the account profile the client acts for. Cached responses
+// are kept apart per profile so one account's pages are never served to another.
+func WithProfile(profile string) Option {
+	return func(c *Client) {
+		c.profile = profile
+	}
+}
+
+// WithOrderIndex sets the local order index used when Amazon's order search is unavailable
+func WithOrderIndex(idx *orderindex.Index) Option {
+	return func(c *Client) {
+		c.orderIndex = idx
+	}
+}
+
+// WithClock sets the clock used for time-dependent behavior
+func WithClock(clock Clock) Option {
+	return func(c *Client) {
+		if clock != nil {
+			c.clock = clock
+		}
+	}
+}
+
+// WithLogger sets the structured logger for request diagnostics
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		if logger != nil {
+			c.logger = logger
+		}
+	}
+}
+
+// BaseURL returns the Amazon site the client talks to
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// Marketplace returns the regional storefront the client is configured for
+func (c *Client) Marketplace() *marketplace.Marketplace {
+	return c.marketplace
+}
+
+// cacheKey returns the response cache key for a request URL. The key includes the
+// marketplace and profile because a base URL override (or a shared cache) would
+// otherwise let different storefronts and accounts read each other's pages.
+func (c *Client) cacheKey(requestURL string) string {
+	return c.marketplace.Code + "|" + c.profile + "|" + requestURL
+}
+
+// now returns the current time according to the client's clock
+func (c *Client) now() time.Time {
+	return c.clock.Now()
+}
@@ -0,0 +1,247 @@
+package amazon
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zkwentz/amazon-cli/internal/marketplace"
+	"github.com/zkwentz/amazon-cli/internal/ratelimit"
+)
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (f *fixedClock) Now() time.Time {
+	return f.now
+}
+
+func TestNewClient_Defaults(t *testing.T) {
+	c := NewClient()
+
+	if c.BaseURL() != "https://www.amazon.com" {
+		t.Errorf("expected default base URL, got %s", c.BaseURL())
+	}
+	if c.httpClient == nil || c.rateLimiter == nil || c.clock == nil || c.logger == nil {
+		t.Error("expected default HTTP client, rate limiter, clock and logger")
+	}
+	if c.cache != nil {
+		t.Error("expected caching to be disabled by default")
+	}
+}
+
+func TestNewClient_Options(t *testing.T) {
+	httpClient := &http.Client{Timeout: 5 * time.Second}
+	limiter := ratelimit.NewRateLimiter(0, 0, 1)
+	cache := NewMemoryCache(time.Minute, nil)
+	clock := &fixedClock{now: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}
+	logger := slog.New(slog.DiscardHandler)
+
+	c := NewClient(
+		WithBaseURL("http://127.0.0.1:8080/"),
+		WithHTTPClient(httpClient),
+		WithRateLimiter(limiter),
+		WithCache(cache),
+		WithClock(clock),
+		WithLogger(logger),
+	)
+
+	if c.BaseURL() != "http://127.0.0.1:8080" {
+		t.Errorf("expected trailing slash to be trimmed, got %s", c.BaseURL())
+	}
+	if c.httpClient != httpClient {
+		t.Error("WithHTTPClient was not applied")
+	}
+	if c.rateLimiter != limiter {
+		t.Error("WithRateLimiter was not applied")
+	}
+	if c.cache != cache {
+		t.Error("WithCache was not applied")
+	}
+	if !c.now().Equal(clock.now) {
+		t.Errorf("expected clock time %v, got %v", clock.now, c.now())
+	}
+	if c.logger != logger {
+		t.Error("WithLogger was not applied")
+	}
+}
+
+func TestNewClient_NilOptionsKeepDefaults(t *testing.T) {
+	c := NewClient(WithBaseURL(""), WithHTTPClient(nil), WithRateLimiter(nil), WithClock(nil), WithLogger(nil))
+
+	if c.BaseURL() != "https://www.amazon.com" || c.httpClient == nil || c.rateLimiter == nil || c.clock == nil || c.logger == nil {
+		t.Error("nil or empty options should not override defaults")
+	}
+}
+
+func TestWithMarketplace(t *testing.T) {
+	de, _ := marketplace.Get("de")
+
+	c := NewClient(WithMarketplace(de))
+	if c.BaseURL() != "https://www.amazon.de" {
+		t.Errorf("expected amazon.de base URL, got %s", c.BaseURL())
+	}
+	if c.Marketplace() != de {
+		t.Error("WithMarketplace was not applied")
+	}
+
+	// An explicit base URL applied afterwards takes precedence
+	c = NewClient(WithMarketplace(de), WithBaseURL("http://127.0.0.1:8080"))
+	if c.BaseURL() != "http://127.0.0.1:8080" {
+		t.Errorf("expected base URL override, got %s", c.BaseURL())
+	}
+	if c.Marketplace() != de {
+		t.Error("expected marketplace to be kept when overriding base URL")
+	}
+}
+
+func TestDo_SetsMarketplaceAcceptLanguage(t *testing.T) {
+	var acceptLanguage string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptLanguage = r.Header.Get("Accept-Language")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	uk, _ := marketplace.Get("uk")
+	c := NewClient(WithMarketplace(uk), WithBaseURL(server.URL))
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if acceptLanguage != "en-GB,en;q=0.9" {
+		t.Errorf("expected en-GB Accept-Language, got %q", acceptLanguage)
+	}
+}
+
+func TestClock_UsedForReturnTimestamps(t *testing.T) {
+	clock := &fixedClock{now: time.Date(2019, 7, 4, 0, 0, 0, 0, time.UTC)}
+	c := NewClient(WithClock(clock))
+
+	ret, err := c.CreateReturn("123-4567890-1234567", "item1", "defective")
+	if err != nil {
+		t.Fatalf("CreateReturn() error = %v", err)
+	}
+	if !strings.HasPrefix(ret.CreatedAt, "2019-07-04") {
+		t.Errorf("expected CreatedAt from injected clock, got %s", ret.CreatedAt)
+	}
+}
+
+func TestDo_CachesGETResponses(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("product page"))
+	}))
+	defer server.Close()
+
+	c := NewClient(
+		WithBaseURL(server.URL),
+		WithRateLimiter(ratelimit.NewRateLimiter(0, 0, 0)),
+		WithCache(NewMemoryCache(time.Minute, nil)),
+	)
+
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest("GET", c.BaseURL()+"/dp/B08N5WRWNW", nil)
+		resp, err := c.Do(req)
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		body := &bytes.Buffer{}
+		_, _ = body.ReadFrom(resp.Body)
+		resp.Body.Close()
+		if body.String() != "product page" {
+			t.Errorf("request %d: unexpected body %q", i, body.String())
+		}
+	}
+
+	if hits != 1 {
+		t.Errorf("expected 1 server hit with caching, got %d", hits)
+	}
+}
+
+func TestDo_CacheKeepsMarketplacesAndProfilesApart(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("order history"))
+	}))
+	defer server.Close()
+
+	de, _ := marketplace.Get("de")
+	cache := NewMemoryCache(time.Minute, nil)
+	clients := []*Client{
+		NewClient(WithBaseURL(server.URL), WithProfile("personal")),
+		NewClient(WithBaseURL(server.URL), WithProfile("work")),
+		NewClient(WithMarketplace(de), WithBaseURL(server.URL), WithProfile("personal")),
+		NewClient(WithBaseURL(server.URL), WithProfile("personal")),
+	}
+
+	for i, c := range clients {
+		WithRateLimiter(ratelimit.NewRateLimiter(0, 0, 0))(c)
+		WithCache(cache)(c)
+
+		req, _ := http.NewRequest("GET", c.BaseURL()+"/gp/your-account/order-history", nil)
+		resp, err := c.Do(req)
+		if err != nil {
+			t.Fatalf("client %d: Do() error = %v", i, err)
+		}
+		resp.Body.Close()
+	}
+
+	// Only the last client repeats the marketplace and profile of an earlier one
+	if hits != 3 {
+		t.Errorf("expected 3 server hits for 3 distinct marketplace/profile pairs, got %d", hits)
+	}
+}
+
+func TestSetTransport_DoesNotModifyCallerHTTPClient(t *testing.T) {
+	httpClient := &http.Client{Timeout: 5 * time.Second}
+	c := NewClient(WithHTTPClient(httpClient))
+
+	rt := &http.Transport{}
+	c.SetTransport(rt)
+
+	if httpClient.Transport != nil {
+		t.Error("SetTransport modified the HTTP client passed to WithHTTPClient")
+	}
+	if c.Transport() != rt {
+		t.Error("SetTransport was not applied to the client")
+	}
+	if c.httpClient.Timeout != 5*time.Second {
+		t.Errorf("expected the copied HTTP client to keep its timeout, got %v", c.httpClient.Timeout)
+	}
+}
+
+func TestDo_LogsRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	logs := &bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	c := NewClient(WithLogger(logger))
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if !strings.Contains(logs.String(), "status=200") {
+		t.Errorf("expected request to be logged, got %q", logs.String())
+	}
+}
@@ -0,0 +1,221 @@
+package amazon
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zkwentz/amazon-cli/internal/marketplace"
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+// OrderSearchQuery describes a search across the order history
+type OrderSearchQuery struct {
+	// Text matches item titles, ASINs, sellers and order IDs (case-insensitive),
+	// or the order total or an item price when it looks like an amount (e.g. "$29.99")
+	Text string
+	// ASIN keeps only orders containing this item
+	ASIN string
+	// MinTotal keeps only orders whose total is at least this amount
+	MinTotal float64
+	// MaxTotal keeps only orders whose total is at most this amount; 0 means no maximum
+	MaxTotal float64
+	// Status keeps only orders with this status (e.g. "delivered"); "pending" also keeps
+	// out_for_delivery and delayed orders, and "returned" return_started and refunded ones
+	Status string
+	// Since keeps only orders placed on or after this date
+	Since time.Time
+	// Until keeps only orders placed on or before this date
+	Until time.Time
+	// Limit caps the number of results; 0 means no limit
+	Limit int
+}
+
+// priceTextRegex matches search text that is an amount rather than a word (e.g. "$29.99", "29,99 €")
+var priceTextRegex = regexp.MustCompile(`^\p{Sc}?\s*\d[\d.,\s\x{00a0}]*\s*\p{Sc}?$`)
+
+// Matches reports whether an order satisfies every filter of the query.
+// Order dates are interpreted using the given marketplace (nil means US).
+func (q OrderSearchQuery) Matches(order models.Order, m *marketplace.Marketplace) bool {
+	m = marketplaceOrDefault(m)
+
+	if !orderStatusMatches(q.Status, order.Status) {
+		return false
+	}
+	if q.MinTotal > 0 && order.Total < q.MinTotal {
+		return false
+	}
+	if q.MaxTotal > 0 && order.Total > q.MaxTotal {
+		return false
+	}
+
+	if q.ASIN != "" {
+		found := false
+		for _, item := range order.Items {
+			if strings.EqualFold(item.ASIN, q.ASIN) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if !q.Since.IsZero() || !q.Until.IsZero() {
+		date, err := m.ParseDate(order.Date)
+		if err != nil {
+			return false
+		}
+		if (!q.Since.IsZero() && date.Before(q.Since)) || (!q.Until.IsZero() && date.After(q.Until)) {
+			return false
+		}
+	}
+
+	return q.Text == "" || q.matchesText(order, m)
+}
+
+// matchesText reports whether the query text appears in the order
+func (q OrderSearchQuery) matchesText(order models.Order, m *marketplace.Marketplace) bool {
+	text := strings.TrimSpace(q.Text)
+
+	// Amounts match the order total or any item price
+	if priceTextRegex.MatchString(text) {
+		amount := m.ParsePrice(text)
+		if sameAmount(order.Total, amount) {
+			return true
+		}
+		for _, item := range order.Items {
+			if sameAmount(item.Price, amount) {
+				return true
+			}
+		}
+	}
+
+	needle := strings.ToLower(text)
+	if strings.Contains(strings.ToLower(order.OrderID), needle) {
+		return true
+	}
+	for _, item := range order.Items {
+		if strings.Contains(strings.ToLower(item.Title), needle) ||
+			strings.EqualFold(item.ASIN, text) ||
+			strings.Contains(strings.ToLower(item.Seller), needle) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Filter returns the orders matching the query, up to its limit, in their original order
+func (q OrderSearchQuery) Filter(orders []models.Order, m *marketplace.Marketplace) []models.Order {
+	matched := []models.Order{}
+	for _, order := range orders {
+		if q.Limit > 0 && len(matched) >= q.Limit {
+			break
+		}
+		if q.Matches(order, m) {
+			matched = append(matched, order)
+		}
+	}
+	return matched
+}
+
+// sameAmount compares two currency amounts to the cent
+func sameAmount(a, b float64) bool {
+	return math.Abs(a-b) < 0.005
+}
+
+// SearchOrders finds orders matching the query across the full order history.
+// Text searches use Amazon's order search page. When it is unavailable, or no text
+// is given, the local order index is searched if one is configured and synced;
+// otherwise the order history is walked and filtered locally.
+func (c *Client) SearchOrders(query OrderSearchQuery) (*models.OrdersResponse, error) {
+	orders := []models.Order{}
+	collect := func(order models.Order) error {
+		orders = append(orders, order)
+		if query.Limit > 0 && len(orders) >= query.Limit {
+			return errStopWalk
+		}
+		return nil
+	}
+
+	err := errOrderPageNotFound
+	if strings.TrimSpace(query.Text) != "" {
+		err = c.searchOrderPages(query, collect)
+	}
+	if errors.Is(err, errOrderPageNotFound) {
+		// Drop results from search pages read before one went missing; the
+		// fallback finds them again
+		orders = []models.Order{}
+		if c.orderIndex != nil && c.orderIndex.Len() > 0 {
+			orders = query.Filter(c.orderIndex.Orders(), c.marketplace)
+			err = nil
+		} else {
+			err = c.searchOrderHistory(query, collect)
+		}
+	}
+	if err != nil && !errors.Is(err, errStopWalk) {
+		return nil, err
+	}
+
+	return &models.OrdersResponse{
+		Orders:     orders,
+		TotalCount: len(orders),
+	}, nil
+}
+
+// searchOrderPages runs the text search on Amazon's order search page and applies
+// the remaining filters to the results. Amazon matches text against more than the
+// list page shows, so results are not re-filtered by text.
+func (c *Client) searchOrderPages(query OrderSearchQuery, fn func(models.Order) error) error {
+	filters := query
+	filters.Text = ""
+
+	_, err := c.walkPages(func(startIndex int) string {
+		return c.orderSearchURL(query.Text, startIndex)
+	}, OrderQuery{}, func(page []models.Order) error {
+		for _, order := range page {
+			if !filters.Matches(order, c.marketplace) {
+				continue
+			}
+			if err := fn(order); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+// searchOrderHistory walks the order history in the query's date range and filters it locally
+func (c *Client) searchOrderHistory(query OrderSearchQuery, fn func(models.Order) error) error {
+	walk := OrderQuery{
+		Status: query.Status,
+		Since:  query.Since,
+		Until:  query.Until,
+		All:    true,
+	}
+
+	return c.WalkOrders(walk, func(order models.Order) error {
+		if !query.Matches(order, c.marketplace) {
+			return nil
+		}
+		return fn(order)
+	})
+}
+
+// orderSearchURL builds the order search URL for the given text and start index
+func (c *Client) orderSearchURL(text string, startIndex int) string {
+	params := url.Values{}
+	params.Set("search", text)
+	if startIndex > 0 {
+		params.Set("startIndex", strconv.Itoa(startIndex))
+	}
+	return fmt.Sprintf("%s/your-orders/search?%s", c.baseURL, params.Encode())
+}
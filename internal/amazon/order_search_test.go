@@ -0,0 +1,173 @@
+package amazon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zkwentz/amazon-cli/internal/ratelimit"
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+func TestOrderSearchQuery_Matches(t *testing.T) {
+	order := models.Order{
+		OrderID: "112-1234567-7654321",
+		Date:    "February 2, 2026",
+		Total:   74.97,
+		Status:  models.OrderStatusDelivered,
+		Items: []models.OrderItem{
+			{ASIN: "B0C1H2J3K4", Title: "Replacement Water Filter (3-Pack)", Price: 19.99, Seller: "Hydro Goods LLC"},
+			{ASIN: "B0D9Z8Y7X6", Title: "LED Desk Lamp with USB Port", Price: 14.98},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		query    OrderSearchQuery
+		expected bool
+	}{
+		{"empty query", OrderSearchQuery{}, true},
+		{"title case-insensitive", OrderSearchQuery{Text: "water filter"}, true},
+		{"title miss", OrderSearchQuery{Text: "headphones"}, false},
+		{"ASIN as text", OrderSearchQuery{Text: "b0d9z8y7x6"}, true},
+		{"seller", OrderSearchQuery{Text: "hydro goods"}, true},
+		{"order ID", OrderSearchQuery{Text: "1234567-7654321"}, true},
+		{"order total", OrderSearchQuery{Text: "$74.97"}, true},
+		{"item price", OrderSearchQuery{Text: "14.98"}, true},
+		{"price miss", OrderSearchQuery{Text: "$15.00"}, false},
+		{"ASIN filter", OrderSearchQuery{ASIN: "B0C1H2J3K4"}, true},
+		{"ASIN filter miss", OrderSearchQuery{ASIN: "B000000000"}, false},
+		{"min total", OrderSearchQuery{MinTotal: 50}, true},
+		{"min total miss", OrderSearchQuery{MinTotal: 75}, false},
+		{"max total miss", OrderSearchQuery{MaxTotal: 70}, false},
+		{"status", OrderSearchQuery{Status: models.OrderStatusDelivered}, true},
+		{"status miss", OrderSearchQuery{Status: models.OrderStatusReturned}, false},
+		{"date range", OrderSearchQuery{Since: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), Until: time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC)}, true},
+		{"date range miss", OrderSearchQuery{Since: time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC)}, false},
+		{"combined", OrderSearchQuery{Text: "lamp", ASIN: "B0C1H2J3K4", MaxTotal: 100}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.Matches(order, nil); got != tt.expected {
+				t.Errorf("Matches() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSearchOrders_UsesOrderSearchPage(t *testing.T) {
+	fixtureData, err := os.ReadFile(filepath.Join("..", "..", "testdata", "orders", "order_list_items_sample.html"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture file: %v", err)
+	}
+
+	var searched string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/your-orders/search" {
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		searched = r.URL.Query().Get("search")
+		_, _ = w.Write(fixtureData)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithRateLimiter(ratelimit.NewRateLimiter(0, 0, 0)))
+
+	// Amazon matched the text; the remaining filters are applied locally
+	response, err := client.SearchOrders(OrderSearchQuery{Text: "filter cartridge", ASIN: "B0D9Z8Y7X6"})
+	if err != nil {
+		t.Fatalf("SearchOrders() error = %v", err)
+	}
+	if searched != "filter cartridge" {
+		t.Errorf("Expected search text to be sent, got %q", searched)
+	}
+	if response.TotalCount != 1 || response.Orders[0].OrderID != "112-1234567-7654321" {
+		t.Errorf("Expected the matching order, got %+v", response.Orders)
+	}
+
+	response, err = client.SearchOrders(OrderSearchQuery{Text: "lamp", MinTotal: 100})
+	if err != nil {
+		t.Fatalf("SearchOrders() error = %v", err)
+	}
+	if response.TotalCount != 0 {
+		t.Errorf("Expected no orders above the minimum total, got %d", response.TotalCount)
+	}
+}
+
+func TestSearchOrders_FallsBackToHistory(t *testing.T) {
+	var requests []string
+	orders := generateOrders("125", time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC), 12)
+	views := map[string][]models.Order{"year-2025": orders}
+
+	server := orderHistoryServer(t, views, []int{2025}, &requests)
+	defer server.Close()
+
+	clock := &fixedClock{now: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)}
+	client := NewClient(WithBaseURL(server.URL), WithClock(clock), WithRateLimiter(ratelimit.NewRateLimiter(0, 0, 0)))
+
+	// The generated order pages carry no items, so the match comes from the total
+	response, err := client.SearchOrders(OrderSearchQuery{Text: "$12.00"})
+	if err != nil {
+		t.Fatalf("SearchOrders() error = %v", err)
+	}
+	if response.TotalCount != 1 || response.Orders[0].OrderID != orders[11].OrderID {
+		t.Errorf("Expected the order totaling $12.00, got %+v", response.Orders)
+	}
+
+	// The search page was tried first, then the 2025 history was walked
+	if len(requests) < 2 || !strings.HasPrefix(requests[0], "search=") {
+		t.Errorf("Expected search page request before walking history, got %v", requests)
+	}
+
+	// Filters without text walk the history directly
+	requests = nil
+	response, err = client.SearchOrders(OrderSearchQuery{MinTotal: 11, Limit: 1})
+	if err != nil {
+		t.Fatalf("SearchOrders() error = %v", err)
+	}
+	if response.TotalCount != 1 {
+		t.Errorf("Expected limit of 1 result, got %d", response.TotalCount)
+	}
+	if strings.HasPrefix(requests[0], "search=") {
+		t.Errorf("Expected no search page request without text, got %v", requests)
+	}
+}
+
+func TestSearchOrders_FallbackDropsPartialSearchResults(t *testing.T) {
+	var requests []string
+	orders := generateOrders("125", time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC), 12)
+	history := orderHistoryServer(t, map[string][]models.Order{"year-2025": orders}, []int{2025}, &requests)
+	defer history.Close()
+
+	// The search page serves its first page from the history, then goes missing
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/your-orders/search" {
+			if r.URL.Query().Get("startIndex") != "" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			r.URL.Path = "/gp/your-account/order-history"
+			r.URL.RawQuery = "timeFilter=year-2025"
+		}
+		history.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	clock := &fixedClock{now: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)}
+	client := NewClient(WithBaseURL(server.URL), WithClock(clock), WithRateLimiter(ratelimit.NewRateLimiter(0, 0, 0)))
+
+	response, err := client.SearchOrders(OrderSearchQuery{Text: "$12.00"})
+	if err != nil {
+		t.Fatalf("SearchOrders() error = %v", err)
+	}
+	if response.TotalCount != 1 || response.Orders[0].OrderID != orders[11].OrderID {
+		t.Errorf("Expected only the history match, got %+v", response.Orders)
+	}
+}
@@ -0,0 +1,159 @@
+package amazon
+
+import (
+	"errors"
+	"time"
+
+	"github.com/zkwentz/amazon-cli/internal/orderindex"
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+// syncUnchangedWindow is how many consecutive unchanged orders past the last synced
+// order end an incremental sync (one order-history page)
+const syncUnchangedWindow = orderHistoryPageSize
+
+// syncSaveInterval is how many fetched orders are checkpointed to the index at once
+const syncSaveInterval = orderHistoryPageSize
+
+// SyncOptions controls an order index sync
+type SyncOptions struct {
+	// Full walks the entire order history instead of stopping shortly after the last synced order
+	Full bool
+}
+
+// SyncResult summarizes an order index sync
+type SyncResult struct {
+	Profile           string    `json:"profile"`
+	New               int       `json:"new"`
+	Updated           int       `json:"updated"`
+	Unchanged         int       `json:"unchanged"`
+	TotalOrders       int       `json:"total_orders"`
+	LastSyncedOrderID string    `json:"last_synced_order_id,omitempty"`
+	LastSyncedAt      time.Time `json:"last_synced_at"`
+	Resumed           bool      `json:"resumed"`
+}
+
+// SyncOrders pulls new and changed orders into the local index. New orders and orders
+// whose status or total changed are fetched in full with GetOrder; unchanged orders are
+// skipped. Status and delivery date changes to stored orders are sent to the notifier,
+// if one is set; order detail pages carry no tracking events, so those are only
+// reported by the tracking watchers. The index is checkpointed every syncSaveInterval
+// orders and when the walk fails, so an interrupted sync resumes on the next run
+// without fetching most stored orders again. The first sync walks the full history;
+// later syncs stop shortly after reaching the order the previous sync started from.
+func (c *Client) SyncOrders(idx *orderindex.Index, opts SyncOptions) (*SyncResult, error) {
+	state := idx.SyncState()
+	result := &SyncResult{
+		Profile: idx.Profile(),
+		Resumed: state.InProgress != nil,
+	}
+
+	// Record the sync as in progress so an interruption is detected next time
+	if state.InProgress == nil {
+		state.InProgress = &orderindex.SyncCheckpoint{StartedAt: c.now()}
+	}
+	idx.SetMarketplace(c.marketplace.Code)
+	idx.SetSyncState(state)
+	if err := idx.Save(); err != nil {
+		return nil, err
+	}
+
+	lastSynced := state.LastSyncedOrderID
+	passedLastSynced := false
+	unchanged := 0
+
+	err := c.WalkOrders(OrderQuery{All: true}, func(order models.Order) error {
+		if state.InProgress.NewestOrderID == "" {
+			state.InProgress.NewestOrderID = order.OrderID
+		}
+		if lastSynced != "" && order.OrderID == lastSynced {
+			passedLastSynced = true
+		}
+
+		stored, exists := idx.Get(order.OrderID)
+		if exists && !orderChanged(stored, order) {
+			result.Unchanged++
+			unchanged++
+			if !opts.Full && passedLastSynced && unchanged >= syncUnchangedWindow {
+				return errStopWalk
+			}
+			return nil
+		}
+		unchanged = 0
+
+		synced, err := c.syncOrder(order)
+		if err != nil {
+			return err
+		}
+		idx.Put(*synced)
+		if exists {
+			result.Updated++
+			c.notify(models.HookSourceSync, orderChangeEvents(stored, *synced))
+		} else {
+			result.New++
+		}
+
+		// Checkpoint in batches; rewriting the index after every order is quadratic
+		state.InProgress.Processed++
+		idx.SetSyncState(state)
+		if state.InProgress.Processed%syncSaveInterval == 0 {
+			return idx.Save()
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStopWalk) {
+		// Keep the orders fetched so far for the next run
+		if saveErr := idx.Save(); saveErr != nil {
+			return nil, errors.Join(err, saveErr)
+		}
+		return nil, err
+	}
+
+	// Mark the sync complete
+	if state.InProgress.NewestOrderID != "" {
+		state.LastSyncedOrderID = state.InProgress.NewestOrderID
+	}
+	state.LastSyncedAt = c.now()
+	state.InProgress = nil
+	idx.SetSyncState(state)
+	if err := idx.Save(); err != nil {
+		return nil, err
+	}
+
+	result.TotalOrders = idx.Len()
+	result.LastSyncedOrderID = state.LastSyncedOrderID
+	result.LastSyncedAt = state.LastSyncedAt
+	return result, nil
+}
+
+// syncOrder builds the stored form of an order-history entry: merged with its
+// detail page when one exists, with the date normalized to YYYY-MM-DD
+func (c *Client) syncOrder(order models.Order) (*models.Order, error) {
+	if c.marketplace.ValidOrderID(order.OrderID) {
+		detail, err := c.GetOrder(order.OrderID)
+		if err != nil {
+			return nil, err
+		}
+		mergeOrderDetail(&order, detail)
+		if detail.Date != "" {
+			order.Date = detail.Date
+		}
+	}
+
+	if date, err := c.marketplace.ParseDate(order.Date); err == nil {
+		order.Date = date.Format("2006-01-02")
+	}
+
+	return &order, nil
+}
+
+// orderChanged reports whether an order-history entry differs from its stored copy
+func orderChanged(stored, listed models.Order) bool {
+	if listed.Status != "" && listed.Status != models.OrderStatusUnknown && listed.Status != stored.Status {
+		return true
+	}
+	if listed.Total != 0 && !sameAmount(listed.Total, stored.Total) {
+		return true
+	}
+	return len(stored.Items) == 0 && len(listed.Items) > 0
+}
@@ -0,0 +1,291 @@
+package amazon
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/zkwentz/amazon-cli/internal/orderindex"
+	"github.com/zkwentz/amazon-cli/internal/ratelimit"
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+// orderSyncServer serves the order history views plus a detail page for every order.
+// Detail requests are recorded; orders listed in failing return a server error.
+func orderSyncServer(t *testing.T, views map[string][]models.Order, details *[]string, failing map[string]bool) *httptest.Server {
+	t.Helper()
+
+	var requests []string
+	history := orderHistoryServer(t, views, []int{2025}, &requests)
+	t.Cleanup(history.Close)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/gp/your-account/order-details" {
+			history.Config.Handler.ServeHTTP(w, r)
+			return
+		}
+
+		orderID := r.URL.Query().Get("orderID")
+		*details = append(*details, orderID)
+		if failing[orderID] {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		fmt.Fprintf(w, `<html><body><span class="order-id-value">%s</span>
+<div class="order-status"><span class="status-badge">Delivered</span></div>
+<div class="order-item" data-asin="B08N5WRWNW"><span class="item-title">Echo Dot</span></div>
+</body></html>`, orderID)
+	}))
+}
+
+func newSyncTestClient(url string) *Client {
+	clock := &fixedClock{now: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)}
+	return NewClient(WithBaseURL(url), WithClock(clock), WithRateLimiter(ratelimit.NewRateLimiter(0, 0, 0)))
+}
+
+func TestSyncOrders_FirstSyncStoresAllOrders(t *testing.T) {
+	var details []string
+	orders := generateOrders("111", time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC), 12)
+	server := orderSyncServer(t, map[string][]models.Order{"year-2025": orders}, &details, nil)
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "default.json")
+	idx, _ := orderindex.Open(path, orderindex.DefaultProfile)
+
+	result, err := newSyncTestClient(server.URL).SyncOrders(idx, SyncOptions{})
+	if err != nil {
+		t.Fatalf("SyncOrders() error = %v", err)
+	}
+
+	if result.New != 12 || result.Updated != 0 || result.TotalOrders != 12 {
+		t.Errorf("Expected 12 new orders, got %+v", result)
+	}
+	if len(details) != 12 {
+		t.Errorf("Expected a detail request per order, got %d", len(details))
+	}
+	if result.LastSyncedOrderID != orders[0].OrderID {
+		t.Errorf("Expected last synced order %s, got %s", orders[0].OrderID, result.LastSyncedOrderID)
+	}
+
+	// The saved index holds detailed orders with normalized dates
+	reopened, err := orderindex.Open(path, orderindex.DefaultProfile)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	order, exists := reopened.Get(orders[0].OrderID)
+	if !exists {
+		t.Fatal("Expected newest order to be stored")
+	}
+	if order.Date != "2025-06-30" {
+		t.Errorf("Expected normalized date 2025-06-30, got %s", order.Date)
+	}
+	if len(order.Items) != 1 || order.Items[0].ASIN != "B08N5WRWNW" {
+		t.Errorf("Expected items from the detail page, got %+v", order.Items)
+	}
+	if state := reopened.SyncState(); state.InProgress != nil || state.LastSyncedOrderID != orders[0].OrderID {
+		t.Errorf("Expected completed sync state, got %+v", state)
+	}
+	if reopened.Marketplace() != "us" {
+		t.Errorf("Expected marketplace us, got %s", reopened.Marketplace())
+	}
+}
+
+func TestSyncOrders_IncrementalStopsAfterLastSynced(t *testing.T) {
+	var details []string
+	orders := generateOrders("111", time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC), 25)
+	views := map[string][]models.Order{"year-2025": orders}
+	server := orderSyncServer(t, views, &details, nil)
+	defer server.Close()
+
+	idx, _ := orderindex.Open(filepath.Join(t.TempDir(), "default.json"), orderindex.DefaultProfile)
+	client := newSyncTestClient(server.URL)
+	if _, err := client.SyncOrders(idx, SyncOptions{}); err != nil {
+		t.Fatalf("SyncOrders() error = %v", err)
+	}
+
+	// Two new orders arrive and an older order's total changes
+	newOrders := generateOrders("222", time.Date(2025, 7, 2, 0, 0, 0, 0, time.UTC), 2)
+	changed := orders[3]
+	changed.Total = 99
+	updated := append(newOrders, orders...)
+	updated[2+3] = changed
+	views["year-2025"] = updated
+
+	details = nil
+	result, err := client.SyncOrders(idx, SyncOptions{})
+	if err != nil {
+		t.Fatalf("SyncOrders() error = %v", err)
+	}
+
+	if result.New != 2 || result.Updated != 1 {
+		t.Errorf("Expected 2 new and 1 updated order, got %+v", result)
+	}
+	if len(details) != 3 {
+		t.Errorf("Expected details for new and changed orders only, got %v", details)
+	}
+	// The three orders ahead of the changed one, then one window past it
+	if result.Unchanged != 3+syncUnchangedWindow {
+		t.Errorf("Expected the walk to stop after %d unchanged orders, got %d", syncUnchangedWindow, result.Unchanged)
+	}
+	if result.LastSyncedOrderID != newOrders[0].OrderID {
+		t.Errorf("Expected last synced order %s, got %s", newOrders[0].OrderID, result.LastSyncedOrderID)
+	}
+
+	// A full sync checks every order
+	result, err = client.SyncOrders(idx, SyncOptions{Full: true})
+	if err != nil {
+		t.Fatalf("SyncOrders() error = %v", err)
+	}
+	if result.Unchanged != 27 {
+		t.Errorf("Expected all 27 orders checked, got %+v", result)
+	}
+}
+
+func TestSyncOrders_NotifiesStatusChanges(t *testing.T) {
+	var details []string
+	orders := generateOrders("111", time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC), 3)
+	server := orderSyncServer(t, map[string][]models.Order{"year-2025": orders}, &details, nil)
+	defer server.Close()
+
+	idx, _ := orderindex.Open(filepath.Join(t.TempDir(), "default.json"), orderindex.DefaultProfile)
+	client := newSyncTestClient(server.URL)
+	var notified []models.HookEvent
+	WithNotifier(func(event models.HookEvent) {
+		notified = append(notified, event)
+	})(client)
+
+	// New orders are not changes
+	if _, err := client.SyncOrders(idx, SyncOptions{}); err != nil {
+		t.Fatalf("SyncOrders() error = %v", err)
+	}
+	if len(notified) != 0 {
+		t.Errorf("Expected no notifications on the first sync, got %+v", notified)
+	}
+
+	// The stored copy was still pending; the order history now shows it delivered
+	stored, _ := idx.Get(orders[1].OrderID)
+	stored.Status = models.OrderStatusPending
+	idx.Put(stored)
+
+	if _, err := client.SyncOrders(idx, SyncOptions{Full: true}); err != nil {
+		t.Fatalf("SyncOrders() error = %v", err)
+	}
+	if len(notified) != 1 {
+		t.Fatalf("Expected one notification, got %+v", notified)
+	}
+	event := notified[0]
+	if event.Type != models.HookEventStatusChanged || event.Source != models.HookSourceSync || event.OrderID != orders[1].OrderID {
+		t.Errorf("Unexpected notification: %+v", event)
+	}
+	if event.Previous != models.OrderStatusPending || event.Current != models.OrderStatusDelivered || event.Order == nil {
+		t.Errorf("Expected pending -> delivered with the order, got %+v", event)
+	}
+}
+
+func TestSyncOrders_ResumesAfterInterruption(t *testing.T) {
+	var details []string
+	orders := generateOrders("111", time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC), 15)
+	failing := map[string]bool{orders[12].OrderID: true}
+	server := orderSyncServer(t, map[string][]models.Order{"year-2025": orders}, &details, failing)
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "default.json")
+	idx, _ := orderindex.Open(path, orderindex.DefaultProfile)
+	client := newSyncTestClient(server.URL)
+
+	if _, err := client.SyncOrders(idx, SyncOptions{}); err == nil {
+		t.Fatal("Expected the sync to fail on the broken detail page")
+	}
+
+	// Progress up to the failure was saved
+	reopened, _ := orderindex.Open(path, orderindex.DefaultProfile)
+	state := reopened.SyncState()
+	if state.InProgress == nil || state.InProgress.Processed != 12 {
+		t.Fatalf("Expected an in-progress checkpoint after 12 orders, got %+v", state.InProgress)
+	}
+	if reopened.Len() != 12 {
+		t.Errorf("Expected 12 stored orders, got %d", reopened.Len())
+	}
+
+	// The next run resumes without fetching stored orders again
+	delete(failing, orders[12].OrderID)
+	details = nil
+	result, err := client.SyncOrders(reopened, SyncOptions{})
+	if err != nil {
+		t.Fatalf("SyncOrders() error = %v", err)
+	}
+	if !result.Resumed || result.New != 3 || result.TotalOrders != 15 {
+		t.Errorf("Expected a resumed sync adding 3 orders, got %+v", result)
+	}
+	if len(details) != 3 {
+		t.Errorf("Expected details for the remaining orders only, got %v", details)
+	}
+	if result.LastSyncedOrderID != orders[0].OrderID {
+		t.Errorf("Expected last synced order %s, got %s", orders[0].OrderID, result.LastSyncedOrderID)
+	}
+}
+
+func TestSyncOrders_SavesIndexInBatches(t *testing.T) {
+	var details []string
+	orders := generateOrders("111", time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC), syncSaveInterval+5)
+	sync := orderSyncServer(t, map[string][]models.Order{"year-2025": orders}, &details, nil)
+	defer sync.Close()
+
+	// Record how many orders the saved index holds whenever a detail page is fetched
+	path := filepath.Join(t.TempDir(), "default.json")
+	var saved []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gp/your-account/order-details" {
+			onDisk, _ := orderindex.Open(path, orderindex.DefaultProfile)
+			saved = append(saved, onDisk.Len())
+		}
+		sync.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	idx, _ := orderindex.Open(path, orderindex.DefaultProfile)
+	if _, err := newSyncTestClient(server.URL).SyncOrders(idx, SyncOptions{}); err != nil {
+		t.Fatalf("SyncOrders() error = %v", err)
+	}
+
+	if len(saved) != len(orders) {
+		t.Fatalf("Expected a detail request per order, got %d", len(saved))
+	}
+	if saved[syncSaveInterval-1] != 0 || saved[syncSaveInterval] != syncSaveInterval {
+		t.Errorf("Expected the index to be saved after %d orders, got %v", syncSaveInterval, saved)
+	}
+	if reopened, _ := orderindex.Open(path, orderindex.DefaultProfile); reopened.Len() != len(orders) {
+		t.Errorf("Expected %d orders saved at the end, got %d", len(orders), reopened.Len())
+	}
+}
+
+func TestSearchOrders_FallsBackToOrderIndex(t *testing.T) {
+	var requests []string
+	server := orderHistoryServer(t, map[string][]models.Order{}, []int{2025}, &requests)
+	defer server.Close()
+
+	idx, _ := orderindex.Open(filepath.Join(t.TempDir(), "default.json"), orderindex.DefaultProfile)
+	idx.Put(models.Order{OrderID: "111-0000000-0000001", Date: "2025-03-01", Total: 12,
+		Items: []models.OrderItem{{ASIN: "B08N5WRWNW", Title: "Echo Dot"}}})
+	idx.Put(models.Order{OrderID: "111-0000000-0000002", Date: "2025-04-01", Total: 30})
+
+	client := newSyncTestClient(server.URL)
+	WithOrderIndex(idx)(client)
+
+	response, err := client.SearchOrders(OrderSearchQuery{Text: "echo"})
+	if err != nil {
+		t.Fatalf("SearchOrders() error = %v", err)
+	}
+	if response.TotalCount != 1 || response.Orders[0].OrderID != "111-0000000-0000001" {
+		t.Errorf("Expected the indexed Echo Dot order, got %+v", response.Orders)
+	}
+
+	// Only the unavailable search page was requested; the history was not walked
+	if len(requests) != 1 {
+		t.Errorf("Expected a single search page request, got %v", requests)
+	}
+}
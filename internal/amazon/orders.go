@@ -2,28 +2,279 @@ package amazon
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"net/url"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/zkwentz/amazon-cli/internal/carrier"
+	"github.com/zkwentz/amazon-cli/internal/marketplace"
 	"github.com/zkwentz/amazon-cli/pkg/models"
 )
 
+// orderHistoryPageSize is the number of orders Amazon shows per order-history page
+const orderHistoryPageSize = 10
+
+// earliestOrderYear bounds history walks when the page does not list available years
+const earliestOrderYear = 1995
+
+// errStopWalk ends an order walk early once enough orders were collected
+var errStopWalk = errors.New("stop walking orders")
+
+// errOrderPageNotFound reports that an order listing page does not exist (HTTP 404)
+var errOrderPageNotFound = errors.New("unexpected status code: 404")
+
+// OrderQuery selects orders from the order history
+type OrderQuery struct {
+	// Limit caps the number of orders returned; 0 means no limit
+	Limit int
+	// Status keeps only orders with this status (e.g. "delivered"); see OrderSearchQuery.Status
+	Status string
+	// Since keeps only orders placed on or after this date
+	Since time.Time
+	// Until keeps only orders placed on or before this date
+	Until time.Time
+	// All walks the full order history instead of the recent orders view
+	All bool
+	// Expand fetches order details for orders whose list entry lacks item information
+	Expand bool
+	// Details fetches the detail page of every order, e.g. for the charges breakdown
+	Details bool
+	// Type selects physical (the default), digital or all orders
+	Type string
+}
+
+// hasDateRange reports whether the query restricts order dates
+func (q OrderQuery) hasDateRange() bool {
+	return !q.Since.IsZero() || !q.Until.IsZero()
+}
+
+// byYear reports whether the query needs the per-year history views
+func (q OrderQuery) byYear() bool {
+	return q.All || q.hasDateRange()
+}
+
+// inRange reports whether an order date falls within the query's date range
+func (q OrderQuery) inRange(date time.Time) bool {
+	if !q.Since.IsZero() && date.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && date.After(q.Until) {
+		return false
+	}
+	return true
+}
+
+// orderHistoryPage is a single parsed page of the order history
+type orderHistoryPage struct {
+	orders  []models.Order
+	hasNext bool
+	years   []int
+}
+
 // GetOrders retrieves a list of orders with optional filtering
 func (c *Client) GetOrders(limit int, status string) (*models.OrdersResponse, error) {
 	if limit <= 0 {
 		limit = 10
 	}
 
-	// Construct the order history URL
-	orderHistoryURL := fmt.Sprintf("%s/gp/your-account/order-history", c.baseURL)
+	return c.ListOrders(OrderQuery{Limit: limit, Status: status})
+}
+
+// ListOrders collects all orders matching the query
+func (c *Client) ListOrders(query OrderQuery) (*models.OrdersResponse, error) {
+	orders := []models.Order{}
+	err := c.WalkOrders(query, func(order models.Order) error {
+		orders = append(orders, order)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.OrdersResponse{
+		Orders:     orders,
+		TotalCount: len(orders),
+	}, nil
+}
+
+// WalkOrders pages through the order history, newest first, calling fn for each
+// matching order as soon as its page is parsed. Walking stops when the query's
+// limit is reached, the date range is exhausted, or fn returns an error.
+// Physical and digital orders are listed separately, so with OrderTypeAll both
+// histories are walked side by side and merged by date.
+func (c *Client) WalkOrders(query OrderQuery, fn func(models.Order) error) error {
+	if query.orderType() == OrderTypeAll {
+		return c.walkAllOrders(query, fn)
+	}
+
+	count := 0
+	visitPage := func(orders []models.Order) error {
+		// Select the orders this page contributes before fetching any details
+		var selected []models.Order
+		for _, order := range orders {
+			if query.Limit > 0 && count+len(selected) >= query.Limit {
+				break
+			}
+			if !orderStatusMatches(query.Status, order.Status) {
+				continue
+			}
+			if order.Type != query.orderType() {
+				continue
+			}
+			selected = append(selected, order)
+		}
+
+		if query.Expand || query.Details {
+			if err := c.expandOrders(selected, query.Details); err != nil {
+				return err
+			}
+		}
+
+		for _, order := range selected {
+			if err := fn(order); err != nil {
+				return err
+			}
+			count++
+		}
+		if query.Limit > 0 && count >= query.Limit {
+			return errStopWalk
+		}
+		return nil
+	}
+
+	var err error
+	if query.byYear() {
+		err = c.walkOrderYears(query, visitPage)
+	} else {
+		_, err = c.walkOrderPages("", query, visitPage)
+	}
+	if errors.Is(err, errStopWalk) {
+		return nil
+	}
+	return err
+}
+
+// walkOrderYears walks the per-year history views from the newest year in range to the oldest
+func (c *Client) walkOrderYears(query OrderQuery, visit func([]models.Order) error) error {
+	endYear := c.now().Year()
+	if !query.Until.IsZero() && query.Until.Year() < endYear {
+		endYear = query.Until.Year()
+	}
+
+	startYear := 0
+	if !query.Since.IsZero() {
+		startYear = query.Since.Year()
+	}
+
+	for year := endYear; startYear == 0 || year >= startYear; year-- {
+		page, err := c.walkOrderPages(yearFilter(year), query, visit)
+		if err != nil {
+			return err
+		}
+
+		if startYear == 0 {
+			switch {
+			case len(page.years) > 0:
+				// Amazon lists the years an account has orders in; stop at the oldest
+				startYear = page.years[len(page.years)-1]
+			case len(page.orders) == 0 || year <= earliestOrderYear:
+				return nil
+			}
+		}
+	}
+
+	return nil
+}
+
+// walkOrderPages visits the orders of one history view (time filter) a page at a time,
+// following pagination. Digital orders have their own history view. It returns the
+// first page so callers can inspect the available years.
+func (c *Client) walkOrderPages(timeFilter string, query OrderQuery, visit func([]models.Order) error) (*orderHistoryPage, error) {
+	return c.walkPages(func(startIndex int) string {
+		if query.orderType() == models.OrderTypeDigital {
+			return c.digitalOrderHistoryURL(timeFilter, startIndex)
+		}
+		return c.orderHistoryURL(timeFilter, startIndex)
+	}, query, visit)
+}
+
+// walkPages visits the orders of a paginated order listing, building each page's URL
+// from its start index. It returns the first page.
+func (c *Client) walkPages(pageURL func(startIndex int) string, query OrderQuery, visit func([]models.Order) error) (*orderHistoryPage, error) {
+	var first *orderHistoryPage
+	seen := make(map[string]bool)
+
+	for startIndex := 0; ; startIndex += orderHistoryPageSize {
+		page, err := c.fetchOrderPage(pageURL(startIndex))
+		if err != nil {
+			return nil, err
+		}
+		if first == nil {
+			first = page
+		}
+
+		// Guard against a page repeating orders already seen (e.g. an ignored start index)
+		var fresh []models.Order
+		for _, order := range page.orders {
+			if !seen[order.OrderID] {
+				seen[order.OrderID] = true
+				fresh = append(fresh, order)
+			}
+		}
+		if len(fresh) == 0 {
+			return first, nil
+		}
+
+		// Keep the orders in the date range; orders are listed newest first,
+		// so once one predates --since everything after it is older too
+		var batch []models.Order
+		reachedSince := false
+		for _, order := range fresh {
+			if query.hasDateRange() {
+				// Orders without a recognizable date cannot be placed in the range
+				date, err := c.marketplace.ParseDate(order.Date)
+				if err != nil {
+					continue
+				}
+				if !query.Since.IsZero() && date.Before(query.Since) {
+					reachedSince = true
+					break
+				}
+				if !query.inRange(date) {
+					continue
+				}
+			}
+			batch = append(batch, order)
+		}
 
+		if len(batch) > 0 {
+			if err := visit(batch); err != nil {
+				return first, err
+			}
+		}
+		if reachedSince {
+			return first, errStopWalk
+		}
+
+		if !page.hasNext {
+			return first, nil
+		}
+	}
+}
+
+// fetchOrderPage fetches and parses one page of an order listing (history or search results)
+func (c *Client) fetchOrderPage(pageURL string) (*orderHistoryPage, error) {
 	// Create HTTP GET request
-	req, err := http.NewRequest("GET", orderHistoryURL, nil)
+	req, err := http.NewRequest("GET", pageURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -36,6 +287,9 @@ func (c *Client) GetOrders(limit int, status string) (*models.OrdersResponse, er
 	defer resp.Body.Close()
 
 	// Check for successful response
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errOrderPageNotFound
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
@@ -47,32 +301,36 @@ func (c *Client) GetOrders(limit int, status string) (*models.OrdersResponse, er
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
+	// Check for CAPTCHA
+	if c.detectCAPTCHA(body.Bytes()) {
+		return nil, fmt.Errorf("CAPTCHA detected - Amazon is blocking automated access")
+	}
+
 	// Parse the HTML response
-	orders, err := parseOrdersHTML(body.Bytes())
+	page, err := parseOrderHistoryPage(body.Bytes(), c.marketplace)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse order history: %w", err)
 	}
 
-	// Filter by status if provided
-	if status != "" {
-		filtered := []models.Order{}
-		for _, order := range orders {
-			if order.Status == status {
-				filtered = append(filtered, order)
-			}
-		}
-		orders = filtered
-	}
+	return page, nil
+}
 
-	// Apply limit
-	if len(orders) > limit {
-		orders = orders[:limit]
+// orderHistoryURL builds the order history URL for a time filter (e.g. "year-2024") and start index
+func (c *Client) orderHistoryURL(timeFilter string, startIndex int) string {
+	orderHistoryURL := fmt.Sprintf("%s/gp/your-account/order-history", c.baseURL)
+
+	params := url.Values{}
+	if timeFilter != "" {
+		params.Set("timeFilter", timeFilter)
+	}
+	if startIndex > 0 {
+		params.Set("startIndex", strconv.Itoa(startIndex))
+	}
+	if len(params) > 0 {
+		orderHistoryURL += "?" + params.Encode()
 	}
 
-	return &models.OrdersResponse{
-		Orders:     orders,
-		TotalCount: len(orders),
-	}, nil
+	return orderHistoryURL
 }
 
 // GetOrder retrieves details for a specific order
@@ -83,13 +341,12 @@ func (c *Client) GetOrder(orderID string) (*models.Order, error) {
 	}
 
 	// Validate orderID format (Amazon order IDs are in format: XXX-XXXXXXX-XXXXXXX)
-	orderIDPattern := regexp.MustCompile(`^\d{3}-\d{7}-\d{7}$`)
-	if !orderIDPattern.MatchString(orderID) {
+	if !c.marketplace.ValidOrderID(orderID) {
 		return nil, fmt.Errorf("invalid order ID format: expected XXX-XXXXXXX-XXXXXXX, got %s", orderID)
 	}
 
 	// Construct the order detail URL
-	orderURL := fmt.Sprintf("%s/gp/your-account/order-details?orderID=%s", c.baseURL, orderID)
+	orderURL := c.orderDetailURL(orderID)
 
 	// Create HTTP GET request
 	req, err := http.NewRequest("GET", orderURL, nil)
@@ -121,7 +378,7 @@ func (c *Client) GetOrder(orderID string) (*models.Order, error) {
 	}
 
 	// Parse the HTML response
-	order, err := parseOrderDetailHTML(body.Bytes())
+	order, err := parseOrderDetailHTML(body.Bytes(), c.marketplace)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse order details: %w", err)
 	}
@@ -129,14 +386,129 @@ func (c *Client) GetOrder(orderID string) (*models.Order, error) {
 	return order, nil
 }
 
+// expandConcurrency bounds the number of order detail pages fetched at once by --expand
+const expandConcurrency = 4
+
+// expandOrders fills in details missing from order-history entries by fetching each
+// order's detail page concurrently; with all, every order's page is fetched.
+// Requests still pass through the client's rate limiter.
+func (c *Client) expandOrders(orders []models.Order, all bool) error {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, expandConcurrency)
+
+	for i := range orders {
+		// Only standard orders have detail pages reachable through GetOrder
+		if (!all && !needsOrderDetail(orders[i])) || !c.marketplace.ValidOrderID(orders[i].OrderID) {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(order *models.Order) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			detail, err := c.GetOrder(order.OrderID)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to expand order %s: %w", order.OrderID, err)
+				}
+				mu.Unlock()
+				return
+			}
+			mergeOrderDetail(order, detail)
+		}(&orders[i])
+	}
+
+	wg.Wait()
+	return firstErr
+}
+
+// needsOrderDetail reports whether an order-history entry lacks information
+// that only the order detail page provides
+func needsOrderDetail(order models.Order) bool {
+	if len(order.Items) == 0 || order.Total == 0 {
+		return true
+	}
+	// Refund amounts are only shown in the detail page's charges
+	if order.Charges == nil && (order.Status == models.OrderStatusReturned || order.Status == models.OrderStatusRefunded) {
+		return true
+	}
+	for _, item := range order.Items {
+		if item.ASIN == "" || item.Title == "" || item.Price == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeOrderDetail fills the fields of a list entry that are missing from the detail page's data
+func mergeOrderDetail(order *models.Order, detail *models.Order) {
+	if order.Total == 0 {
+		order.Total = detail.Total
+	}
+	if order.Status == "" || order.Status == models.OrderStatusUnknown {
+		order.Status = detail.Status
+	}
+	if order.Tracking == nil {
+		order.Tracking = detail.Tracking
+	}
+	if order.ShippingAddress == nil {
+		order.ShippingAddress = detail.ShippingAddress
+	}
+	if order.PaymentMethod == nil {
+		order.PaymentMethod = detail.PaymentMethod
+	}
+	if order.Charges == nil {
+		order.Charges = detail.Charges
+	}
+	if len(order.Shipments) == 0 {
+		order.Shipments = detail.Shipments
+	}
+
+	if len(detail.Items) == 0 {
+		return
+	}
+
+	// The detail page is authoritative for items; keep images only the list page shows
+	images := make(map[string]string)
+	for _, item := range order.Items {
+		if item.Image != "" {
+			images[item.ASIN] = item.Image
+		}
+	}
+	items := make([]models.OrderItem, len(detail.Items))
+	for i, item := range detail.Items {
+		if item.Image == "" {
+			item.Image = images[item.ASIN]
+		}
+		items[i] = item
+	}
+	order.Items = items
+}
+
 // GetOrderTracking retrieves tracking information for an order
 func (c *Client) GetOrderTracking(orderID string) (*models.Tracking, error) {
+	return c.GetShipmentTracking(orderID, "")
+}
+
+// GetShipmentTracking retrieves the tracking of one package of an order. An empty
+// shipment ID returns the tracking Amazon shows for the order as a whole.
+func (c *Client) GetShipmentTracking(orderID, shipmentID string) (*models.Tracking, error) {
 	if orderID == "" {
 		return nil, fmt.Errorf("order ID cannot be empty")
 	}
 
 	// Build tracking URL
 	trackingURL := fmt.Sprintf("%s/progress-tracker/package/ref=ppx_yo_dt_b_track_package?_encoding=UTF8&orderId=%s", c.baseURL, orderID)
+	if shipmentID != "" {
+		trackingURL += "&shipmentId=" + url.QueryEscape(shipmentID)
+	}
 
 	// Create HTTP GET request
 	req, err := http.NewRequest("GET", trackingURL, nil)
@@ -168,7 +540,7 @@ func (c *Client) GetOrderTracking(orderID string) (*models.Tracking, error) {
 	}
 
 	// Parse tracking information from HTML
-	tracking, err := parseTrackingHTML(body.Bytes())
+	tracking, err := parseTrackingHTML(body.Bytes(), c.marketplace)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse tracking information: %w", err)
 	}
@@ -176,44 +548,88 @@ func (c *Client) GetOrderTracking(orderID string) (*models.Tracking, error) {
 	return tracking, nil
 }
 
-// GetOrderHistory retrieves order history for a specific year
+// GetOrderShipmentsTracking retrieves the tracking of every package of an order along
+// with the items each package contains. Packages that have not shipped yet have no
+// tracking page and keep a nil Tracking.
+func (c *Client) GetOrderShipmentsTracking(orderID string) (*models.OrderTracking, error) {
+	order, err := c.GetOrder(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.OrderTracking{
+		OrderID:   order.OrderID,
+		Shipments: make([]models.Shipment, 0, len(order.Shipments)),
+	}
+	for _, shipment := range order.Shipments {
+		if shipment.Tracking != nil {
+			tracking, err := c.GetShipmentTracking(order.OrderID, shipment.ShipmentID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to track shipment %s: %w", shipment.ShipmentID, err)
+			}
+			shipment.Tracking = mergeTracking(tracking, shipment.Tracking)
+		}
+		result.Shipments = append(result.Shipments, shipment)
+	}
+
+	return result, nil
+}
+
+// mergeTracking fills the fields the tracking page left empty from the order detail page
+func mergeTracking(tracking, detail *models.Tracking) *models.Tracking {
+	if tracking.Carrier == "" {
+		tracking.Carrier = detail.Carrier
+	}
+	if tracking.TrackingNumber == "" {
+		tracking.TrackingNumber = detail.TrackingNumber
+	}
+	if tracking.Status == "" {
+		tracking.Status = detail.Status
+	}
+	if tracking.DeliveryDate == "" {
+		tracking.DeliveryDate = detail.DeliveryDate
+	}
+	identifyCarrier(tracking)
+	return tracking
+}
+
+// GetOrderHistory retrieves all orders placed in a specific year
 func (c *Client) GetOrderHistory(year int) (*models.OrdersResponse, error) {
+	return c.GetOrderHistoryByType(year, models.OrderTypePhysical)
+}
+
+// GetOrderHistoryByType retrieves the physical, digital or all orders placed in a
+// year; all orders are merged newest first
+func (c *Client) GetOrderHistoryByType(year int, orderType string) (*models.OrdersResponse, error) {
+	if err := ValidateOrderType(orderType); err != nil {
+		return nil, err
+	}
 	if year <= 0 {
-		year = time.Now().Year()
-	}
-
-	// TODO: Implement actual Amazon API call
-	// For now, return mock data
-
-	orders := []models.Order{
-		{
-			OrderID: "123-1111111-1111111",
-			Date:    fmt.Sprintf("%d-06-15", year),
-			Total:   149.99,
-			Status:  "delivered",
-			Items: []models.OrderItem{
-				{
-					ASIN:     "B08XYZ9876",
-					Title:    "Kindle Paperwhite",
-					Quantity: 1,
-					Price:    149.99,
-				},
-			},
-		},
-		{
-			OrderID: "123-2222222-2222222",
-			Date:    fmt.Sprintf("%d-03-20", year),
-			Total:   35.50,
-			Status:  "delivered",
-			Items: []models.OrderItem{
-				{
-					ASIN:     "B07DEF4567",
-					Title:    "Book: The Go Programming Language",
-					Quantity: 1,
-					Price:    35.50,
-				},
-			},
-		},
+		year = c.now().Year()
+	}
+
+	query := OrderQuery{Type: orderType}
+	types := []string{query.orderType()}
+	if query.orderType() == OrderTypeAll {
+		types = []string{models.OrderTypePhysical, models.OrderTypeDigital}
+	}
+
+	orders := []models.Order{}
+	for _, t := range types {
+		_, err := c.walkOrderPages(yearFilter(year), OrderQuery{Type: t}, func(page []models.Order) error {
+			for _, order := range page {
+				if order.Type == t {
+					orders = append(orders, order)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(types) > 1 {
+		c.sortOrdersByDate(orders)
 	}
 
 	return &models.OrdersResponse{
@@ -222,18 +638,51 @@ func (c *Client) GetOrderHistory(year int) (*models.OrdersResponse, error) {
 	}, nil
 }
 
-// parseOrdersHTML parses order list HTML and extracts order information
-func parseOrdersHTML(html []byte) ([]models.Order, error) {
+// yearFilter returns the order history time filter for a calendar year
+func yearFilter(year int) string {
+	return fmt.Sprintf("year-%d", year)
+}
+
+// parseOrdersHTML parses order list HTML and extracts order information.
+// Prices and order IDs are interpreted using the given marketplace (nil means US).
+func parseOrdersHTML(html []byte, m *marketplace.Marketplace) ([]models.Order, error) {
+	page, err := parseOrderHistoryPage(html, m)
+	if err != nil {
+		return nil, err
+	}
+	return page.orders, nil
+}
+
+// parseOrderHistoryPage parses an order history page: its orders, whether a next
+// page exists, and the years offered by the time filter (newest first)
+func parseOrderHistoryPage(html []byte, m *marketplace.Marketplace) (*orderHistoryPage, error) {
+	m = marketplaceOrDefault(m)
+
 	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(html))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse HTML: %w", err)
 	}
 
+	page := &orderHistoryPage{}
+
+	// A next-page link is present and enabled when more orders follow
+	next := doc.Find(".a-pagination .a-last")
+	page.hasNext = next.Length() > 0 && !next.HasClass("a-disabled") && next.Find("a").Length() > 0
+
+	// The time filter lists "year-YYYY" options for every year with orders
+	doc.Find(`select[name="timeFilter"] option`).Each(func(i int, s *goquery.Selection) {
+		value, _ := s.Attr("value")
+		if year, err := strconv.Atoi(strings.TrimPrefix(value, "year-")); err == nil && strings.HasPrefix(value, "year-") {
+			page.years = append(page.years, year)
+		}
+	})
+	sort.Sort(sort.Reverse(sort.IntSlice(page.years)))
+
 	var orders []models.Order
 
 	// Find all order elements
 	doc.Find(".order").Each(func(i int, s *goquery.Selection) {
-		order := models.Order{}
+		order := models.Order{Currency: m.Currency}
 
 		// Extract order ID from data attribute
 		if orderID, exists := s.Attr("data-order-id"); exists {
@@ -241,8 +690,7 @@ func parseOrdersHTML(html []byte) ([]models.Order, error) {
 		} else {
 			// Try to extract from order number text
 			orderNumText := s.Find(".order-number").Text()
-			re := regexp.MustCompile(`\d{3}-\d{7}-\d{7}`)
-			if match := re.FindString(orderNumText); match != "" {
+			if match := m.FindOrderID(orderNumText); match != "" {
 				order.OrderID = match
 			}
 		}
@@ -253,40 +701,105 @@ func parseOrdersHTML(html []byte) ([]models.Order, error) {
 
 		// Extract order total
 		totalText := s.Find(".order-total").Text()
-		order.Total = parsePrice(totalText)
+		order.Total = m.ParsePrice(totalText)
+
+		// Digital order IDs start with "D"
+		order.Type = orderTypeOf(order.OrderID)
 
 		// Extract order status from delivery status text
-		statusText := strings.ToLower(strings.TrimSpace(s.Find(".delivery-status").Text()))
-		if strings.Contains(statusText, "delivered") {
-			order.Status = "delivered"
-		} else if strings.Contains(statusText, "arriving") || strings.Contains(statusText, "shipping") {
-			order.Status = "pending"
-		} else if strings.Contains(statusText, "cancelled") {
-			order.Status = "cancelled"
-		} else if strings.Contains(statusText, "returned") {
-			order.Status = "returned"
-		} else {
-			order.Status = "unknown"
+		order.Status = normalizeOrderStatus(s.Find(".delivery-status").Text(), m)
+		if order.Type == models.OrderTypeDigital && order.Status == models.OrderStatusUnknown {
+			// Digital orders are delivered on purchase and usually show no status
+			order.Status = models.OrderStatusDelivered
 		}
 
+		// Extract the item rows shown on the order card
+		order.Items = parseOrderCardItems(s, m)
+
 		// Only add orders that have at least an order ID
 		if order.OrderID != "" {
 			orders = append(orders, order)
 		}
 	})
 
-	return orders, nil
+	page.orders = orders
+	return page, nil
+}
+
+// productLinkRegex extracts the ASIN from product links such as /dp/B08N5WRWNW or /gp/product/B08N5WRWNW
+var productLinkRegex = regexp.MustCompile(`/(?:dp|gp/product)/([A-Z0-9]{10})`)
+
+// parseOrderCardItems extracts the items listed on an order-history card
+func parseOrderCardItems(card *goquery.Selection, m *marketplace.Marketplace) []models.OrderItem {
+	items := []models.OrderItem{}
+
+	card.Find(".item, .yohtmlc-item").Each(func(i int, s *goquery.Selection) {
+		item := models.OrderItem{Quantity: 1}
+
+		// Extract ASIN from a data attribute, falling back to the product link
+		if asin, exists := s.Attr("data-asin"); exists {
+			item.ASIN = asin
+		} else if asin, exists := s.Find("[data-asin]").First().Attr("data-asin"); exists {
+			item.ASIN = asin
+		}
+		productLink := s.Find(`a[href*="/dp/"], a[href*="/gp/product/"]`)
+		if item.ASIN == "" {
+			href, _ := productLink.First().Attr("href")
+			if match := productLinkRegex.FindStringSubmatch(href); match != nil {
+				item.ASIN = match[1]
+			}
+		}
+
+		// Extract title, falling back to the text of the product link
+		item.Title = strings.TrimSpace(s.Find(".item-title, .yohtmlc-product-title").First().Text())
+		if item.Title == "" {
+			productLink.EachWithBreak(func(i int, link *goquery.Selection) bool {
+				item.Title = strings.TrimSpace(link.Text())
+				return item.Title == ""
+			})
+		}
+
+		// Extract quantity (e.g. "Qty: 2"); a single unit shows no quantity
+		quantityText := s.Find(".item-quantity, .item-view-qty").First().Text()
+		if digits := strings.TrimFunc(quantityText, func(r rune) bool { return r < '0' || r > '9' }); digits != "" {
+			if quantity, err := strconv.Atoi(digits); err == nil && quantity > 0 {
+				item.Quantity = quantity
+			}
+		}
+
+		// Extract price
+		item.Price = m.ParsePrice(s.Find(".item-price, .a-color-price").First().Text())
+
+		// Extract product image
+		if src, exists := s.Find("img").First().Attr("src"); exists {
+			item.Image = src
+		}
+
+		// Extract the format and delivery of digital items
+		parseDigitalItemFields(s, &item)
+
+		// Only add item if we have at least ASIN or title
+		if item.ASIN != "" || item.Title != "" {
+			items = append(items, item)
+		}
+	})
+
+	return items
 }
 
-// parseOrderDetailHTML parses Amazon order detail HTML and extracts complete order information
-func parseOrderDetailHTML(html []byte) (*models.Order, error) {
+// parseOrderDetailHTML parses Amazon order detail HTML and extracts complete order information.
+// Prices and dates are interpreted using the given marketplace (nil means US).
+func parseOrderDetailHTML(html []byte, m *marketplace.Marketplace) (*models.Order, error) {
+	m = marketplaceOrDefault(m)
+
 	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(html))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse HTML: %w", err)
 	}
 
 	order := &models.Order{
-		Items: []models.OrderItem{},
+		Currency: m.Currency,
+		Items:    []models.OrderItem{},
 	}
 
 	// Extract order ID
@@ -305,7 +818,7 @@ func parseOrderDetailHTML(html []byte) (*models.Order, error) {
 	dateText := doc.Find(".order-date .value").Text()
 	if dateText != "" {
 		// Try to parse the date and convert to YYYY-MM-DD format
-		parsedDate, err := time.Parse("January 2, 2006", strings.TrimSpace(dateText))
+		parsedDate, err := m.ParseDate(dateText)
 		if err == nil {
 			order.Date = parsedDate.Format("2006-01-02")
 		} else {
@@ -316,17 +829,70 @@ func parseOrderDetailHTML(html []byte) (*models.Order, error) {
 	// Extract total
 	totalText := doc.Find(".order-total .value").Text()
 	if totalText != "" {
-		order.Total = parsePrice(totalText)
+		order.Total = m.ParsePrice(totalText)
 	}
 
 	// Extract status
 	statusText := doc.Find(".order-status .status-badge").Text()
 	if statusText != "" {
-		order.Status = strings.ToLower(strings.TrimSpace(statusText))
+		order.Status = normalizeOrderStatus(statusText, m)
+	}
+	order.Type = orderTypeOf(order.OrderID)
+	if order.Type == models.OrderTypeDigital && (order.Status == "" || order.Status == models.OrderStatusUnknown) {
+		order.Status = models.OrderStatusDelivered
 	}
 
 	// Extract order items
-	doc.Find(".order-item").Each(func(i int, s *goquery.Selection) {
+	order.Items = parseOrderDetailItems(doc.Selection, m)
+
+	// Extract tracking information if present
+	order.Tracking = parseTrackingSection(doc.Find(".tracking-section").First(), m)
+
+	// Extract shipments; orders without shipment groups ship as a single package
+	doc.Find(".shipment").Each(func(i int, s *goquery.Selection) {
+		shipment := models.Shipment{
+			Status:   normalizeOrderStatus(s.Find(".shipment-status").First().Text(), m),
+			Items:    parseOrderDetailItems(s, m),
+			Tracking: parseTrackingSection(s.Find(".tracking-section").First(), m),
+		}
+		if id, exists := s.Attr("data-shipment-id"); exists {
+			shipment.ShipmentID = id
+		}
+		order.Shipments = append(order.Shipments, shipment)
+	})
+	if len(order.Shipments) == 0 && len(order.Items) > 0 {
+		order.Shipments = []models.Shipment{{
+			Status:   order.Status,
+			Items:    order.Items,
+			Tracking: order.Tracking,
+		}}
+	}
+
+	// Extract shipping address
+	order.ShippingAddress = parseAddress(doc.Find(".shipping-section .address div"), m)
+
+	// Extract payment method
+	order.PaymentMethod = parsePaymentMethod(doc.Find(".payment-section .payment-info").Text(), m)
+
+	// Extract charges breakdown
+	order.Charges = parseOrderCharges(doc.Find(".order-summary .summary-row, #od-subtotals .a-row"), m)
+	if order.Total == 0 && order.Charges != nil {
+		order.Total = order.Charges.Total
+	}
+
+	// Validate that we extracted essential information
+	if order.OrderID == "" {
+		return nil, fmt.Errorf("failed to extract order ID from HTML")
+	}
+
+	return order, nil
+}
+
+// parseOrderDetailItems extracts the order items within a selection of the order detail page
+func parseOrderDetailItems(sel *goquery.Selection, m *marketplace.Marketplace) []models.OrderItem {
+	items := []models.OrderItem{}
+
+	sel.Find(".order-item").Each(func(i int, s *goquery.Selection) {
 		item := models.OrderItem{}
 
 		// Extract ASIN from data attribute or text
@@ -349,7 +915,7 @@ func parseOrderDetailHTML(html []byte) (*models.Order, error) {
 		// Extract price
 		priceText := s.Find(".item-price .value").Text()
 		if priceText != "" {
-			item.Price = parsePrice(priceText)
+			item.Price = m.ParsePrice(priceText)
 		}
 
 		// Extract quantity
@@ -361,83 +927,180 @@ func parseOrderDetailHTML(html []byte) (*models.Order, error) {
 			}
 		}
 
+		// Extract seller ("Sold by: ...")
+		seller := s.Find(".item-seller .value").Text()
+		if seller == "" {
+			if match := soldByRegex.FindStringSubmatch(s.Text()); match != nil {
+				seller = match[1]
+			}
+		}
+		item.Seller = strings.TrimSpace(seller)
+
+		// Extract category when the page shows one
+		item.Category = strings.TrimSpace(s.Find(".item-category .value").Text())
+
+		// Extract the format and delivery of digital items
+		parseDigitalItemFields(s, &item)
+
 		// Only add item if we have at least ASIN and title
 		if item.ASIN != "" && item.Title != "" {
-			order.Items = append(order.Items, item)
+			items = append(items, item)
 		}
 	})
 
-	// Extract tracking information if present
-	trackingSection := doc.Find(".tracking-section")
-	if trackingSection.Length() > 0 {
-		tracking := &models.Tracking{}
+	return items
+}
 
-		carrier := trackingSection.Find(".tracking-carrier .value").Text()
-		if carrier != "" {
-			tracking.Carrier = strings.TrimSpace(carrier)
-		}
+// soldByRegex extracts the seller name from "Sold by: ..." text
+var soldByRegex = regexp.MustCompile(`Sold by:?\s*([^\n]+)`)
 
-		trackingNumber := trackingSection.Find(".tracking-number .value").Text()
-		if trackingNumber != "" {
-			tracking.TrackingNumber = strings.TrimSpace(trackingNumber)
-		}
+// parseTrackingSection extracts tracking information from a tracking section.
+// It returns nil if the section has neither a carrier nor a tracking number.
+func parseTrackingSection(section *goquery.Selection, m *marketplace.Marketplace) *models.Tracking {
+	if section.Length() == 0 {
+		return nil
+	}
 
-		status := trackingSection.Find(".tracking-status .value").Text()
-		if status != "" {
-			tracking.Status = strings.ToLower(strings.TrimSpace(status))
-		}
+	tracking := &models.Tracking{}
 
-		deliveryDate := trackingSection.Find(".delivery-date .value").Text()
-		if deliveryDate != "" {
-			// Try to parse the date and convert to YYYY-MM-DD format
-			parsedDate, err := time.Parse("January 2, 2006", strings.TrimSpace(deliveryDate))
-			if err == nil {
-				tracking.DeliveryDate = parsedDate.Format("2006-01-02")
-			} else {
-				tracking.DeliveryDate = strings.TrimSpace(deliveryDate)
-			}
+	carrier := section.Find(".tracking-carrier .value").Text()
+	if carrier != "" {
+		tracking.Carrier = strings.TrimSpace(carrier)
+	}
+
+	trackingNumber := section.Find(".tracking-number .value").Text()
+	if trackingNumber != "" {
+		tracking.TrackingNumber = strings.TrimSpace(trackingNumber)
+	}
+
+	status := section.Find(".tracking-status .value").Text()
+	if status != "" {
+		tracking.Status = strings.ToLower(strings.TrimSpace(status))
+	}
+
+	deliveryDate := section.Find(".delivery-date .value").Text()
+	if deliveryDate != "" {
+		// Try to parse the date and convert to YYYY-MM-DD format
+		parsedDate, err := m.ParseDate(deliveryDate)
+		if err == nil {
+			tracking.DeliveryDate = parsedDate.Format("2006-01-02")
+		} else {
+			tracking.DeliveryDate = strings.TrimSpace(deliveryDate)
 		}
+	}
+
+	// Only return tracking if we have at least a tracking number or carrier
+	if tracking.TrackingNumber == "" && tracking.Carrier == "" {
+		return nil
+	}
+	identifyCarrier(tracking)
+	return tracking
+}
+
+// identifyCarrier sets the normalized carrier code and public tracking URL of a package
+func identifyCarrier(tracking *models.Tracking) {
+	tracking.CarrierCode, tracking.TrackingURL = carrier.Identify(tracking.Carrier, tracking.TrackingNumber)
+}
 
-		// Only set tracking if we have at least a tracking number or carrier
-		if tracking.TrackingNumber != "" || tracking.Carrier != "" {
-			order.Tracking = tracking
+// parseAddress builds an address from its display lines: name, street lines,
+// city/state/postal code and country. The city line is split in the marketplace's
+// format. It returns nil if there are no lines.
+func parseAddress(lines *goquery.Selection, m *marketplace.Marketplace) *models.Address {
+	var parts []string
+	lines.Each(func(i int, s *goquery.Selection) {
+		if text := strings.TrimSpace(s.Text()); text != "" {
+			parts = append(parts, text)
 		}
+	})
+	if len(parts) == 0 {
+		return nil
 	}
 
-	// Validate that we extracted essential information
-	if order.OrderID == "" {
-		return nil, fmt.Errorf("failed to extract order ID from HTML")
+	address := &models.Address{Name: parts[0]}
+	if len(parts) < 4 {
+		address.Street = strings.Join(parts[1:], ", ")
+		return address
 	}
 
-	return order, nil
+	address.Country = parts[len(parts)-1]
+	address.Street = strings.Join(parts[1:len(parts)-2], ", ")
+	address.City, address.State, address.Zip, _ = marketplaceOrDefault(m).ParseCityLine(parts[len(parts)-2])
+
+	return address
 }
 
-// parsePrice extracts a float64 price from a price string (e.g., "$29.99" -> 29.99)
-func parsePrice(priceStr string) float64 {
-	// Remove currency symbols and whitespace
-	priceStr = strings.TrimSpace(priceStr)
+// parsePaymentMethod summarizes the payment method text, recognizing the
+// marketplace's "ending in" phrase. It returns nil if the text is empty.
+func parsePaymentMethod(text string, m *marketplace.Marketplace) *models.PaymentMethod {
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return nil
+	}
 
-	// Use regex to extract numeric value
-	re := regexp.MustCompile(`[\d,]+\.?\d*`)
-	match := re.FindString(priceStr)
-	if match == "" {
-		return 0.0
+	if cardType, last4, ok := marketplaceOrDefault(m).ParsePaymentCard(text); ok {
+		return &models.PaymentMethod{Type: cardType, Last4: last4}
 	}
+	return &models.PaymentMethod{Type: text}
+}
 
-	// Remove commas
-	match = strings.ReplaceAll(match, ",", "")
+// parseOrderCharges extracts the charges breakdown from order summary rows
+// (label followed by amount), matching labels in the marketplace's language.
+// "Free Shipping" rows count as promotions. It returns nil if no charges are recognized.
+func parseOrderCharges(rows *goquery.Selection, m *marketplace.Marketplace) *models.OrderCharges {
+	charges := &models.OrderCharges{}
+	found := false
+
+	rows.Each(func(i int, s *goquery.Selection) {
+		label := s.Find(".label, .a-column").First().Text()
+		amount := m.ParsePrice(s.Find(".value, .a-column").Last().Text())
+
+		switch m.ChargeKind(label) {
+		// Deductions are shown negative ("-$5.00") but stored as the amount deducted
+		case marketplace.ChargeGiftCard:
+			charges.GiftCard = math.Abs(amount)
+		case marketplace.ChargePromotion:
+			charges.Promotions += math.Abs(amount)
+		case marketplace.ChargeSubtotal:
+			charges.Subtotal = amount
+		case marketplace.ChargeShipping:
+			charges.Shipping += amount
+		case marketplace.ChargeRefund:
+			charges.Refund = math.Abs(amount)
+		case marketplace.ChargeTax:
+			charges.Tax = amount
+		case marketplace.ChargeTotal:
+			charges.Total = amount
+		default:
+			// Unknown rows, and the total before tax, which is derived from the others
+			return
+		}
+		found = true
+	})
 
-	// Parse to float
-	price, err := strconv.ParseFloat(match, 64)
-	if err != nil {
-		return 0.0
+	if !found {
+		return nil
 	}
+	return charges
+}
+
+// parsePrice extracts a float64 price from a US-formatted price string (e.g., "$29.99" -> 29.99)
+func parsePrice(priceStr string) float64 {
+	return marketplace.Default().ParsePrice(priceStr)
+}
 
-	return price
+// marketplaceOrDefault returns m, or the default marketplace if m is nil
+func marketplaceOrDefault(m *marketplace.Marketplace) *marketplace.Marketplace {
+	if m == nil {
+		return marketplace.Default()
+	}
+	return m
 }
 
-// parseTrackingHTML parses Amazon tracking page HTML and extracts tracking information
-func parseTrackingHTML(html []byte) (*models.Tracking, error) {
+// parseTrackingHTML parses Amazon tracking page HTML and extracts tracking information.
+// Dates are interpreted using the given marketplace (nil means US).
+func parseTrackingHTML(html []byte, m *marketplace.Marketplace) (*models.Tracking, error) {
+	m = marketplaceOrDefault(m)
+
 	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(html))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse HTML: %w", err)
@@ -469,7 +1132,7 @@ func parseTrackingHTML(html []byte) (*models.Tracking, error) {
 	deliveryDate := doc.Find(".delivery-date .value").Text()
 	if deliveryDate != "" {
 		// Try to parse the date and convert to YYYY-MM-DD format
-		parsedDate, err := time.Parse("January 2, 2006", strings.TrimSpace(deliveryDate))
+		parsedDate, err := m.ParseDate(deliveryDate)
 		if err == nil {
 			tracking.DeliveryDate = parsedDate.Format("2006-01-02")
 		} else {
@@ -484,7 +1147,7 @@ func parseTrackingHTML(html []byte) (*models.Tracking, error) {
 		timestamp := s.Find(".event-timestamp").Text()
 		if timestamp != "" {
 			// Try to parse timestamp and convert to RFC3339
-			parsedTime, err := time.Parse("January 2, 2006 3:04 PM", strings.TrimSpace(timestamp))
+			parsedTime, err := m.ParseDateTime(timestamp)
 			if err == nil {
 				event.Timestamp = parsedTime.Format(time.RFC3339)
 			} else {
@@ -512,6 +1175,7 @@ func parseTrackingHTML(html []byte) (*models.Tracking, error) {
 	if tracking.TrackingNumber == "" && tracking.Carrier == "" {
 		return nil, fmt.Errorf("failed to extract tracking information from HTML")
 	}
+	identifyCarrier(tracking)
 
 	return tracking, nil
 }
@@ -1,11 +1,22 @@
 package amazon
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/zkwentz/amazon-cli/internal/marketplace"
+	"github.com/zkwentz/amazon-cli/internal/ratelimit"
+	"github.com/zkwentz/amazon-cli/pkg/models"
 )
 
 func TestParseOrdersHTML_ReturnsCorrectCount(t *testing.T) {
@@ -16,7 +27,7 @@ func TestParseOrdersHTML_ReturnsCorrectCount(t *testing.T) {
 	}
 
 	// Parse the HTML
-	orders, err := parseOrdersHTML(fixtureData)
+	orders, err := parseOrdersHTML(fixtureData, marketplace.Default())
 	if err != nil {
 		t.Fatalf("parseOrdersHTML failed: %v", err)
 	}
@@ -36,7 +47,7 @@ func TestParseOrdersHTML_ExtractsAllFields(t *testing.T) {
 	}
 
 	// Parse the HTML
-	orders, err := parseOrdersHTML(fixtureData)
+	orders, err := parseOrdersHTML(fixtureData, marketplace.Default())
 	if err != nil {
 		t.Fatalf("parseOrdersHTML failed: %v", err)
 	}
@@ -66,7 +77,7 @@ func TestParseOrdersHTML_ExtractsOrderIDs(t *testing.T) {
 	}
 
 	// Parse the HTML
-	orders, err := parseOrdersHTML(fixtureData)
+	orders, err := parseOrdersHTML(fixtureData, marketplace.Default())
 	if err != nil {
 		t.Fatalf("parseOrdersHTML failed: %v", err)
 	}
@@ -98,7 +109,7 @@ func TestParseOrdersHTML_ExtractsTotals(t *testing.T) {
 	}
 
 	// Parse the HTML
-	orders, err := parseOrdersHTML(fixtureData)
+	orders, err := parseOrdersHTML(fixtureData, marketplace.Default())
 	if err != nil {
 		t.Fatalf("parseOrdersHTML failed: %v", err)
 	}
@@ -126,7 +137,7 @@ func TestParseOrdersHTML_ExtractsStatuses(t *testing.T) {
 	}
 
 	// Parse the HTML
-	orders, err := parseOrdersHTML(fixtureData)
+	orders, err := parseOrdersHTML(fixtureData, marketplace.Default())
 	if err != nil {
 		t.Fatalf("parseOrdersHTML failed: %v", err)
 	}
@@ -149,7 +160,7 @@ func TestParseOrdersHTML_ExtractsStatuses(t *testing.T) {
 func TestParseOrdersHTML_EmptyHTML(t *testing.T) {
 	html := []byte(`<html><body><div id="ordersContainer"></div></body></html>`)
 
-	orders, err := parseOrdersHTML(html)
+	orders, err := parseOrdersHTML(html, marketplace.Default())
 	if err != nil {
 		t.Fatalf("parseOrdersHTML failed: %v", err)
 	}
@@ -164,7 +175,7 @@ func TestParseOrdersHTML_InvalidHTML(t *testing.T) {
 
 	// This should still parse without error (goquery is lenient)
 	// but return no orders
-	orders, err := parseOrdersHTML(html)
+	orders, err := parseOrdersHTML(html, marketplace.Default())
 	if err != nil {
 		t.Fatalf("parseOrdersHTML failed: %v", err)
 	}
@@ -190,7 +201,7 @@ func TestParseOrdersHTML_PartialData(t *testing.T) {
 		</html>
 	`)
 
-	orders, err := parseOrdersHTML(html)
+	orders, err := parseOrdersHTML(html, marketplace.Default())
 	if err != nil {
 		t.Fatalf("parseOrdersHTML failed: %v", err)
 	}
@@ -607,7 +618,7 @@ func TestParseTrackingHTML_ValidHTML(t *testing.T) {
 	}
 
 	// Parse the HTML
-	tracking, err := parseTrackingHTML(fixtureData)
+	tracking, err := parseTrackingHTML(fixtureData, marketplace.Default())
 	if err != nil {
 		t.Fatalf("parseTrackingHTML failed: %v", err)
 	}
@@ -672,7 +683,7 @@ func TestParseTrackingHTML_MinimalData(t *testing.T) {
 		</html>
 	`)
 
-	tracking, err := parseTrackingHTML(html)
+	tracking, err := parseTrackingHTML(html, marketplace.Default())
 	if err != nil {
 		t.Fatalf("parseTrackingHTML failed: %v", err)
 	}
@@ -705,7 +716,7 @@ func TestParseTrackingHTML_NoTrackingInfo(t *testing.T) {
 		</html>
 	`)
 
-	_, err := parseTrackingHTML(html)
+	_, err := parseTrackingHTML(html, marketplace.Default())
 	if err == nil {
 		t.Error("Expected error for HTML without tracking info, got nil")
 	}
@@ -742,7 +753,7 @@ func TestParseTrackingHTML_WithEvents(t *testing.T) {
 		</html>
 	`)
 
-	tracking, err := parseTrackingHTML(html)
+	tracking, err := parseTrackingHTML(html, marketplace.Default())
 	if err != nil {
 		t.Fatalf("parseTrackingHTML failed: %v", err)
 	}
@@ -784,7 +795,7 @@ func TestParseTrackingHTML_DateParsing(t *testing.T) {
 		</html>
 	`)
 
-	tracking, err := parseTrackingHTML(html)
+	tracking, err := parseTrackingHTML(html, marketplace.Default())
 	if err != nil {
 		t.Fatalf("parseTrackingHTML failed: %v", err)
 	}
@@ -814,7 +825,7 @@ func TestParseTrackingHTML_StatusNormalization(t *testing.T) {
 		</html>
 	`)
 
-	tracking, err := parseTrackingHTML(html)
+	tracking, err := parseTrackingHTML(html, marketplace.Default())
 	if err != nil {
 		t.Fatalf("parseTrackingHTML failed: %v", err)
 	}
@@ -848,7 +859,7 @@ func TestParseTrackingHTML_EmptyEvents(t *testing.T) {
 		</html>
 	`)
 
-	tracking, err := parseTrackingHTML(html)
+	tracking, err := parseTrackingHTML(html, marketplace.Default())
 	if err != nil {
 		t.Fatalf("parseTrackingHTML failed: %v", err)
 	}
@@ -929,6 +940,10 @@ func TestGetOrderTracking_Success(t *testing.T) {
 		t.Errorf("Expected DeliveryDate 2026-01-20, got %s", tracking.DeliveryDate)
 	}
 
+	if tracking.CarrierCode != "ups" || tracking.TrackingURL != "https://www.ups.com/track?tracknum=1Z999AA10123456784" {
+		t.Errorf("Expected UPS carrier code and tracking URL, got %s %s", tracking.CarrierCode, tracking.TrackingURL)
+	}
+
 	if len(tracking.Events) != 3 {
 		t.Errorf("Expected 3 events, got %d", len(tracking.Events))
 	}
@@ -1030,3 +1045,855 @@ func TestGetOrderTracking_ValidOrderIDFormat(t *testing.T) {
 		}
 	}
 }
+
+func TestParseOrdersHTML_UKMarketplace(t *testing.T) {
+	fixtureData, err := os.ReadFile(filepath.Join("..", "..", "testdata", "orders", "order_list_sample_uk.html"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture file: %v", err)
+	}
+
+	uk, _ := marketplace.Get("uk")
+	orders, err := parseOrdersHTML(fixtureData, uk)
+	if err != nil {
+		t.Fatalf("parseOrdersHTML returned error: %v", err)
+	}
+
+	if len(orders) != 2 {
+		t.Fatalf("Expected 2 orders, got %d", len(orders))
+	}
+
+	if orders[0].Total != 1249.00 {
+		t.Errorf("Expected total 1249.00, got %.2f", orders[0].Total)
+	}
+	if orders[0].Currency != "GBP" {
+		t.Errorf("Expected currency GBP, got %s", orders[0].Currency)
+	}
+	if orders[0].Status != "delivered" {
+		t.Errorf("Expected status delivered, got %s", orders[0].Status)
+	}
+
+	// The second order has no data attribute; its ID comes from the order number text
+	if orders[1].OrderID != "202-3333333-4444444" {
+		t.Errorf("Expected order ID 202-3333333-4444444, got %s", orders[1].OrderID)
+	}
+	if orders[1].Total != 12.50 {
+		t.Errorf("Expected total 12.50, got %.2f", orders[1].Total)
+	}
+}
+
+func TestParseOrdersHTML_DEMarketplace(t *testing.T) {
+	fixtureData, err := os.ReadFile(filepath.Join("..", "..", "testdata", "orders", "order_list_sample_de.html"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture file: %v", err)
+	}
+
+	de, _ := marketplace.Get("de")
+	orders, err := parseOrdersHTML(fixtureData, de)
+	if err != nil {
+		t.Fatalf("parseOrdersHTML returned error: %v", err)
+	}
+
+	if len(orders) != 2 {
+		t.Fatalf("Expected 2 orders, got %d", len(orders))
+	}
+
+	expectedTotals := []float64{1299.99, 24.90}
+	for i, order := range orders {
+		if order.Total != expectedTotals[i] {
+			t.Errorf("Order %d: expected total %.2f, got %.2f", i, expectedTotals[i], order.Total)
+		}
+		if order.Currency != "EUR" {
+			t.Errorf("Order %d: expected currency EUR, got %s", i, order.Currency)
+		}
+	}
+
+	if orders[1].OrderID != "302-3333333-4444444" {
+		t.Errorf("Expected order ID 302-3333333-4444444, got %s", orders[1].OrderID)
+	}
+
+	// German status text ("Zugestellt", "Storniert") is normalized like English
+	expectedStatuses := []string{models.OrderStatusDelivered, models.OrderStatusCancelled}
+	for i, order := range orders {
+		if order.Status != expectedStatuses[i] {
+			t.Errorf("Order %d: expected status %s, got %s", i, expectedStatuses[i], order.Status)
+		}
+	}
+}
+
+func TestParseOrderDetailHTML_DEMarketplace(t *testing.T) {
+	fixtureData, err := os.ReadFile(filepath.Join("..", "..", "testdata", "orders", "order_detail_sample_de.html"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture file: %v", err)
+	}
+
+	de, _ := marketplace.Get("de")
+	order, err := parseOrderDetailHTML(fixtureData, de)
+	if err != nil {
+		t.Fatalf("parseOrderDetailHTML returned error: %v", err)
+	}
+
+	if order.OrderID != "302-1111111-2222222" {
+		t.Errorf("Expected order ID 302-1111111-2222222, got %s", order.OrderID)
+	}
+	if order.Date != "2026-01-15" {
+		t.Errorf("Expected date 2026-01-15, got %s", order.Date)
+	}
+	if order.Total != 1324.89 {
+		t.Errorf("Expected total 1324.89, got %.2f", order.Total)
+	}
+	if order.Currency != "EUR" {
+		t.Errorf("Expected currency EUR, got %s", order.Currency)
+	}
+	if order.Status != models.OrderStatusDelivered {
+		t.Errorf("Expected status %s, got %s", models.OrderStatusDelivered, order.Status)
+	}
+
+	if len(order.Items) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(order.Items))
+	}
+	if order.Items[0].Price != 1299.99 {
+		t.Errorf("Expected item price 1299.99, got %.2f", order.Items[0].Price)
+	}
+	if order.Items[1].Price != 24.90 {
+		t.Errorf("Expected item price 24.90, got %.2f", order.Items[1].Price)
+	}
+
+	if order.Tracking == nil {
+		t.Fatal("Expected tracking information")
+	}
+	if order.Tracking.Carrier != "DHL" {
+		t.Errorf("Expected carrier DHL, got %s", order.Tracking.Carrier)
+	}
+	if order.Tracking.DeliveryDate != "2026-01-18" {
+		t.Errorf("Expected delivery date 2026-01-18, got %s", order.Tracking.DeliveryDate)
+	}
+
+	expectedCharges := models.OrderCharges{
+		Subtotal:   1324.89,
+		Shipping:   4.99,
+		Tax:        211.54,
+		Promotions: 4.99,
+		Total:      1324.89,
+	}
+	if order.Charges == nil || *order.Charges != expectedCharges {
+		t.Errorf("Expected charges %+v, got %+v", expectedCharges, order.Charges)
+	}
+}
+
+// orderHistoryServer serves generated order-history pages. Orders are keyed by
+// time filter ("" for the default view); pages hold orderHistoryPageSize orders.
+func orderHistoryServer(t *testing.T, views map[string][]models.Order, years []int, requests *[]string) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r.URL.RawQuery)
+
+		// Behave like a marketplace without an order search page
+		if r.URL.Path != "/gp/your-account/order-history" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		orders := views[r.URL.Query().Get("timeFilter")]
+		start, _ := strconv.Atoi(r.URL.Query().Get("startIndex"))
+		end := start + orderHistoryPageSize
+		if end > len(orders) {
+			end = len(orders)
+		}
+		if start > end {
+			start = end
+		}
+
+		var html strings.Builder
+		html.WriteString(`<html><body><select name="timeFilter"><option value="last30">last 30 days</option>`)
+		for _, year := range years {
+			fmt.Fprintf(&html, `<option value="year-%d">%d</option>`, year, year)
+		}
+		html.WriteString(`</select><div id="ordersContainer">`)
+		for _, order := range orders[start:end] {
+			fmt.Fprintf(&html, `<div class="order" data-order-id="%s"><span class="order-date">%s</span><span class="order-total">$%.2f</span><span class="delivery-status">Delivered</span></div>`,
+				order.OrderID, order.Date, order.Total)
+		}
+		html.WriteString(`</div><ul class="a-pagination">`)
+		if end < len(orders) {
+			html.WriteString(`<li class="a-last"><a href="?startIndex=next">Next</a></li>`)
+		} else {
+			html.WriteString(`<li class="a-disabled a-last">Next</li>`)
+		}
+		html.WriteString(`</ul></body></html>`)
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(html.String()))
+	}))
+}
+
+// generateOrders creates n orders placed on consecutive days counting back from newest
+func generateOrders(prefix string, newest time.Time, n int) []models.Order {
+	orders := make([]models.Order, n)
+	for i := range orders {
+		orders[i] = models.Order{
+			OrderID: fmt.Sprintf("%s-%07d-%07d", prefix, i, i),
+			Date:    newest.AddDate(0, 0, -i).Format("January 2, 2006"),
+			Total:   float64(i + 1),
+		}
+	}
+	return orders
+}
+
+func TestGetOrders_FollowsPagination(t *testing.T) {
+	var requests []string
+	views := map[string][]models.Order{
+		"": generateOrders("111", time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC), 25),
+	}
+	server := orderHistoryServer(t, views, nil, &requests)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithRateLimiter(ratelimit.NewRateLimiter(0, 0, 0)))
+
+	response, err := client.GetOrders(15, "")
+	if err != nil {
+		t.Fatalf("GetOrders() error = %v", err)
+	}
+	if len(response.Orders) != 15 {
+		t.Fatalf("Expected 15 orders, got %d", len(response.Orders))
+	}
+	if len(requests) != 2 {
+		t.Errorf("Expected 2 page requests for 15 orders, got %d: %v", len(requests), requests)
+	}
+
+	requests = nil
+	response, err = client.ListOrders(OrderQuery{})
+	if err != nil {
+		t.Fatalf("ListOrders() error = %v", err)
+	}
+	if response.TotalCount != 25 {
+		t.Errorf("Expected all 25 orders without a limit, got %d", response.TotalCount)
+	}
+
+	expected := []string{"", "startIndex=10", "startIndex=20"}
+	if strings.Join(requests, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected requests %v, got %v", expected, requests)
+	}
+}
+
+func TestWalkOrders_AllWalksEveryYear(t *testing.T) {
+	var requests []string
+	views := map[string][]models.Order{
+		"year-2026": generateOrders("126", time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC), 2),
+		"year-2025": generateOrders("125", time.Date(2025, 12, 20, 0, 0, 0, 0, time.UTC), 11),
+		"year-2024": generateOrders("124", time.Date(2024, 7, 4, 0, 0, 0, 0, time.UTC), 1),
+		"year-2023": generateOrders("123", time.Date(2023, 7, 4, 0, 0, 0, 0, time.UTC), 1),
+	}
+	server := orderHistoryServer(t, views, []int{2026, 2025, 2024}, &requests)
+	defer server.Close()
+
+	clock := &fixedClock{now: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+	client := NewClient(WithBaseURL(server.URL), WithClock(clock), WithRateLimiter(ratelimit.NewRateLimiter(0, 0, 0)))
+
+	response, err := client.ListOrders(OrderQuery{All: true})
+	if err != nil {
+		t.Fatalf("ListOrders() error = %v", err)
+	}
+
+	// Years offered by the time filter bound the walk, so 2023 is never requested
+	if response.TotalCount != 14 {
+		t.Errorf("Expected 14 orders across 2024-2026, got %d", response.TotalCount)
+	}
+	expected := []string{"timeFilter=year-2026", "timeFilter=year-2025", "startIndex=10&timeFilter=year-2025", "timeFilter=year-2024"}
+	if strings.Join(requests, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected requests %v, got %v", expected, requests)
+	}
+}
+
+func TestWalkOrders_AllStopsAtEmptyYearWithoutYearList(t *testing.T) {
+	var requests []string
+	views := map[string][]models.Order{
+		"year-2026": generateOrders("126", time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC), 1),
+	}
+	server := orderHistoryServer(t, views, nil, &requests)
+	defer server.Close()
+
+	clock := &fixedClock{now: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+	client := NewClient(WithBaseURL(server.URL), WithClock(clock), WithRateLimiter(ratelimit.NewRateLimiter(0, 0, 0)))
+
+	response, err := client.ListOrders(OrderQuery{All: true})
+	if err != nil {
+		t.Fatalf("ListOrders() error = %v", err)
+	}
+	if response.TotalCount != 1 {
+		t.Errorf("Expected 1 order, got %d", response.TotalCount)
+	}
+	if len(requests) != 2 {
+		t.Errorf("Expected walk to stop after the first empty year, got requests %v", requests)
+	}
+}
+
+func TestWalkOrders_DateRangeSpansYears(t *testing.T) {
+	var requests []string
+	views := map[string][]models.Order{
+		"year-2026": generateOrders("126", time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC), 3),
+		"year-2025": generateOrders("125", time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC), 5),
+		"year-2024": generateOrders("124", time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), 25),
+	}
+	server := orderHistoryServer(t, views, []int{2026, 2025, 2024}, &requests)
+	defer server.Close()
+
+	clock := &fixedClock{now: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+	client := NewClient(WithBaseURL(server.URL), WithClock(clock), WithRateLimiter(ratelimit.NewRateLimiter(0, 0, 0)))
+
+	query := OrderQuery{
+		Since: time.Date(2024, 12, 28, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+	response, err := client.ListOrders(query)
+	if err != nil {
+		t.Fatalf("ListOrders() error = %v", err)
+	}
+
+	// Jan 2 and Jan 1 2025, then Dec 31 through Dec 28 2024
+	if response.TotalCount != 6 {
+		t.Errorf("Expected 6 orders in range, got %d", response.TotalCount)
+	}
+
+	// The walk starts at the --until year and stops at the first order older than --since
+	// without fetching the remaining 2024 pages
+	expected := []string{"timeFilter=year-2025", "timeFilter=year-2024"}
+	if strings.Join(requests, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected requests %v, got %v", expected, requests)
+	}
+}
+
+func TestWalkOrders_StreamsEachPage(t *testing.T) {
+	var requests []string
+	views := map[string][]models.Order{
+		"": generateOrders("111", time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC), 15),
+	}
+	server := orderHistoryServer(t, views, nil, &requests)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithRateLimiter(ratelimit.NewRateLimiter(0, 0, 0)))
+
+	// Orders from the first page are delivered before the second page is fetched
+	var requestsAtFirstOrder int
+	count := 0
+	err := client.WalkOrders(OrderQuery{}, func(order models.Order) error {
+		if count == 0 {
+			requestsAtFirstOrder = len(requests)
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WalkOrders() error = %v", err)
+	}
+	if requestsAtFirstOrder != 1 {
+		t.Errorf("Expected first order before the second page request, got %d requests", requestsAtFirstOrder)
+	}
+	if count != 15 {
+		t.Errorf("Expected 15 orders, got %d", count)
+	}
+
+	// An error from the callback aborts the walk
+	stop := errors.New("stop")
+	err = client.WalkOrders(OrderQuery{}, func(order models.Order) error {
+		return stop
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("Expected callback error to be returned, got %v", err)
+	}
+}
+
+func TestParseOrderHistoryPage_PaginationAndYears(t *testing.T) {
+	html := `<html><body>
+		<select name="timeFilter">
+			<option value="last30">last 30 days</option>
+			<option value="months-3">past 3 months</option>
+			<option value="year-2024">2024</option>
+			<option value="year-2026">2026</option>
+			<option value="year-2025">2025</option>
+		</select>
+		<ul class="a-pagination"><li class="a-last"><a href="/next">Next</a></li></ul>
+	</body></html>`
+
+	page, err := parseOrderHistoryPage([]byte(html), nil)
+	if err != nil {
+		t.Fatalf("parseOrderHistoryPage() error = %v", err)
+	}
+	if !page.hasNext {
+		t.Error("Expected next page to be detected")
+	}
+	if len(page.years) != 3 || page.years[0] != 2026 || page.years[2] != 2024 {
+		t.Errorf("Expected years [2026 2025 2024], got %v", page.years)
+	}
+
+	last := `<ul class="a-pagination"><li class="a-disabled a-last">Next</li></ul>`
+	page, _ = parseOrderHistoryPage([]byte(last), nil)
+	if page.hasNext {
+		t.Error("Expected disabled next link to end pagination")
+	}
+}
+
+func TestGetOrderHistory_FetchesYearView(t *testing.T) {
+	var requests []string
+	views := map[string][]models.Order{
+		"year-2024": generateOrders("124", time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC), 12),
+		"year-2025": generateOrders("125", time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), 3),
+	}
+	server := orderHistoryServer(t, views, []int{2025, 2024}, &requests)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithRateLimiter(ratelimit.NewRateLimiter(0, 0, 0)))
+
+	response, err := client.GetOrderHistory(2024)
+	if err != nil {
+		t.Fatalf("GetOrderHistory() error = %v", err)
+	}
+
+	if response.TotalCount != 12 {
+		t.Errorf("Expected 12 orders for 2024, got %d", response.TotalCount)
+	}
+	for _, order := range response.Orders {
+		if !strings.HasPrefix(order.OrderID, "124-") {
+			t.Errorf("Expected only 2024 orders, got %s", order.OrderID)
+		}
+		if order.Status != models.OrderStatusDelivered {
+			t.Errorf("Expected status delivered, got %s", order.Status)
+		}
+	}
+
+	expected := []string{"timeFilter=year-2024", "startIndex=10&timeFilter=year-2024"}
+	if strings.Join(requests, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected requests %v, got %v", expected, requests)
+	}
+}
+
+func TestGetOrderHistory_DefaultsToCurrentYear(t *testing.T) {
+	var requests []string
+	server := orderHistoryServer(t, map[string][]models.Order{}, nil, &requests)
+	defer server.Close()
+
+	clock := &fixedClock{now: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+	client := NewClient(WithBaseURL(server.URL), WithClock(clock), WithRateLimiter(ratelimit.NewRateLimiter(0, 0, 0)))
+
+	response, err := client.GetOrderHistory(0)
+	if err != nil {
+		t.Fatalf("GetOrderHistory() error = %v", err)
+	}
+
+	// No fabricated orders are returned for a year without orders
+	if response.TotalCount != 0 || len(response.Orders) != 0 {
+		t.Errorf("Expected no orders, got %d", response.TotalCount)
+	}
+	if len(requests) != 1 || requests[0] != "timeFilter=year-2026" {
+		t.Errorf("Expected a single request for 2026, got %v", requests)
+	}
+}
+
+func TestParseOrdersHTML_ExtractsItems(t *testing.T) {
+	fixtureData, err := os.ReadFile(filepath.Join("..", "..", "testdata", "orders", "order_list_sample.html"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture file: %v", err)
+	}
+
+	orders, err := parseOrdersHTML(fixtureData, marketplace.Default())
+	if err != nil {
+		t.Fatalf("parseOrdersHTML failed: %v", err)
+	}
+
+	expectedCounts := []int{1, 2, 1}
+	for i, order := range orders {
+		if len(order.Items) != expectedCounts[i] {
+			t.Errorf("Order %d: expected %d items, got %d", i, expectedCounts[i], len(order.Items))
+		}
+	}
+
+	item := orders[1].Items[1]
+	if item.ASIN != "B07ABC5678" || item.Title != "Phone Case - Clear" || item.Quantity != 1 {
+		t.Errorf("Unexpected item: %+v", item)
+	}
+}
+
+func TestParseOrdersHTML_ItemRowsFromProductLinks(t *testing.T) {
+	fixtureData, err := os.ReadFile(filepath.Join("..", "..", "testdata", "orders", "order_list_items_sample.html"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture file: %v", err)
+	}
+
+	orders, err := parseOrdersHTML(fixtureData, marketplace.Default())
+	if err != nil {
+		t.Fatalf("parseOrdersHTML failed: %v", err)
+	}
+	if len(orders) != 1 || len(orders[0].Items) != 2 {
+		t.Fatalf("Expected 1 order with 2 items, got %+v", orders)
+	}
+
+	expected := []models.OrderItem{
+		{
+			ASIN:     "B0C1H2J3K4",
+			Title:    "Replacement Water Filter (3-Pack)",
+			Quantity: 3,
+			Price:    19.99,
+			Image:    "https://m.media-amazon.com/images/I/61filter._SS142_.jpg",
+		},
+		{
+			ASIN:     "B0D9Z8Y7X6",
+			Title:    "LED Desk Lamp with USB Port",
+			Quantity: 1,
+			Price:    14.98,
+			Image:    "https://m.media-amazon.com/images/I/71lamp._SS142_.jpg",
+		},
+	}
+	for i, item := range orders[0].Items {
+		if item != expected[i] {
+			t.Errorf("Item %d: expected %+v, got %+v", i, expected[i], item)
+		}
+	}
+}
+
+func TestNeedsOrderDetail_RefundAmount(t *testing.T) {
+	order := models.Order{
+		OrderID: "111-0000000-0000001", Total: 24.99, Status: models.OrderStatusReturned,
+		Items: []models.OrderItem{{ASIN: "B08N5WRWNW", Title: "Echo Dot", Price: 24.99}},
+	}
+	if !needsOrderDetail(order) {
+		t.Error("Expected a returned order without charges to need its detail page")
+	}
+
+	order.Charges = &models.OrderCharges{Total: 24.99, Refund: 24.99}
+	if needsOrderDetail(order) {
+		t.Error("Expected a returned order with charges not to need its detail page")
+	}
+}
+
+func TestListOrders_ExpandFetchesMissingDetails(t *testing.T) {
+	listHTML, err := os.ReadFile(filepath.Join("..", "..", "testdata", "orders", "order_list_sample.html"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture file: %v", err)
+	}
+	itemsHTML, err := os.ReadFile(filepath.Join("..", "..", "testdata", "orders", "order_list_items_sample.html"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture file: %v", err)
+	}
+	detailHTML, err := os.ReadFile(filepath.Join("..", "..", "testdata", "orders", "order_detail_sample.html"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture file: %v", err)
+	}
+
+	var mu sync.Mutex
+	var detailRequests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gp/your-account/order-history":
+			_, _ = w.Write(listHTML)
+		case "/items/gp/your-account/order-history":
+			_, _ = w.Write(itemsHTML)
+		default:
+			mu.Lock()
+			detailRequests = append(detailRequests, r.URL.Query().Get("orderID"))
+			mu.Unlock()
+			_, _ = w.Write(detailHTML)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithRateLimiter(ratelimit.NewRateLimiter(0, 0, 0)))
+
+	response, err := client.ListOrders(OrderQuery{Expand: true})
+	if err != nil {
+		t.Fatalf("ListOrders() error = %v", err)
+	}
+
+	// Every order in the sample lacks item prices, so each detail page is fetched
+	if len(detailRequests) != 3 {
+		t.Errorf("Expected 3 detail requests, got %v", detailRequests)
+	}
+	for _, order := range response.Orders {
+		if len(order.Items) != 2 || order.Items[0].Price != 59.99 {
+			t.Errorf("Order %s: expected items from the detail page, got %+v", order.OrderID, order.Items)
+		}
+		if order.Tracking == nil {
+			t.Errorf("Order %s: expected tracking from the detail page", order.OrderID)
+		}
+	}
+
+	// Orders whose list entry is already complete are not fetched again
+	detailRequests = nil
+	client = NewClient(WithBaseURL(server.URL+"/items"), WithRateLimiter(ratelimit.NewRateLimiter(0, 0, 0)))
+	response, err = client.ListOrders(OrderQuery{Expand: true})
+	if err != nil {
+		t.Fatalf("ListOrders() error = %v", err)
+	}
+	if len(detailRequests) != 0 {
+		t.Errorf("Expected no detail requests for complete orders, got %v", detailRequests)
+	}
+	if response.Orders[0].Items[0].Image == "" {
+		t.Error("Expected list page item images to be kept")
+	}
+
+	// Details fetches every order's page, e.g. for the charges breakdown
+	response, err = client.ListOrders(OrderQuery{Details: true})
+	if err != nil {
+		t.Fatalf("ListOrders() error = %v", err)
+	}
+	if len(detailRequests) != len(response.Orders) {
+		t.Errorf("Expected a detail request per order, got %v", detailRequests)
+	}
+}
+
+func TestMergeOrderDetail_KeepsListImages(t *testing.T) {
+	order := models.Order{
+		OrderID: "111-2222222-3333333",
+		Status:  models.OrderStatusDelivered,
+		Items:   []models.OrderItem{{ASIN: "B08N5WRWNW", Title: "Headphones", Image: "https://example.com/a.jpg"}},
+	}
+	detail := &models.Order{
+		Total:  84.98,
+		Status: "shipped",
+		Items:  []models.OrderItem{{ASIN: "B08N5WRWNW", Title: "Headphones", Quantity: 1, Price: 59.99}},
+	}
+
+	mergeOrderDetail(&order, detail)
+
+	if order.Total != 84.98 {
+		t.Errorf("Expected total from detail, got %.2f", order.Total)
+	}
+	if order.Status != models.OrderStatusDelivered {
+		t.Errorf("Expected list status to be kept, got %s", order.Status)
+	}
+	if order.Items[0].Price != 59.99 || order.Items[0].Image != "https://example.com/a.jpg" {
+		t.Errorf("Expected detail item with list image, got %+v", order.Items[0])
+	}
+}
+
+func TestParseOrderDetailHTML_AddressAndPayment(t *testing.T) {
+	fixtureData, err := os.ReadFile(filepath.Join("..", "..", "testdata", "orders", "order_detail_sample.html"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture file: %v", err)
+	}
+
+	order, err := parseOrderDetailHTML(fixtureData, marketplace.Default())
+	if err != nil {
+		t.Fatalf("parseOrderDetailHTML returned error: %v", err)
+	}
+
+	expectedAddress := models.Address{
+		Name:    "John Doe",
+		Street:  "123 Main Street, Apartment 4B",
+		City:    "Seattle",
+		State:   "WA",
+		Zip:     "98101",
+		Country: "United States",
+	}
+	if order.ShippingAddress == nil || *order.ShippingAddress != expectedAddress {
+		t.Errorf("Expected address %+v, got %+v", expectedAddress, order.ShippingAddress)
+	}
+
+	if order.PaymentMethod == nil || order.PaymentMethod.Type != "Visa" || order.PaymentMethod.Last4 != "1234" {
+		t.Errorf("Expected Visa ending in 1234, got %+v", order.PaymentMethod)
+	}
+
+	// Without shipment groups the whole order ships as one package
+	if len(order.Shipments) != 1 {
+		t.Fatalf("Expected 1 implicit shipment, got %d", len(order.Shipments))
+	}
+	if len(order.Shipments[0].Items) != 2 || order.Shipments[0].Tracking != order.Tracking {
+		t.Errorf("Expected implicit shipment with all items and the order tracking, got %+v", order.Shipments[0])
+	}
+
+	if order.Charges != nil {
+		t.Errorf("Expected no charges breakdown, got %+v", order.Charges)
+	}
+}
+
+func TestParseOrderDetailHTML_Shipments(t *testing.T) {
+	fixtureData, err := os.ReadFile(filepath.Join("..", "..", "testdata", "orders", "order_detail_shipments_sample.html"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture file: %v", err)
+	}
+
+	order, err := parseOrderDetailHTML(fixtureData, marketplace.Default())
+	if err != nil {
+		t.Fatalf("parseOrderDetailHTML returned error: %v", err)
+	}
+
+	if order.Status != models.OrderStatusPending {
+		t.Errorf("Expected order status pending, got %s", order.Status)
+	}
+	if len(order.Items) != 2 {
+		t.Errorf("Expected 2 items across shipments, got %d", len(order.Items))
+	}
+	if len(order.Shipments) != 2 {
+		t.Fatalf("Expected 2 shipments, got %d", len(order.Shipments))
+	}
+
+	first := order.Shipments[0]
+	if first.ShipmentID != "DmF7kq3Lz" || first.Status != models.OrderStatusDelivered {
+		t.Errorf("Unexpected first shipment: %+v", first)
+	}
+	if len(first.Items) != 1 || first.Items[0].ASIN != "B0AAAA1111" || first.Items[0].Seller != "Hydro Goods LLC" {
+		t.Errorf("Unexpected first shipment items: %+v", first.Items)
+	}
+	if first.Tracking == nil || first.Tracking.TrackingNumber != "TBA123456789000" || first.Tracking.DeliveryDate != "2026-03-05" {
+		t.Errorf("Unexpected first shipment tracking: %+v", first.Tracking)
+	}
+	if first.Tracking.CarrierCode != "amazon" || first.Tracking.TrackingURL != "https://track.amazon.com/tracking/TBA123456789000" {
+		t.Errorf("Expected Amazon Logistics carrier code and tracking URL, got %+v", first.Tracking)
+	}
+
+	second := order.Shipments[1]
+	if second.Status != models.OrderStatusOutForDelivery {
+		t.Errorf("Expected second shipment out_for_delivery, got %s", second.Status)
+	}
+	if len(second.Items) != 1 || second.Items[0].Quantity != 2 || second.Items[0].Seller != "Amazon.com Services LLC" {
+		t.Errorf("Unexpected second shipment items: %+v", second.Items)
+	}
+	if second.Tracking == nil || second.Tracking.Carrier != "USPS" {
+		t.Errorf("Unexpected second shipment tracking: %+v", second.Tracking)
+	}
+
+	// The order-level tracking remains the first shipment's for compatibility
+	if order.Tracking == nil || order.Tracking.TrackingNumber != "TBA123456789000" {
+		t.Errorf("Expected order tracking from the first shipment, got %+v", order.Tracking)
+	}
+
+	if order.ShippingAddress == nil || order.ShippingAddress.Street != "500 Pine Street" || order.ShippingAddress.City != "Portland" {
+		t.Errorf("Unexpected shipping address: %+v", order.ShippingAddress)
+	}
+	if order.PaymentMethod == nil || order.PaymentMethod.Type != "Mastercard" || order.PaymentMethod.Last4 != "4242" {
+		t.Errorf("Unexpected payment method: %+v", order.PaymentMethod)
+	}
+
+	expectedCharges := models.OrderCharges{
+		Subtotal:   94.93,
+		Shipping:   5.99,
+		Tax:        8.39,
+		Promotions: 5.99,
+		GiftCard:   2.00,
+		Total:      101.32,
+	}
+	if order.Charges == nil || *order.Charges != expectedCharges {
+		t.Errorf("Expected charges %+v, got %+v", expectedCharges, order.Charges)
+	}
+}
+
+func TestParseOrderCharges_Refund(t *testing.T) {
+	html := `<div class="order-summary">
+		<div class="summary-row"><span class="label">Grand Total:</span><span class="value">$54.02</span></div>
+		<div class="summary-row"><span class="label">Refund Total:</span><span class="value">$24.99</span></div>
+	</div>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("failed to parse HTML: %v", err)
+	}
+
+	charges := parseOrderCharges(doc.Find(".order-summary .summary-row"), marketplace.Default())
+	if charges == nil || charges.Total != 54.02 || charges.Refund != 24.99 {
+		t.Errorf("Expected total 54.02 and refund 24.99, got %+v", charges)
+	}
+}
+
+func TestParseOrderCharges_FreeShipping(t *testing.T) {
+	// The free shipping row is a promotion and must not replace the shipping charge,
+	// whichever comes first
+	html := `<div class="order-summary">
+		<div class="summary-row"><span class="label">Free Shipping:</span><span class="value">-$5.99</span></div>
+		<div class="summary-row"><span class="label">Shipping &amp; Handling:</span><span class="value">$5.99</span></div>
+		<div class="summary-row"><span class="label">Grand Total:</span><span class="value">$24.99</span></div>
+	</div>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("failed to parse HTML: %v", err)
+	}
+
+	charges := parseOrderCharges(doc.Find(".order-summary .summary-row"), marketplace.Default())
+	if charges == nil || charges.Shipping != 5.99 || charges.Promotions != 5.99 || charges.Total != 24.99 {
+		t.Errorf("Expected shipping 5.99 and promotions 5.99, got %+v", charges)
+	}
+}
+
+func TestParsePaymentMethod(t *testing.T) {
+	tests := []struct {
+		code     string
+		input    string
+		expected *models.PaymentMethod
+	}{
+		{"us", "Visa ending in 1234", &models.PaymentMethod{Type: "Visa", Last4: "1234"}},
+		{"us", "  American Express\n ending in 0005 ", &models.PaymentMethod{Type: "American Express", Last4: "0005"}},
+		{"us", "Amazon Gift Card", &models.PaymentMethod{Type: "Amazon Gift Card"}},
+		{"us", "", nil},
+		{"de", "Visa endet auf 4821", &models.PaymentMethod{Type: "Visa", Last4: "4821"}},
+		{"de", "Mastercard ending in 9034", &models.PaymentMethod{Type: "Mastercard", Last4: "9034"}},
+		{"fr", "Visa se terminant par 7310", &models.PaymentMethod{Type: "Visa", Last4: "7310"}},
+		{"jp", "JCB 末尾が5678", &models.PaymentMethod{Type: "JCB", Last4: "5678"}},
+	}
+
+	for _, tt := range tests {
+		m, _ := marketplace.Get(tt.code)
+		got := parsePaymentMethod(tt.input, m)
+		if (got == nil) != (tt.expected == nil) || (got != nil && *got != *tt.expected) {
+			t.Errorf("parsePaymentMethod(%q) = %+v, expected %+v", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestGetOrderShipmentsTracking(t *testing.T) {
+	detail, err := os.ReadFile(filepath.Join("..", "..", "testdata", "orders", "order_detail_shipments_sample.html"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture file: %v", err)
+	}
+
+	var trackedShipments []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gp/your-account/order-details":
+			_, _ = w.Write(detail)
+		case "/progress-tracker/package/ref=ppx_yo_dt_b_track_package":
+			shipmentID := r.URL.Query().Get("shipmentId")
+			trackedShipments = append(trackedShipments, shipmentID)
+			if shipmentID == "DmF7kq3Lz" {
+				_, _ = w.Write([]byte(trackingPage("Delivered", "Delivered to front door")))
+				return
+			}
+			_, _ = w.Write([]byte(`<html><body><div class="tracking-number"><span class="value">9400111899223344556677</span></div><div class="tracking-status"><span class="value">Out for delivery</span></div></body></html>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := newSyncTestClient(server.URL)
+	result, err := client.GetOrderShipmentsTracking("114-3141592-6535897")
+	if err != nil {
+		t.Fatalf("GetOrderShipmentsTracking() error = %v", err)
+	}
+
+	if strings.Join(trackedShipments, ",") != "DmF7kq3Lz,Dq9Rt2Wxy" {
+		t.Errorf("Expected each shipment to be tracked, got %v", trackedShipments)
+	}
+	if result.OrderID != "114-3141592-6535897" || len(result.Shipments) != 2 {
+		t.Fatalf("Unexpected result: %+v", result)
+	}
+
+	first := result.Shipments[0]
+	if len(first.Items) != 1 || first.Items[0].ASIN != "B0AAAA1111" {
+		t.Errorf("Expected first package to contain B0AAAA1111, got %+v", first.Items)
+	}
+	if first.Tracking == nil || first.Tracking.Status != "delivered" || len(first.Tracking.Events) != 1 {
+		t.Errorf("Expected first package tracking from its tracking page, got %+v", first.Tracking)
+	}
+	// The detail page fills in what the tracking page omits
+	if first.Tracking.DeliveryDate != "2026-03-05" {
+		t.Errorf("Expected delivery date from the detail page, got %s", first.Tracking.DeliveryDate)
+	}
+
+	second := result.Shipments[1]
+	if len(second.Items) != 1 || second.Items[0].ASIN != "B0BBBB2222" {
+		t.Errorf("Expected second package to contain B0BBBB2222, got %+v", second.Items)
+	}
+	if second.Tracking == nil || second.Tracking.Carrier != "USPS" || second.Tracking.Status != "out for delivery" {
+		t.Errorf("Unexpected second package tracking: %+v", second.Tracking)
+	}
+	if second.Tracking.CarrierCode != "usps" || second.Tracking.TrackingURL == "" {
+		t.Errorf("Expected the second package to be identified as USPS, got %+v", second.Tracking)
+	}
+}
@@ -6,9 +6,9 @@ import (
 	"net/http"
 	"regexp"
 	"strings"
-	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/zkwentz/amazon-cli/internal/marketplace"
 	"github.com/zkwentz/amazon-cli/pkg/models"
 )
 
@@ -59,7 +59,7 @@ func (c *Client) GetProduct(asin string) (*models.Product, error) {
 	}
 
 	// Parse the HTML response
-	product, err := parseProductDetailHTML(body.Bytes())
+	product, err := parseProductDetailHTML(body.Bytes(), c.marketplace)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse product details: %w", err)
 	}
@@ -111,7 +111,7 @@ func (c *Client) GetProductReviews(asin string, limit int) (*models.ReviewsRespo
 	}
 
 	// Parse the HTML response
-	reviewsResponse, err := parseReviewsHTML(body.Bytes(), asin, limit)
+	reviewsResponse, err := parseReviewsHTML(body.Bytes(), asin, limit, c.marketplace)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse reviews: %w", err)
 	}
@@ -119,14 +119,17 @@ func (c *Client) GetProductReviews(asin string, limit int) (*models.ReviewsRespo
 	return reviewsResponse, nil
 }
 
-// parseProductDetailHTML parses Amazon product detail page HTML and extracts product information
-func parseProductDetailHTML(html []byte) (*models.Product, error) {
+// parseProductDetailHTML parses Amazon product detail page HTML and extracts product information.
+// Prices are interpreted using the given marketplace (nil means US).
+func parseProductDetailHTML(html []byte, m *marketplace.Marketplace) (*models.Product, error) {
+	m = marketplaceOrDefault(m)
+
 	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(html))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse HTML: %w", err)
 	}
 
-	product := &models.Product{}
+	product := &models.Product{Currency: m.Currency}
 
 	// Extract ASIN - multiple possible locations
 	// 1. From data-asin attribute
@@ -183,7 +186,7 @@ func parseProductDetailHTML(html []byte) (*models.Product, error) {
 		priceEl := doc.Find(selector)
 		if priceEl.Length() > 0 {
 			priceText := priceEl.First().Text()
-			price := parsePriceFromText(priceText)
+			price := m.ParsePrice(priceText)
 			if price > 0 {
 				product.Price = price
 				break
@@ -202,7 +205,7 @@ func parseProductDetailHTML(html []byte) (*models.Product, error) {
 		originalPriceEl := doc.Find(selector)
 		if originalPriceEl.Length() > 0 {
 			originalPriceText := originalPriceEl.First().Text()
-			originalPrice := parsePriceFromText(originalPriceText)
+			originalPrice := m.ParsePrice(originalPriceText)
 			if originalPrice > 0 && originalPrice != product.Price {
 				product.OriginalPrice = &originalPrice
 				break
@@ -392,8 +395,11 @@ func parseProductDetailHTML(html []byte) (*models.Product, error) {
 	return product, nil
 }
 
-// parseReviewsHTML parses Amazon product reviews page HTML and extracts review information
-func parseReviewsHTML(html []byte, asin string, limit int) (*models.ReviewsResponse, error) {
+// parseReviewsHTML parses Amazon product reviews page HTML and extracts review information.
+// Review dates are interpreted using the given marketplace (nil means US).
+func parseReviewsHTML(html []byte, asin string, limit int, m *marketplace.Marketplace) (*models.ReviewsResponse, error) {
+	m = marketplaceOrDefault(m)
+
 	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(html))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse HTML: %w", err)
@@ -479,7 +485,7 @@ func parseReviewsHTML(html []byte, asin string, limit int) (*models.ReviewsRespo
 		dateEl := s.Find("span[data-hook='review-date']")
 		if dateEl.Length() > 0 {
 			dateText := strings.TrimSpace(dateEl.First().Text())
-			review.Date = parseDateFromReview(dateText)
+			review.Date = parseDateFromReview(dateText, m)
 		}
 
 		// Check if verified purchase
@@ -497,30 +503,19 @@ func parseReviewsHTML(html []byte, asin string, limit int) (*models.ReviewsRespo
 }
 
 // parseDateFromReview extracts and formats date from review date text
-// Amazon review dates are typically in format "Reviewed in [Country] on [Date]"
-func parseDateFromReview(dateText string) string {
-	// Remove "Reviewed in [Country] on " prefix
+// Amazon review dates are typically in format "Reviewed in [Country] on [Date]",
+// localized per marketplace (e.g. "Rezension aus Deutschland vom 15. Januar 2024")
+func parseDateFromReview(dateText string, m *marketplace.Marketplace) string {
+	m = marketplaceOrDefault(m)
 	dateText = strings.TrimSpace(dateText)
 
-	// Try to extract date after "on "
-	if idx := strings.LastIndex(dateText, " on "); idx != -1 {
-		dateText = dateText[idx+4:]
-	}
-
-	// Try to parse common date formats
-	dateFormats := []string{
-		"January 2, 2006",
-		"Jan 2, 2006",
-		"2 January 2006",
-		"2006-01-02",
+	if t, err := m.ParseDate(dateText); err == nil {
+		return t.Format("2006-01-02")
 	}
 
-	for _, format := range dateFormats {
-		if t, err := time.Parse(format, dateText); err == nil {
-			return t.Format("2006-01-02")
-		}
+	// If parsing fails, return the date portion of the original text
+	if idx := strings.LastIndex(dateText, " on "); idx != -1 {
+		dateText = dateText[idx+4:]
 	}
-
-	// If parsing fails, return the original text
 	return dateText
 }
@@ -2,6 +2,8 @@ package amazon
 
 import (
 	"testing"
+
+	"github.com/zkwentz/amazon-cli/internal/marketplace"
 )
 
 func TestParseProductDetailHTML(t *testing.T) {
@@ -74,7 +76,7 @@ func TestParseProductDetailHTML(t *testing.T) {
 		</html>
 	`)
 
-	product, err := parseProductDetailHTML(html)
+	product, err := parseProductDetailHTML(html, marketplace.Default())
 	if err != nil {
 		t.Fatalf("parseProductDetailHTML failed: %v", err)
 	}
@@ -151,7 +153,7 @@ func TestParseProductDetailHTML_MinimalFields(t *testing.T) {
 		</html>
 	`)
 
-	product, err := parseProductDetailHTML(html)
+	product, err := parseProductDetailHTML(html, marketplace.Default())
 	if err != nil {
 		t.Fatalf("parseProductDetailHTML failed: %v", err)
 	}
@@ -216,7 +218,7 @@ func TestParseProductDetailHTML_MissingASIN(t *testing.T) {
 		</html>
 	`)
 
-	_, err := parseProductDetailHTML(html)
+	_, err := parseProductDetailHTML(html, marketplace.Default())
 	if err == nil {
 		t.Error("Expected error for missing ASIN, got nil")
 	}
@@ -232,7 +234,7 @@ func TestParseProductDetailHTML_MissingTitle(t *testing.T) {
 		</html>
 	`)
 
-	_, err := parseProductDetailHTML(html)
+	_, err := parseProductDetailHTML(html, marketplace.Default())
 	if err == nil {
 		t.Error("Expected error for missing title, got nil")
 	}
@@ -265,7 +267,7 @@ func TestParseProductDetailHTML_OutOfStock(t *testing.T) {
 				</html>
 			`)
 
-			product, err := parseProductDetailHTML(html)
+			product, err := parseProductDetailHTML(html, marketplace.Default())
 			if err != nil {
 				t.Fatalf("parseProductDetailHTML failed: %v", err)
 			}
@@ -326,7 +328,7 @@ func TestParseProductDetailHTML_ASINExtraction(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			product, err := parseProductDetailHTML(tt.html)
+			product, err := parseProductDetailHTML(tt.html, marketplace.Default())
 			if err != nil {
 				t.Fatalf("parseProductDetailHTML failed: %v", err)
 			}
@@ -388,7 +390,7 @@ func TestParseProductDetailHTML_PriceVariations(t *testing.T) {
 				</html>
 			`)
 
-			product, err := parseProductDetailHTML(html)
+			product, err := parseProductDetailHTML(html, marketplace.Default())
 			if err != nil {
 				t.Fatalf("parseProductDetailHTML failed: %v", err)
 			}
@@ -445,7 +447,7 @@ func TestParseProductDetailHTML_RatingVariations(t *testing.T) {
 				</html>
 			`)
 
-			product, err := parseProductDetailHTML(html)
+			product, err := parseProductDetailHTML(html, marketplace.Default())
 			if err != nil {
 				t.Fatalf("parseProductDetailHTML failed: %v", err)
 			}
@@ -512,7 +514,7 @@ func TestParseProductDetailHTML_PrimeIndicators(t *testing.T) {
 				</html>
 			`)
 
-			product, err := parseProductDetailHTML(html)
+			product, err := parseProductDetailHTML(html, marketplace.Default())
 			if err != nil {
 				t.Fatalf("parseProductDetailHTML failed: %v", err)
 			}
@@ -542,7 +544,7 @@ func TestParseProductDetailHTML_FeaturesExtraction(t *testing.T) {
 		</html>
 	`)
 
-	product, err := parseProductDetailHTML(html)
+	product, err := parseProductDetailHTML(html, marketplace.Default())
 	if err != nil {
 		t.Fatalf("parseProductDetailHTML failed: %v", err)
 	}
@@ -585,7 +587,7 @@ func TestParseProductDetailHTML_ImagesDeduplication(t *testing.T) {
 		</html>
 	`)
 
-	product, err := parseProductDetailHTML(html)
+	product, err := parseProductDetailHTML(html, marketplace.Default())
 	if err != nil {
 		t.Fatalf("parseProductDetailHTML failed: %v", err)
 	}
@@ -622,7 +624,7 @@ func TestParseProductDetailHTML_ImageFiltering(t *testing.T) {
 		</html>
 	`)
 
-	product, err := parseProductDetailHTML(html)
+	product, err := parseProductDetailHTML(html, marketplace.Default())
 	if err != nil {
 		t.Fatalf("parseProductDetailHTML failed: %v", err)
 	}
@@ -640,7 +642,7 @@ func TestParseProductDetailHTML_ImageFiltering(t *testing.T) {
 func TestParseProductDetailHTML_EmptyHTML(t *testing.T) {
 	html := []byte(`<html><body></body></html>`)
 
-	_, err := parseProductDetailHTML(html)
+	_, err := parseProductDetailHTML(html, marketplace.Default())
 	if err == nil {
 		t.Error("Expected error for HTML without required fields, got nil")
 	}
@@ -649,7 +651,7 @@ func TestParseProductDetailHTML_EmptyHTML(t *testing.T) {
 func TestParseProductDetailHTML_InvalidHTML(t *testing.T) {
 	html := []byte(`not valid html at all`)
 
-	_, err := parseProductDetailHTML(html)
+	_, err := parseProductDetailHTML(html, marketplace.Default())
 	if err == nil {
 		t.Error("Expected error for invalid HTML, got nil")
 	}
@@ -669,7 +671,7 @@ func TestParseProductDetailHTML_DescriptionExtraction(t *testing.T) {
 		</html>
 	`)
 
-	product, err := parseProductDetailHTML(html)
+	product, err := parseProductDetailHTML(html, marketplace.Default())
 	if err != nil {
 		t.Fatalf("parseProductDetailHTML failed: %v", err)
 	}
@@ -732,7 +734,7 @@ func TestParseProductDetailHTML_DeliveryEstimate(t *testing.T) {
 				</html>
 			`)
 
-			product, err := parseProductDetailHTML(html)
+			product, err := parseProductDetailHTML(html, marketplace.Default())
 			if err != nil {
 				t.Fatalf("parseProductDetailHTML failed: %v", err)
 			}
@@ -874,7 +876,7 @@ func TestParseReviewsHTML(t *testing.T) {
 		</html>
 	`)
 
-	response, err := parseReviewsHTML(html, "B08N5WRWNW", 10)
+	response, err := parseReviewsHTML(html, "B08N5WRWNW", 10, marketplace.Default())
 	if err != nil {
 		t.Fatalf("parseReviewsHTML failed: %v", err)
 	}
@@ -960,7 +962,7 @@ func TestParseReviewsHTML_LimitReviews(t *testing.T) {
 	`)
 
 	// Limit to 2 reviews
-	response, err := parseReviewsHTML(html, "B12345TEST", 2)
+	response, err := parseReviewsHTML(html, "B12345TEST", 2, marketplace.Default())
 	if err != nil {
 		t.Fatalf("parseReviewsHTML failed: %v", err)
 	}
@@ -979,7 +981,7 @@ func TestParseReviewsHTML_EmptyReviews(t *testing.T) {
 		</html>
 	`)
 
-	response, err := parseReviewsHTML(html, "B12345TEST", 10)
+	response, err := parseReviewsHTML(html, "B12345TEST", 10, marketplace.Default())
 	if err != nil {
 		t.Fatalf("parseReviewsHTML failed: %v", err)
 	}
@@ -1009,7 +1011,7 @@ func TestParseReviewsHTML_MissingFields(t *testing.T) {
 		</html>
 	`)
 
-	response, err := parseReviewsHTML(html, "B12345TEST", 10)
+	response, err := parseReviewsHTML(html, "B12345TEST", 10, marketplace.Default())
 	if err != nil {
 		t.Fatalf("parseReviewsHTML failed: %v", err)
 	}
@@ -1055,7 +1057,7 @@ func TestParseDateFromReview(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := parseDateFromReview(tt.input)
+			result := parseDateFromReview(tt.input, marketplace.Default())
 			if result != tt.expectedDate {
 				t.Errorf("Expected date %s, got %s", tt.expectedDate, result)
 			}
@@ -1079,14 +1081,14 @@ func TestGetProductReviews_DefaultLimit(t *testing.T) {
 	// This test would require mocking HTTP responses
 	// For now, we verify the limit is set to default when <= 0
 	client := NewClient()
-	
+
 	// Test with 0 limit - should default to 10
 	_, err := client.GetProductReviews("B08N5WRWNW", 0)
 	// Expected to fail with network error since we're not mocking, but shouldn't fail on validation
 	if err != nil && err.Error() == "limit must be positive" {
 		t.Error("Expected limit validation to allow 0 and default to 10")
 	}
-	
+
 	// Test with negative limit - should default to 10
 	_, err = client.GetProductReviews("B08N5WRWNW", -5)
 	// Expected to fail with network error since we're not mocking, but shouldn't fail on validation
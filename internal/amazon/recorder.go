@@ -0,0 +1,405 @@
+package amazon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sensitiveHeaders lists headers that are never written to disk by the recorder
+var sensitiveHeaders = []string{
+	"Cookie",
+	"Set-Cookie",
+	"Authorization",
+	"Proxy-Authorization",
+	"X-Amz-Access-Token",
+}
+
+// piiPatterns contains regular expressions for account PII that is redacted
+// from recorded response bodies before they are written to disk
+var piiPatterns = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	// Email addresses
+	{regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`), "redacted@example.com"},
+	// Account greeting in the navigation bar (e.g. "Hello, Jane")
+	{regexp.MustCompile(`Hello, [^<"\n]+`), "Hello, Customer"},
+	// Customer and session identifiers embedded in page scripts
+	{regexp.MustCompile(`"(customerId|sessionId|ubid-main)"\s*:\s*"[^"]*"`), `"$1":"REDACTED"`},
+	// US phone numbers
+	{regexp.MustCompile(`\(?\b\d{3}\)?[-. ]\d{3}[-. ]\d{4}\b`), "555-555-0100"},
+	// Last four digits of a payment card (e.g. "Visa ending in 1234", "**** 1234")
+	{regexp.MustCompile(`(?i)(ending in|endet auf|endend auf|se terminant par|finissant par|末尾)(:?\s*)\d{4}`), "${1}${2}0000"},
+	{regexp.MustCompile(`(\*{4}\s?)\d{4}\b`), "${1}0000"},
+}
+
+// addressBlockRegex matches the opening tag of an element holding a postal address:
+// an <address> element or any element whose class or id mentions "address"
+var addressBlockRegex = regexp.MustCompile(`(?i)<(address)\b[^>]*>|<([a-z][a-z0-9]*)\b[^>]*\s(?:class|id)="[^"]*address[^"]*"[^>]*>`)
+
+// addressTextRegex matches the non-blank text between two tags
+var addressTextRegex = regexp.MustCompile(`>([^<]*[^<\s][^<]*)<`)
+
+// htmlTagRegex matches an opening or closing HTML tag and captures its name
+var htmlTagRegex = regexp.MustCompile(`<(/?)([a-zA-Z][a-zA-Z0-9]*)\b[^>]*>`)
+
+// voidElements are HTML elements without content or closing tag
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true,
+	"img": true, "input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// recordedExtensions maps response media types to the extension of the saved body file
+var recordedExtensions = map[string]string{
+	"text/html":              ".html",
+	"application/xhtml+xml":  ".html",
+	"application/json":       ".json",
+	"text/javascript":        ".js",
+	"application/javascript": ".js",
+	"text/plain":             ".txt",
+	"text/xml":               ".xml",
+	"application/xml":        ".xml",
+	"text/calendar":          ".ics",
+	"application/pdf":        ".pdf",
+}
+
+// recordedMetaSuffix names exchange metadata files; it cannot collide with a body
+// file, whose name is the sequence number followed by a single extension
+const recordedMetaSuffix = ".meta.json"
+
+// RecordedExchange is a single request/response pair captured by RecordingTransport
+type RecordedExchange struct {
+	Method          string              `json:"method"`
+	URL             string              `json:"url"`
+	RequestHeaders  map[string][]string `json:"request_headers"`
+	StatusCode      int                 `json:"status_code"`
+	ResponseHeaders map[string][]string `json:"response_headers"`
+	BodyFile        string              `json:"body_file"`
+	RecordedAt      string              `json:"recorded_at"`
+}
+
+// RecordingTransport is an http.RoundTripper that saves every request/response
+// pair it sees to a directory, scrubbed of cookies and account PII.
+// Each exchange is written as NNNN.meta.json metadata plus an NNNN body file
+// whose extension follows the response Content-Type (NNNN.html for pages), so
+// captured pages can be copied directly into testdata/.
+type RecordingTransport struct {
+	dir  string
+	base http.RoundTripper
+	seq  int
+	mu   sync.Mutex
+}
+
+// NewRecordingTransport creates a RecordingTransport that writes to dir and
+// forwards requests to base. If base is nil, http.DefaultTransport is used.
+func NewRecordingTransport(dir string, base http.RoundTripper) (*RecordingTransport, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("record directory cannot be empty")
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create record directory: %w", err)
+	}
+
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &RecordingTransport{
+		dir:  dir,
+		base: base,
+	}, nil
+}
+
+// RoundTrip executes the request through the base transport and records the exchange
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read the body so it can be saved, then hand the caller a fresh reader
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body for recording: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err := t.save(req, resp, body); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// save writes the scrubbed exchange metadata and body to the record directory
+func (t *RecordingTransport) save(req *http.Request, resp *http.Response, body []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.seq++
+	name := fmt.Sprintf("%04d", t.seq)
+
+	exchange := RecordedExchange{
+		Method:          req.Method,
+		URL:             req.URL.RequestURI(),
+		RequestHeaders:  scrubHeaders(req.Header),
+		StatusCode:      resp.StatusCode,
+		ResponseHeaders: scrubHeaders(resp.Header),
+		BodyFile:        name + bodyExtension(resp.Header.Get("Content-Type")),
+		RecordedAt:      time.Now().UTC().Format(time.RFC3339),
+	}
+
+	if isTextContent(resp.Header.Get("Content-Type")) {
+		body = scrubPII(body)
+	}
+
+	if err := os.WriteFile(filepath.Join(t.dir, exchange.BodyFile), body, 0600); err != nil {
+		return fmt.Errorf("failed to write recorded body: %w", err)
+	}
+
+	data, err := json.MarshalIndent(exchange, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal recorded exchange: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(t.dir, name+recordedMetaSuffix), data, 0600); err != nil {
+		return fmt.Errorf("failed to write recorded exchange: %w", err)
+	}
+
+	return nil
+}
+
+// ReplayTransport is an http.RoundTripper that serves responses previously
+// captured by RecordingTransport without touching the network.
+// Requests are matched by method and request URI (path and query). When the
+// same URL was recorded several times the responses are served in order and
+// the last one is repeated once the sequence is exhausted.
+type ReplayTransport struct {
+	dir       string
+	exchanges map[string][]RecordedExchange
+	served    map[string]int
+	mu        sync.Mutex
+}
+
+// NewReplayTransport loads all recorded exchanges from dir
+func NewReplayTransport(dir string) (*ReplayTransport, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("replay directory cannot be empty")
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"+recordedMetaSuffix))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replay directory: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no recorded exchanges found in %s", dir)
+	}
+	sort.Strings(files)
+
+	t := &ReplayTransport{
+		dir:       dir,
+		exchanges: make(map[string][]RecordedExchange),
+		served:    make(map[string]int),
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read recorded exchange: %w", err)
+		}
+
+		var exchange RecordedExchange
+		if err := json.Unmarshal(data, &exchange); err != nil {
+			return nil, fmt.Errorf("failed to parse recorded exchange %s: %w", filepath.Base(file), err)
+		}
+
+		key := replayKey(exchange.Method, exchange.URL)
+		t.exchanges[key] = append(t.exchanges[key], exchange)
+	}
+
+	return t, nil
+}
+
+// RoundTrip returns the recorded response matching the request
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := replayKey(req.Method, req.URL.RequestURI())
+
+	t.mu.Lock()
+	recorded, exists := t.exchanges[key]
+	if !exists {
+		t.mu.Unlock()
+		return nil, fmt.Errorf("no recorded response for %s %s", req.Method, req.URL.RequestURI())
+	}
+	index := t.served[key]
+	if index >= len(recorded) {
+		index = len(recorded) - 1
+	}
+	t.served[key]++
+	t.mu.Unlock()
+
+	exchange := recorded[index]
+
+	body, err := os.ReadFile(filepath.Join(t.dir, exchange.BodyFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recorded body: %w", err)
+	}
+
+	header := http.Header{}
+	for name, values := range exchange.ResponseHeaders {
+		for _, value := range values {
+			header.Add(name, value)
+		}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", exchange.StatusCode, http.StatusText(exchange.StatusCode)),
+		StatusCode:    exchange.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// replayKey builds the lookup key for a recorded exchange
+func replayKey(method, requestURI string) string {
+	return strings.ToUpper(method) + " " + requestURI
+}
+
+// scrubHeaders returns a copy of the headers without cookies or credentials
+func scrubHeaders(h http.Header) map[string][]string {
+	scrubbed := make(map[string][]string, len(h))
+	for name, values := range h {
+		scrubbed[name] = append([]string(nil), values...)
+	}
+
+	for _, name := range sensitiveHeaders {
+		delete(scrubbed, http.CanonicalHeaderKey(name))
+	}
+
+	return scrubbed
+}
+
+// scrubPII redacts account PII such as email addresses, names, shipping
+// addresses and card digits from a page body
+func scrubPII(body []byte) []byte {
+	body = scrubAddresses(body)
+	for _, p := range piiPatterns {
+		body = p.pattern.ReplaceAll(body, []byte(p.replacement))
+	}
+	return body
+}
+
+// scrubAddresses replaces the text inside address blocks with a placeholder,
+// keeping the markup so recorded pages still parse like the originals
+func scrubAddresses(body []byte) []byte {
+	var out bytes.Buffer
+	rest := body
+	for {
+		loc := addressBlockRegex.FindSubmatchIndex(rest)
+		if loc == nil {
+			out.Write(rest)
+			return out.Bytes()
+		}
+
+		tag := "address"
+		if loc[4] >= 0 {
+			tag = strings.ToLower(string(rest[loc[4]:loc[5]]))
+		}
+		if voidElements[tag] || bytes.HasSuffix(rest[loc[0]:loc[1]], []byte("/>")) {
+			out.Write(rest[:loc[1]])
+			rest = rest[loc[1]:]
+			continue
+		}
+
+		// The span runs from the opening tag's ">" to the closing tag's "<" so every
+		// text node inside it sits between two tag delimiters
+		out.Write(rest[:loc[1]-1])
+		rest = rest[loc[1]-1:]
+		end := 1 + closingTagIndex(rest[1:], tag)
+		span := append(rest[:end:end], '<')
+		scrubbed := addressTextRegex.ReplaceAll(span, []byte(">REDACTED<"))
+		out.Write(scrubbed[:len(scrubbed)-1])
+		rest = rest[end:]
+	}
+}
+
+// closingTagIndex returns the offset in body of the tag closing an element named
+// tag whose opening tag precedes body, or len(body) when it is never closed
+func closingTagIndex(body []byte, tag string) int {
+	depth := 1
+	for _, loc := range htmlTagRegex.FindAllSubmatchIndex(body, -1) {
+		if !strings.EqualFold(string(body[loc[4]:loc[5]]), tag) {
+			continue
+		}
+		if loc[3] > loc[2] {
+			depth--
+		} else if !bytes.HasSuffix(body[loc[0]:loc[1]], []byte("/>")) {
+			depth++
+		}
+		if depth == 0 {
+			return loc[0]
+		}
+	}
+	return len(body)
+}
+
+// bodyExtension returns the file extension for a recorded body of the given Content-Type
+func bodyExtension(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType == "" {
+		return ".html"
+	}
+	if ext, ok := recordedExtensions[mediaType]; ok {
+		return ext
+	}
+	if exts, err := mime.ExtensionsByType(mediaType); err == nil && len(exts) > 0 {
+		return exts[0]
+	}
+	return ".bin"
+}
+
+// isTextContent reports whether a body of the given Content-Type is text that can be
+// scrubbed; binary bodies such as PDFs are saved untouched
+func isTextContent(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType == "" {
+		return true
+	}
+	return strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "json") ||
+		strings.HasSuffix(mediaType, "xml") || strings.HasSuffix(mediaType, "javascript")
+}
+
+// SetTransport replaces the RoundTripper used by the client's HTTP client.
+// It is used to enable recording or replay of HTTP traffic. The client works
+// on its own copy of the HTTP client so one passed to WithHTTPClient is never
+// modified.
+func (c *Client) SetTransport(rt http.RoundTripper) {
+	httpClient := *c.httpClient
+	httpClient.Transport = rt
+	c.httpClient = &httpClient
+}
+
+// Transport returns the RoundTripper currently used by the client's HTTP client
+func (c *Client) Transport() http.RoundTripper {
+	if c.httpClient.Transport == nil {
+		return http.DefaultTransport
+	}
+	return c.httpClient.Transport
+}
@@ -0,0 +1,313 @@
+package amazon
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordingTransport_SavesScrubbedExchange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session-id", Value: "secret-session"})
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`<html><span id="nav-link-accountList">Hello, Jane</span><p>jane.doe@example.org</p><div class="order">ok</div></html>`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	rt, err := NewRecordingTransport(dir, nil)
+	if err != nil {
+		t.Fatalf("NewRecordingTransport() error = %v", err)
+	}
+
+	req, _ := http.NewRequest("GET", server.URL+"/gp/your-account/order-history?startIndex=10", nil)
+	req.Header.Set("Cookie", "session-id=secret-session")
+	resp, err := (&http.Client{Transport: rt}).Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	// Caller still sees the original, unscrubbed body
+	if !strings.Contains(string(body), "jane.doe@example.org") {
+		t.Errorf("expected caller to receive original body, got %s", body)
+	}
+
+	meta, err := os.ReadFile(filepath.Join(dir, "0001.meta.json"))
+	if err != nil {
+		t.Fatalf("expected metadata file to be written: %v", err)
+	}
+	if strings.Contains(string(meta), "secret-session") {
+		t.Error("recorded metadata should not contain cookies")
+	}
+	if !strings.Contains(string(meta), "/gp/your-account/order-history?startIndex=10") {
+		t.Errorf("recorded metadata should contain request URI, got %s", meta)
+	}
+
+	saved, err := os.ReadFile(filepath.Join(dir, "0001.html"))
+	if err != nil {
+		t.Fatalf("expected body file to be written: %v", err)
+	}
+	if strings.Contains(string(saved), "jane.doe@example.org") || strings.Contains(string(saved), "Hello, Jane") {
+		t.Errorf("recorded body should be scrubbed of PII, got %s", saved)
+	}
+	if !strings.Contains(string(saved), `<div class="order">ok</div>`) {
+		t.Errorf("recorded body should keep page content, got %s", saved)
+	}
+}
+
+func TestReplayTransport_ServesRecordedResponses(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+		if calls == 1 {
+			_, _ = w.Write([]byte("first"))
+		} else {
+			_, _ = w.Write([]byte("second"))
+		}
+	}))
+
+	dir := t.TempDir()
+	rt, err := NewRecordingTransport(dir, nil)
+	if err != nil {
+		t.Fatalf("NewRecordingTransport() error = %v", err)
+	}
+	recorder := &http.Client{Transport: rt}
+	for i := 0; i < 2; i++ {
+		resp, err := recorder.Get(server.URL + "/progress-tracker/package")
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	// Close the server so replay cannot reach the network
+	server.Close()
+
+	replay, err := NewReplayTransport(dir)
+	if err != nil {
+		t.Fatalf("NewReplayTransport() error = %v", err)
+	}
+	player := &http.Client{Transport: replay}
+
+	expected := []string{"first", "second", "second"}
+	for i, want := range expected {
+		resp, err := player.Get("https://www.amazon.com/progress-tracker/package")
+		if err != nil {
+			t.Fatalf("replay %d: Get() error = %v", i, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if string(body) != want {
+			t.Errorf("replay %d: expected body %q, got %q", i, want, body)
+		}
+	}
+}
+
+func TestRecordingTransport_JSONRoundTrip(t *testing.T) {
+	payload := `{"orderId":"111-2222222-3333333","status":"Shipped"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(payload))
+	}))
+
+	dir := t.TempDir()
+	rt, err := NewRecordingTransport(dir, nil)
+	if err != nil {
+		t.Fatalf("NewRecordingTransport() error = %v", err)
+	}
+	resp, err := (&http.Client{Transport: rt}).Get(server.URL + "/api/order-status?id=1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+	server.Close()
+
+	saved, err := os.ReadFile(filepath.Join(dir, "0001.json"))
+	if err != nil {
+		t.Fatalf("expected JSON body file to be written: %v", err)
+	}
+	if string(saved) != payload {
+		t.Errorf("JSON body should not be overwritten by metadata, got %s", saved)
+	}
+
+	replay, err := NewReplayTransport(dir)
+	if err != nil {
+		t.Fatalf("NewReplayTransport() error = %v", err)
+	}
+	resp, err = (&http.Client{Transport: replay}).Get("https://www.amazon.com/api/order-status?id=1")
+	if err != nil {
+		t.Fatalf("replay Get() error = %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if string(body) != payload {
+		t.Errorf("replayed body = %s, want %s", body, payload)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("replayed Content-Type = %q, want application/json", got)
+	}
+}
+
+func TestReplayTransport_UnknownRequest(t *testing.T) {
+	dir := t.TempDir()
+	exchange := `{"method":"GET","url":"/known","status_code":200,"body_file":"0001.html"}`
+	_ = os.WriteFile(filepath.Join(dir, "0001.meta.json"), []byte(exchange), 0600)
+	_ = os.WriteFile(filepath.Join(dir, "0001.html"), []byte("known"), 0600)
+
+	replay, err := NewReplayTransport(dir)
+	if err != nil {
+		t.Fatalf("NewReplayTransport() error = %v", err)
+	}
+
+	_, err = (&http.Client{Transport: replay}).Get("https://www.amazon.com/unknown")
+	if err == nil {
+		t.Fatal("expected error for request that was not recorded")
+	}
+	if !strings.Contains(err.Error(), "no recorded response") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestNewReplayTransport_EmptyDirectory(t *testing.T) {
+	if _, err := NewReplayTransport(t.TempDir()); err == nil {
+		t.Error("expected error for directory without recordings")
+	}
+}
+
+func TestClientDo_ReplaysThroughTransport(t *testing.T) {
+	dir := t.TempDir()
+	exchange := `{"method":"GET","url":"/gp/your-account/order-history","status_code":200,"body_file":"0001.html"}`
+	_ = os.WriteFile(filepath.Join(dir, "0001.meta.json"), []byte(exchange), 0600)
+	_ = os.WriteFile(filepath.Join(dir, "0001.html"), []byte(`<div class="order" data-order-id="111-2222222-3333333"></div>`), 0600)
+
+	replay, err := NewReplayTransport(dir)
+	if err != nil {
+		t.Fatalf("NewReplayTransport() error = %v", err)
+	}
+
+	client := NewClient()
+	client.SetTransport(replay)
+
+	resp, err := client.GetOrders(10, "")
+	if err != nil {
+		t.Fatalf("GetOrders() error = %v", err)
+	}
+	if resp.TotalCount != 1 || resp.Orders[0].OrderID != "111-2222222-3333333" {
+		t.Errorf("unexpected replayed orders: %+v", resp.Orders)
+	}
+}
+
+func TestScrubPII(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		redacted string
+	}{
+		{"email", "contact: someone@example.com", "someone@example.com"},
+		{"greeting", "<span>Hello, Alex</span>", "Alex"},
+		{"customer id", `{"customerId":"A1B2C3D4E5"}`, "A1B2C3D4E5"},
+		{"phone", "Call (206) 555-1234", "555-1234"},
+		{"card last four", "<div>Visa ending in 4821</div>", "4821"},
+		{"masked card", "<span>**** 7310</span>", "7310"},
+		{"german card", "Mastercard endet auf 9034", "9034"},
+		{"address block", `<div class="address"><div>Jane Roe</div><div>500 Pine Street</div></div>`, "500 Pine Street"},
+		{"address element", "<address>500 Pine Street<br>Portland</address>", "Portland"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(scrubPII([]byte(tt.input)))
+			if strings.Contains(got, tt.redacted) {
+				t.Errorf("scrubPII(%q) = %q, expected %q to be redacted", tt.input, got, tt.redacted)
+			}
+		})
+	}
+
+	// Order IDs must survive scrubbing
+	if got := string(scrubPII([]byte("111-2222222-3333333"))); got != "111-2222222-3333333" {
+		t.Errorf("order ID should not be scrubbed, got %q", got)
+	}
+}
+
+func TestScrubAddresses_KeepsMarkup(t *testing.T) {
+	input := `<h3>Shipping Address</h3><div class="shipping-address"><div>Jane Roe</div><div><span>500 Pine Street</span></div></div><input type="hidden" id="address-id" value="x"><div class="order">ok</div>`
+	want := `<h3>Shipping Address</h3><div class="shipping-address"><div>REDACTED</div><div><span>REDACTED</span></div></div><input type="hidden" id="address-id" value="x"><div class="order">ok</div>`
+
+	if got := string(scrubAddresses([]byte(input))); got != want {
+		t.Errorf("scrubAddresses() = %q, want %q", got, want)
+	}
+}
+
+func TestScrubPII_FixtureAddressAndCard(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", "testdata", "orders", "order_detail_sample.html"))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	got := string(scrubPII(data))
+	for _, pii := range []string{"John Doe", "123 Main Street", "Seattle, WA 98101", "ending in 1234"} {
+		if strings.Contains(got, pii) {
+			t.Errorf("scrubbed fixture still contains %q", pii)
+		}
+	}
+	if !strings.Contains(got, "Shipment Tracking") {
+		t.Error("scrubbed fixture should keep non-address content")
+	}
+}
+
+func TestBodyExtension(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        string
+	}{
+		{"text/html; charset=UTF-8", ".html"},
+		{"", ".html"},
+		{"application/json", ".json"},
+		{"application/pdf", ".pdf"},
+		{"text/plain", ".txt"},
+		{"application/x-unknown-thing", ".bin"},
+	}
+
+	for _, tt := range tests {
+		if got := bodyExtension(tt.contentType); got != tt.want {
+			t.Errorf("bodyExtension(%q) = %q, want %q", tt.contentType, got, tt.want)
+		}
+	}
+}
+
+func TestRecordingTransport_BinaryBodyKeepsContentAndExtension(t *testing.T) {
+	pdf := []byte("%PDF-1.4 Visa ending in 4242 jane@example.org")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		_, _ = w.Write(pdf)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	rt, err := NewRecordingTransport(dir, nil)
+	if err != nil {
+		t.Fatalf("NewRecordingTransport() error = %v", err)
+	}
+	resp, err := (&http.Client{Transport: rt}).Get(server.URL + "/invoice.pdf")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+
+	saved, err := os.ReadFile(filepath.Join(dir, "0001.pdf"))
+	if err != nil {
+		t.Fatalf("expected PDF body file to be written: %v", err)
+	}
+	if string(saved) != string(pdf) {
+		t.Errorf("binary body should be saved untouched, got %q", saved)
+	}
+}
@@ -0,0 +1,151 @@
+package amazon
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+// PlanReorder looks up the current price and availability of a past order's items.
+// With asins, only those items are included; an ASIN that isn't in the order is an
+// error. Items without an ASIN, no longer sold or out of stock are kept with a reason.
+func (c *Client) PlanReorder(orderID string, asins []string) (*models.ReorderPlan, error) {
+	order, err := c.GetOrder(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := selectReorderItems(order, asins)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &models.ReorderPlan{
+		OrderID:  order.OrderID,
+		Currency: order.Currency,
+		Items:    []models.ReorderItem{},
+	}
+	for _, item := range items {
+		reorder := models.ReorderItem{
+			ASIN:     item.ASIN,
+			Title:    item.Title,
+			Quantity: item.Quantity,
+			OldPrice: item.Price,
+		}
+		if reorder.Quantity <= 0 {
+			reorder.Quantity = 1
+		}
+
+		if item.ASIN == "" {
+			reorder.Reason = "item has no ASIN"
+			plan.Items = append(plan.Items, reorder)
+			continue
+		}
+
+		product, err := c.GetProduct(item.ASIN)
+		switch {
+		case err != nil:
+			reorder.Reason = "product unavailable: " + err.Error()
+		case !product.InStock:
+			reorder.NewPrice = product.Price
+			reorder.Reason = "out of stock"
+		default:
+			reorder.NewPrice = product.Price
+			reorder.InStock = true
+			if product.Currency != "" && plan.Currency == "" {
+				plan.Currency = product.Currency
+			}
+		}
+		if reorder.NewPrice != 0 {
+			reorder.PriceChange = roundCents(reorder.NewPrice - reorder.OldPrice)
+		}
+		if reorder.InStock {
+			plan.OldTotal += reorder.OldPrice * float64(reorder.Quantity)
+			plan.NewTotal += reorder.NewPrice * float64(reorder.Quantity)
+		}
+		plan.Items = append(plan.Items, reorder)
+	}
+
+	plan.OldTotal = roundCents(plan.OldTotal)
+	plan.NewTotal = roundCents(plan.NewTotal)
+	return plan, nil
+}
+
+// Reorder adds the items of a plan that are in stock to the cart in their original quantities
+func (c *Client) Reorder(plan *models.ReorderPlan) (*models.ReorderResult, error) {
+	result := &models.ReorderResult{
+		OrderID: plan.OrderID,
+		Added:   []models.ReorderItem{},
+		Skipped: []models.ReorderItem{},
+	}
+
+	for _, item := range plan.Items {
+		if !item.InStock {
+			result.Skipped = append(result.Skipped, item)
+			continue
+		}
+		cart, err := c.AddToCart(item.ASIN, item.Quantity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add %s to cart: %w", item.ASIN, err)
+		}
+		result.Added = append(result.Added, item)
+		result.Cart = cart
+	}
+
+	if len(result.Added) == 0 {
+		return nil, fmt.Errorf("no items of order %s can be reordered", plan.OrderID)
+	}
+	return result, nil
+}
+
+// CheckReorderCheckout returns an error when the cart already holds items. Checkout
+// buys the whole cart, so reordering with checkout would also buy them without
+// their showing in the reorder preview.
+func (c *Client) CheckReorderCheckout() error {
+	cart, err := c.GetCart()
+	if err != nil {
+		return fmt.Errorf("failed to check cart: %w", err)
+	}
+	if len(cart.Items) > 0 {
+		return fmt.Errorf("cart already contains %d other item(s) that checkout would also buy; clear the cart before reordering with checkout", cart.ItemCount)
+	}
+	return nil
+}
+
+// selectReorderItems picks the order items to reorder: the requested ASINs, or every item
+func selectReorderItems(order *models.Order, asins []string) ([]models.OrderItem, error) {
+	if len(asins) == 0 {
+		return order.Items, nil
+	}
+
+	var selected []models.OrderItem
+	seen := make(map[string]bool)
+	for _, asin := range asins {
+		asin = strings.ToUpper(strings.TrimSpace(asin))
+		if seen[asin] {
+			continue
+		}
+		seen[asin] = true
+
+		found := false
+		for _, item := range order.Items {
+			if item.ASIN == asin {
+				selected = append(selected, item)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("item %s is not in order %s", asin, order.OrderID)
+		}
+	}
+
+	return selected, nil
+}
+
+// roundCents rounds an amount to cents
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
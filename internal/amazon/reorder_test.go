@@ -0,0 +1,138 @@
+package amazon
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// reorderServer serves an order detail page with three items and product pages for them:
+// one cheaper now, one out of stock and one no longer sold
+func reorderServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gp/your-account/order-details":
+			fmt.Fprintf(w, `<html><body><span class="order-id-value">%s</span>
+<div class="order-status"><span class="status-badge">Delivered</span></div>
+<div class="order-item" data-asin="B000FILTER"><span class="item-title">Water Filter</span>
+  <span class="item-price"><span class="value">$14.99</span></span>
+  <span class="item-quantity"><span class="value">2</span></span></div>
+<div class="order-item" data-asin="B0000SOAP1"><span class="item-title">Soap</span>
+  <span class="item-price"><span class="value">$4.00</span></span></div>
+<div class="order-item" data-asin="B0000LAMP1"><span class="item-title">Lamp</span>
+  <span class="item-price"><span class="value">$40.00</span></span></div>
+</body></html>`, r.URL.Query().Get("orderID"))
+		case "/dp/B000FILTER":
+			fmt.Fprint(w, `<html><body><div data-asin="B000FILTER"><span id="productTitle">Water Filter</span>
+<span class="a-price"><span class="a-offscreen">$13.49</span></span>
+<div id="availability"><span>In Stock</span></div></div></body></html>`)
+		case "/dp/B0000SOAP1":
+			fmt.Fprint(w, `<html><body><div data-asin="B0000SOAP1"><span id="productTitle">Soap</span>
+<span class="a-price"><span class="a-offscreen">$4.50</span></span>
+<div id="availability"><span>Currently unavailable.</span></div></div></body></html>`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestPlanReorder(t *testing.T) {
+	server := reorderServer()
+	defer server.Close()
+
+	plan, err := newSyncTestClient(server.URL).PlanReorder("111-2222222-3333333", nil)
+	if err != nil {
+		t.Fatalf("PlanReorder() error = %v", err)
+	}
+
+	if len(plan.Items) != 3 {
+		t.Fatalf("Expected 3 items, got %+v", plan.Items)
+	}
+
+	filter := plan.Items[0]
+	if !filter.InStock || filter.Quantity != 2 || filter.OldPrice != 14.99 || filter.NewPrice != 13.49 || filter.PriceChange != -1.5 {
+		t.Errorf("Unexpected filter item: %+v", filter)
+	}
+	soap := plan.Items[1]
+	if soap.InStock || soap.Reason != "out of stock" || soap.NewPrice != 4.5 {
+		t.Errorf("Expected soap to be out of stock, got %+v", soap)
+	}
+	lamp := plan.Items[2]
+	if lamp.InStock || !strings.HasPrefix(lamp.Reason, "product unavailable") {
+		t.Errorf("Expected the lamp to be unavailable, got %+v", lamp)
+	}
+
+	// Totals only cover the items that can be reordered
+	if plan.OldTotal != 29.98 || plan.NewTotal != 26.98 {
+		t.Errorf("Expected totals 29.98 -> 26.98, got %v -> %v", plan.OldTotal, plan.NewTotal)
+	}
+}
+
+func TestPlanReorder_SelectedItems(t *testing.T) {
+	server := reorderServer()
+	defer server.Close()
+	client := newSyncTestClient(server.URL)
+
+	plan, err := client.PlanReorder("111-2222222-3333333", []string{"b0000soap1", "B0000SOAP1"})
+	if err != nil {
+		t.Fatalf("PlanReorder() error = %v", err)
+	}
+	if len(plan.Items) != 1 || plan.Items[0].ASIN != "B0000SOAP1" {
+		t.Errorf("Expected only the soap, got %+v", plan.Items)
+	}
+
+	_, err = client.PlanReorder("111-2222222-3333333", []string{"B0NOTINORD"})
+	if err == nil || !strings.Contains(err.Error(), "not in order") {
+		t.Errorf("Expected a not-in-order error, got %v", err)
+	}
+}
+
+func TestReorder(t *testing.T) {
+	server := reorderServer()
+	defer server.Close()
+	client := newSyncTestClient(server.URL)
+
+	plan, err := client.PlanReorder("111-2222222-3333333", nil)
+	if err != nil {
+		t.Fatalf("PlanReorder() error = %v", err)
+	}
+
+	result, err := client.Reorder(plan)
+	if err != nil {
+		t.Fatalf("Reorder() error = %v", err)
+	}
+	if len(result.Added) != 1 || result.Added[0].ASIN != "B000FILTER" || len(result.Skipped) != 2 {
+		t.Errorf("Expected the filter added and two items skipped, got %+v", result)
+	}
+	if result.Cart == nil || result.Cart.ItemCount != 2 {
+		t.Errorf("Expected two filters in the cart, got %+v", result.Cart)
+	}
+
+	// A plan with nothing in stock adds nothing
+	plan.Items = plan.Items[1:]
+	if _, err := client.Reorder(plan); err == nil || !strings.Contains(err.Error(), "can be reordered") {
+		t.Errorf("Expected an error when nothing can be reordered, got %v", err)
+	}
+}
+
+func TestCheckReorderCheckout(t *testing.T) {
+	client := NewClient()
+	if err := client.CheckReorderCheckout(); err != nil {
+		t.Errorf("Expected an empty cart to allow checkout, got %v", err)
+	}
+
+	if _, err := client.AddToCart("B08N5WRWNW", 2); err != nil {
+		t.Fatalf("AddToCart() error = %v", err)
+	}
+	err := client.CheckReorderCheckout()
+	if err == nil || !strings.Contains(err.Error(), "cart already contains 2 other item(s)") {
+		t.Errorf("Expected an error for a cart holding other items, got %v", err)
+	}
+
+	_ = client.ClearCart()
+	if err := client.CheckReorderCheckout(); err != nil {
+		t.Errorf("Expected a cleared cart to allow checkout, got %v", err)
+	}
+}
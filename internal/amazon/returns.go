@@ -1,13 +1,43 @@
 package amazon
 
 import (
+	"bytes"
 	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/PuerkitoBio/goquery"
 	"github.com/google/uuid"
+	"github.com/zkwentz/amazon-cli/internal/marketplace"
 	"github.com/zkwentz/amazon-cli/pkg/models"
 )
 
+// returnsCenterPath is the returns center page listing the items that can be returned
+const returnsCenterPath = "/gp/css/returns/homepage.html"
+
+// returnOptionsPath is the page listing the ways an order item can be returned
+const returnOptionsPath = "/gp/css/returns/options.html"
+
+// returnMethodPatterns map return option labels to method codes; the first match wins
+var returnMethodPatterns = []struct {
+	pattern *regexp.Regexp
+	method  string
+}{
+	{regexp.MustCompile(`(?i)pick[\s-]?up`), models.ReturnMethodPickup},
+	{regexp.MustCompile(`(?i)whole\s*foods`), models.ReturnMethodWholeFoods},
+	{regexp.MustCompile(`(?i)kohl'?s`), models.ReturnMethodKohls},
+	{regexp.MustCompile(`(?i)\bups\b`), models.ReturnMethodUPSDropoff},
+	{regexp.MustCompile(`(?i)\bmail\b|usps|post office|\bship`), models.ReturnMethodMail},
+}
+
+// nonWordRegex matches runs of characters that are not letters or digits
+var nonWordRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
 // validReturnReasons contains the list of allowed return reasons
 var validReturnReasons = map[string]bool{
 	"defective":        true,
@@ -18,6 +48,300 @@ var validReturnReasons = map[string]bool{
 	"other":            true,
 }
 
+// returnWindowRegex finds the return deadline in an item's text on pages without a
+// dedicated return-window element, e.g. "Eligible for Return through February 14, 2026"
+var returnWindowRegex = regexp.MustCompile(`(?i)(?:return window clos(?:es|ing)(?: on)?|eligible for return (?:through|until)|return by|returnable until)\s*:?\s*([^\n]+)`)
+
+// purchaseDateRegex finds the purchase date in an item's text, e.g. "Ordered on January 15, 2026"
+var purchaseDateRegex = regexp.MustCompile(`(?i)(?:purchased|ordered|order placed)(?: on)?\s*:?\s*([^\n]+)`)
+
+// GetReturnableItems lists the items in the returns center whose return window is
+// still open, the ones closing soonest first
+func (c *Client) GetReturnableItems() ([]models.ReturnableItem, error) {
+	body, err := c.fetchReturnsPage(c.baseURL + returnsCenterPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch returns center: %w", err)
+	}
+
+	items, err := parseReturnableItemsHTML(body, c.now(), c.marketplace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse returns center: %w", err)
+	}
+
+	return items, nil
+}
+
+// GetReturnOptions lists the ways an order item can be returned: the method, its fee
+// and drop-off location, and how and when the refund is issued
+func (c *Client) GetReturnOptions(orderID, itemID string) ([]models.ReturnOption, error) {
+	if orderID == "" {
+		return nil, fmt.Errorf("order ID cannot be empty")
+	}
+	if !c.marketplace.ValidOrderID(orderID) {
+		return nil, fmt.Errorf("invalid order ID format: expected XXX-XXXXXXX-XXXXXXX, got %s", orderID)
+	}
+	if itemID == "" {
+		return nil, fmt.Errorf("item ID cannot be empty")
+	}
+
+	params := url.Values{}
+	params.Set("orderID", orderID)
+	params.Set("itemID", itemID)
+	body, err := c.fetchReturnsPage(c.baseURL + returnOptionsPath + "?" + params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch return options: %w", err)
+	}
+
+	options, err := parseReturnOptionsHTML(body, c.marketplace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse return options: %w", err)
+	}
+	if len(options) == 0 {
+		return nil, fmt.Errorf("no return options for item %s of order %s", itemID, orderID)
+	}
+
+	return options, nil
+}
+
+// SelectReturnOption returns the option with the given method code
+func SelectReturnOption(options []models.ReturnOption, method string) (*models.ReturnOption, error) {
+	methods := make([]string, len(options))
+	for i, option := range options {
+		if option.Method == method {
+			return &options[i], nil
+		}
+		methods[i] = option.Method
+	}
+	return nil, fmt.Errorf("return method %q is not available for this item: use %s", method, strings.Join(methods, ", "))
+}
+
+// fetchReturnsPage fetches a returns center page and returns the body
+func (c *Client) fetchReturnsPage(pageURL string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	body := &bytes.Buffer{}
+	if _, err := body.ReadFrom(resp.Body); err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if c.detectCAPTCHA(body.Bytes()) {
+		return nil, fmt.Errorf("CAPTCHA detected - Amazon is blocking automated access")
+	}
+
+	return body.Bytes(), nil
+}
+
+// ExpiringReturns keeps the items whose return window closes within the given number
+// of days; 0 keeps only the items that must be returned today
+func ExpiringReturns(items []models.ReturnableItem, days int) []models.ReturnableItem {
+	expiring := []models.ReturnableItem{}
+	for _, item := range items {
+		if item.ReturnWindow != "" && item.DaysLeft <= days {
+			expiring = append(expiring, item)
+		}
+	}
+	return expiring
+}
+
+// parseReturnableItemsHTML parses the items of a returns center page. Return deadlines
+// are converted to dates relative to now; items whose window has closed are dropped
+// and items without a recognizable deadline are listed last.
+func parseReturnableItemsHTML(html []byte, now time.Time, m *marketplace.Marketplace) ([]models.ReturnableItem, error) {
+	m = marketplaceOrDefault(m)
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(html))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	items := []models.ReturnableItem{}
+
+	doc.Find(".returnable-item").Each(func(i int, s *goquery.Selection) {
+		item := models.ReturnableItem{
+			OrderID:  s.AttrOr("data-order-id", ""),
+			ItemID:   s.AttrOr("data-item-id", ""),
+			ASIN:     s.AttrOr("data-asin", ""),
+			Title:    collapseWhitespace(s.Find(".item-title").First().Text()),
+			Quantity: 1,
+		}
+		text := s.Text()
+
+		// Fall back to the visible order number and the product link
+		if item.OrderID == "" {
+			item.OrderID = m.FindOrderID(s.Find(".order-id .value").Text())
+		}
+		if item.OrderID == "" {
+			item.OrderID = m.FindOrderID(text)
+		}
+		if item.ASIN == "" {
+			item.ASIN = strings.TrimSpace(s.Find(".item-asin .value").Text())
+		}
+		if item.ASIN == "" {
+			if match := productLinkRegex.FindStringSubmatch(s.Find("a[href]").AttrOr("href", "")); match != nil {
+				item.ASIN = match[1]
+			}
+		}
+		if item.Title == "" {
+			item.Title = collapseWhitespace(s.Find(`a[href*="/dp/"], a[href*="/gp/product/"]`).First().Text())
+		}
+		if item.ItemID == "" {
+			item.ItemID = item.ASIN
+		}
+
+		item.Price = m.ParsePrice(s.Find(".item-price .value, .item-price, .a-color-price").First().Text())
+		if quantity, err := strconv.Atoi(strings.TrimSpace(s.Find(".item-quantity .value").Text())); err == nil && quantity > 0 {
+			item.Quantity = quantity
+		}
+
+		// Extract the purchase date and the last day of the return window
+		purchaseText := s.Find(".purchase-date .value").Text()
+		if purchaseText == "" {
+			if match := purchaseDateRegex.FindStringSubmatch(text); match != nil {
+				purchaseText = match[1]
+			}
+		}
+		var purchased time.Time
+		if date, err := m.ParseDate(purchaseText); err == nil {
+			purchased = date
+			item.PurchaseDate = date.Format("2006-01-02")
+		}
+
+		windowText := s.Find(".return-window .value").Text()
+		if windowText == "" {
+			if match := returnWindowRegex.FindStringSubmatch(text); match != nil {
+				windowText = match[1]
+			}
+		}
+		if deadline, ok := parseReturnDeadline(windowText, purchased, today, m); ok {
+			item.DaysLeft = int(deadline.Sub(today).Hours() / 24)
+			if item.DaysLeft < 0 {
+				return
+			}
+			item.ReturnWindow = deadline.Format("2006-01-02")
+		}
+
+		if item.OrderID != "" && (item.ASIN != "" || item.Title != "") {
+			items = append(items, item)
+		}
+	})
+
+	// Closing soonest first; items without a deadline last
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if (a.ReturnWindow == "") != (b.ReturnWindow == "") {
+			return a.ReturnWindow != ""
+		}
+		return a.ReturnWindow < b.ReturnWindow
+	})
+
+	return items, nil
+}
+
+// parseReturnOptionsHTML parses the return options of an item. Options without a
+// fee are free; refunds not mentioning a gift card go to the original payment method.
+func parseReturnOptionsHTML(html []byte, m *marketplace.Marketplace) ([]models.ReturnOption, error) {
+	m = marketplaceOrDefault(m)
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(html))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	options := []models.ReturnOption{}
+	doc.Find(".return-option").Each(func(i int, s *goquery.Selection) {
+		option := models.ReturnOption{
+			Label:           collapseWhitespace(s.Find(".option-label").First().Text()),
+			DropoffLocation: collapseWhitespace(s.Find(".dropoff-location").First().Text()),
+			Fee:             m.ParsePrice(s.Find(".option-fee").First().Text()),
+			RefundTiming:    collapseWhitespace(s.Find(".refund-timing").First().Text()),
+		}
+		if option.Label == "" {
+			option.Label = collapseWhitespace(s.Find("label").First().Text())
+		}
+		option.Method = normalizeReturnMethod(s.AttrOr("data-method", ""), option.Label)
+
+		refund := strings.ToLower(s.Find(".refund-method").First().Text())
+		switch {
+		case strings.Contains(refund, "gift card"), strings.Contains(refund, "gift-card"):
+			option.RefundMethod = models.RefundGiftCard
+		case strings.TrimSpace(refund) != "":
+			option.RefundMethod = models.RefundOriginalPayment
+		}
+
+		if option.Method != "" {
+			options = append(options, option)
+		}
+	})
+
+	return options, nil
+}
+
+// normalizeReturnMethod returns the method code of a return option: its data-method
+// code when it is one, otherwise the code matching its label, otherwise the label
+// in snake case
+func normalizeReturnMethod(code, label string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	for _, p := range returnMethodPatterns {
+		if code == p.method {
+			return code
+		}
+	}
+	for _, text := range []string{code, label} {
+		for _, p := range returnMethodPatterns {
+			if p.pattern.MatchString(text) {
+				return p.method
+			}
+		}
+	}
+	if code != "" {
+		return strings.Trim(nonWordRegex.ReplaceAllString(code, "_"), "_")
+	}
+	return strings.Trim(nonWordRegex.ReplaceAllString(strings.ToLower(label), "_"), "_")
+}
+
+// parseReturnDeadline parses a return deadline. Pages often omit the year
+// ("closes on Feb 14"), in which case the deadline is the first such date on or
+// after the purchase date, or after today when the purchase date is unknown.
+func parseReturnDeadline(text string, purchased, today time.Time, m *marketplace.Marketplace) (time.Time, bool) {
+	if strings.TrimSpace(text) == "" {
+		return time.Time{}, false
+	}
+	if date, err := m.ParseDate(text); err == nil {
+		return date, true
+	}
+
+	after := purchased
+	if after.IsZero() {
+		after = today
+	}
+	text = strings.TrimRight(strings.TrimSpace(text), ".")
+	for _, sep := range []string{", ", " "} {
+		date, err := m.ParseDate(text + sep + strconv.Itoa(after.Year()))
+		if err != nil {
+			continue
+		}
+		if date.Before(after) {
+			date = date.AddDate(1, 0, 0)
+		}
+		return date, true
+	}
+	return time.Time{}, false
+}
+
 // CreateReturn creates a return request for an order item
 func (c *Client) CreateReturn(orderID, itemID, reason string) (*models.Return, error) {
 	// Validate orderID is not empty
@@ -50,7 +374,34 @@ func (c *Client) CreateReturn(orderID, itemID, reason string) (*models.Return, e
 		ItemID:    itemID,
 		Reason:    reason,
 		Status:    "pending",
-		CreatedAt: time.Now().Format(time.RFC3339),
+		CreatedAt: c.now().Format(time.RFC3339),
+	}
+
+	return ret, nil
+}
+
+// CreateReturnWithMethod creates a return sent back with the given method, which must
+// be one of the item's return options. An empty method leaves the choice to Amazon.
+func (c *Client) CreateReturnWithMethod(orderID, itemID, reason, method string) (*models.Return, error) {
+	// Choose the option before creating the return; invalid requests are reported by CreateReturn
+	var option *models.ReturnOption
+	if method != "" && orderID != "" && itemID != "" && validReturnReasons[reason] {
+		options, err := c.GetReturnOptions(orderID, itemID)
+		if err != nil {
+			return nil, err
+		}
+		if option, err = SelectReturnOption(options, method); err != nil {
+			return nil, err
+		}
+	}
+
+	ret, err := c.CreateReturn(orderID, itemID, reason)
+	if err != nil {
+		return nil, err
+	}
+	if option != nil {
+		ret.Method = option.Method
+		ret.RefundMethod = option.RefundMethod
 	}
 
 	return ret, nil
@@ -65,7 +416,7 @@ func (c *Client) GetReturnLabel(returnID string) (*models.ReturnLabel, error) {
 
 	// Create mock return label data
 	label := &models.ReturnLabel{
-		URL:          fmt.Sprintf("https://amazon.com/returns/label/%s.pdf", returnID),
+		URL:          fmt.Sprintf("%s/returns/label/%s.pdf", c.baseURL, returnID),
 		Carrier:      "UPS",
 		Instructions: "Print this label and attach it to your package. Drop off at any UPS location.",
 	}
@@ -87,7 +438,7 @@ func (c *Client) GetReturnStatus(returnID string) (*models.Return, error) {
 		ItemID:    "item-12345",
 		Reason:    "defective",
 		Status:    "approved",
-		CreatedAt: time.Now().Add(-24 * time.Hour).Format(time.RFC3339),
+		CreatedAt: c.now().Add(-24 * time.Hour).Format(time.RFC3339),
 	}
 
 	return ret, nil
@@ -0,0 +1,226 @@
+package amazon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zkwentz/amazon-cli/internal/marketplace"
+	"github.com/zkwentz/amazon-cli/internal/ratelimit"
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+func TestGetReturnableItems(t *testing.T) {
+	fixtureData, err := os.ReadFile(filepath.Join("..", "..", "testdata", "returns", "returnable_items_sample.html"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture file: %v", err)
+	}
+
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		_, _ = w.Write(fixtureData)
+	}))
+	defer server.Close()
+
+	clock := &fixedClock{now: time.Date(2026, 2, 1, 18, 30, 0, 0, time.UTC)}
+	client := NewClient(WithBaseURL(server.URL), WithClock(clock), WithRateLimiter(ratelimit.NewRateLimiter(0, 0, 0)))
+
+	items, err := client.GetReturnableItems()
+	if err != nil {
+		t.Fatalf("GetReturnableItems() error = %v", err)
+	}
+	if path != returnsCenterPath {
+		t.Errorf("Expected a request for the returns center, got %s", path)
+	}
+
+	// The cable's window closes first
+	expected := []models.ReturnableItem{
+		{
+			OrderID: "111-4444444-5555555", ItemID: "ITM002", ASIN: "B09XYZ1234",
+			Title: "USB-C to USB-A Cable 6ft (2-Pack)", Price: 12.49, Quantity: 2,
+			PurchaseDate: "2026-01-10", ReturnWindow: "2026-02-09", DaysLeft: 8,
+		},
+		{
+			OrderID: "111-2222222-3333333", ItemID: "ITM001", ASIN: "B08N5WRWNW",
+			Title: "Wireless Bluetooth Headphones - Noise Cancelling Over Ear", Price: 59.99, Quantity: 1,
+			PurchaseDate: "2026-01-15", ReturnWindow: "2026-02-14", DaysLeft: 13,
+		},
+	}
+	if len(items) != len(expected) {
+		t.Fatalf("Expected %d items, got %+v", len(expected), items)
+	}
+	for i := range expected {
+		if items[i] != expected[i] {
+			t.Errorf("Item %d: expected %+v, got %+v", i, expected[i], items[i])
+		}
+	}
+
+	if expiring := ExpiringReturns(items, 7); len(expiring) != 0 {
+		t.Errorf("Expected nothing expiring within 7 days, got %+v", expiring)
+	}
+	if expiring := ExpiringReturns(items, 8); len(expiring) != 1 || expiring[0].ItemID != "ITM002" {
+		t.Errorf("Expected the cable to expire within 8 days, got %+v", expiring)
+	}
+}
+
+func TestParseReturnableItemsHTML_TextDeadlines(t *testing.T) {
+	html := []byte(`<html><body>
+<div class="returnable-item">
+  <a href="/dp/B0LAMP0001">Desk Lamp</a>
+  <div>Order # 112-0000000-0000001</div>
+  <div>Ordered on December 20, 2025</div>
+  <div>Eligible for Return through Jan 31</div>
+  <span class="a-color-price">$40.00</span>
+</div>
+<div class="returnable-item" data-order-id="112-0000000-0000002">
+  <a href="/dp/B0MUG00002">Coffee Mug</a>
+  <div>Return window closes on January 10, 2026</div>
+</div>
+<div class="returnable-item" data-order-id="112-0000000-0000003">
+  <span class="item-title">Gift Card Holder</span>
+</div>
+</body></html>`)
+
+	today := time.Date(2026, 1, 12, 0, 0, 0, 0, time.UTC)
+	items, err := parseReturnableItemsHTML(html, today, marketplace.Default())
+	if err != nil {
+		t.Fatalf("parseReturnableItemsHTML() error = %v", err)
+	}
+
+	// The mug's window has closed; the holder has no deadline and is listed last
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 items, got %+v", items)
+	}
+	lamp := items[0]
+	if lamp.OrderID != "112-0000000-0000001" || lamp.ASIN != "B0LAMP0001" || lamp.ItemID != "B0LAMP0001" || lamp.Title != "Desk Lamp" || lamp.Price != 40 {
+		t.Errorf("Unexpected lamp: %+v", lamp)
+	}
+	// A deadline without a year falls after the purchase date
+	if lamp.PurchaseDate != "2025-12-20" || lamp.ReturnWindow != "2026-01-31" || lamp.DaysLeft != 19 {
+		t.Errorf("Expected the lamp returnable until 2026-01-31, got %+v", lamp)
+	}
+	if items[1].Title != "Gift Card Holder" || items[1].ReturnWindow != "" {
+		t.Errorf("Expected the holder without a deadline, got %+v", items[1])
+	}
+	if expiring := ExpiringReturns(items, 30); len(expiring) != 1 {
+		t.Errorf("Expected items without a deadline to be left out, got %+v", expiring)
+	}
+}
+
+// returnOptionsServer serves the return options fixture for the given order and item
+func returnOptionsServer(t *testing.T, orderID, itemID string) *httptest.Server {
+	t.Helper()
+
+	fixtureData, err := os.ReadFile(filepath.Join("..", "..", "testdata", "returns", "return_options_sample.html"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture file: %v", err)
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != returnOptionsPath || r.URL.Query().Get("orderID") != orderID || r.URL.Query().Get("itemID") != itemID {
+			_, _ = w.Write([]byte(`<html><body><div id="return-options"></div></body></html>`))
+			return
+		}
+		_, _ = w.Write(fixtureData)
+	}))
+}
+
+func TestGetReturnOptions(t *testing.T) {
+	server := returnOptionsServer(t, "111-2222222-3333333", "ITM001")
+	defer server.Close()
+	client := newSyncTestClient(server.URL)
+
+	options, err := client.GetReturnOptions("111-2222222-3333333", "ITM001")
+	if err != nil {
+		t.Fatalf("GetReturnOptions() error = %v", err)
+	}
+
+	expected := []models.ReturnOption{
+		{
+			Method: models.ReturnMethodUPSDropoff, Label: "UPS Drop-off (no box or label needed)",
+			DropoffLocation: "The UPS Store - 123 Main St, Seattle, WA",
+			RefundMethod:    models.RefundOriginalPayment, RefundTiming: "Refund issued when the item is dropped off",
+		},
+		{
+			Method: models.ReturnMethodWholeFoods, Label: "Whole Foods Market drop-off",
+			DropoffLocation: "Whole Foods Market - 2210 Westlake Ave, Seattle, WA",
+			RefundMethod:    models.RefundOriginalPayment, RefundTiming: "Refund issued when the item is dropped off",
+		},
+		{
+			Method: models.ReturnMethodKohls, Label: "Kohl's drop-off",
+			DropoffLocation: "Kohl's - 3000 NE 45th St, Seattle, WA",
+			RefundMethod:    models.RefundGiftCard, RefundTiming: "Refund issued within 2 hours of drop-off",
+		},
+		{
+			Method: models.ReturnMethodPickup, Label: "UPS Pickup from your address", Fee: 8.99,
+			RefundMethod: models.RefundOriginalPayment, RefundTiming: "Refund issued when UPS scans the package",
+		},
+		{
+			Method: models.ReturnMethodMail, Label: "Ship it yourself with a prepaid label (mail)", Fee: 6.99,
+			RefundMethod: models.RefundOriginalPayment, RefundTiming: "Refund issued after we receive the item (up to 14 days)",
+		},
+	}
+	if len(options) != len(expected) {
+		t.Fatalf("Expected %d options, got %+v", len(expected), options)
+	}
+	for i := range expected {
+		if options[i] != expected[i] {
+			t.Errorf("Option %d: expected %+v, got %+v", i, expected[i], options[i])
+		}
+	}
+
+	// An item without options can't be returned
+	if _, err := client.GetReturnOptions("111-2222222-3333333", "ITM999"); err == nil || !strings.Contains(err.Error(), "no return options") {
+		t.Errorf("Expected a no-options error, got %v", err)
+	}
+	if _, err := client.GetReturnOptions("not-an-order", "ITM001"); err == nil || !strings.Contains(err.Error(), "invalid order ID format") {
+		t.Errorf("Expected an invalid order ID error, got %v", err)
+	}
+}
+
+func TestNormalizeReturnMethod(t *testing.T) {
+	tests := []struct {
+		code, label, expected string
+	}{
+		{"WHOLE_FOODS", "", models.ReturnMethodWholeFoods},
+		{"", "Drop off at Amazon Locker", "drop_off_at_amazon_locker"},
+		{"LOCKER", "Amazon Locker", "locker"},
+		{"", "Return at a Groups desk", "return_at_a_groups_desk"},
+		{"", "Schedule a pick-up", models.ReturnMethodPickup},
+	}
+	for _, tt := range tests {
+		if got := normalizeReturnMethod(tt.code, tt.label); got != tt.expected {
+			t.Errorf("normalizeReturnMethod(%q, %q) = %q, want %q", tt.code, tt.label, got, tt.expected)
+		}
+	}
+}
+
+func TestCreateReturnWithMethod(t *testing.T) {
+	server := returnOptionsServer(t, "111-2222222-3333333", "ITM001")
+	defer server.Close()
+	client := newSyncTestClient(server.URL)
+
+	ret, err := client.CreateReturnWithMethod("111-2222222-3333333", "ITM001", "defective", models.ReturnMethodKohls)
+	if err != nil {
+		t.Fatalf("CreateReturnWithMethod() error = %v", err)
+	}
+	if ret.Method != models.ReturnMethodKohls || ret.RefundMethod != models.RefundGiftCard || ret.Reason != "defective" {
+		t.Errorf("Unexpected return: %+v", ret)
+	}
+
+	_, err = client.CreateReturnWithMethod("111-2222222-3333333", "ITM001", "defective", "courier")
+	if err == nil || !strings.Contains(err.Error(), "ups_dropoff, whole_foods, kohls, pickup, mail") {
+		t.Errorf("Expected an error listing the available methods, got %v", err)
+	}
+
+	// An invalid reason is reported before the options are fetched
+	_, err = client.CreateReturnWithMethod("111-2222222-3333333", "ITM001", "bored", "courier")
+	if err == nil || !strings.Contains(err.Error(), "invalid return reason") {
+		t.Errorf("Expected an invalid reason error, got %v", err)
+	}
+}
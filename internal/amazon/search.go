@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/zkwentz/amazon-cli/internal/marketplace"
 	"github.com/zkwentz/amazon-cli/pkg/models"
 )
 
@@ -93,7 +94,7 @@ func (c *Client) Search(query string, opts models.SearchOptions) (*models.Search
 	}
 
 	// Parse the HTML response
-	products, err := parseSearchResultsHTML(body.Bytes())
+	products, err := parseSearchResultsHTML(body.Bytes(), c.marketplace)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse search results: %w", err)
 	}
@@ -107,7 +108,10 @@ func (c *Client) Search(query string, opts models.SearchOptions) (*models.Search
 }
 
 // parseSearchResultsHTML parses Amazon search results HTML and extracts product information
-func parseSearchResultsHTML(html []byte) ([]models.Product, error) {
+// Prices are interpreted using the given marketplace (nil means US).
+func parseSearchResultsHTML(html []byte, m *marketplace.Marketplace) ([]models.Product, error) {
+	m = marketplaceOrDefault(m)
+
 	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(html))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse HTML: %w", err)
@@ -125,7 +129,8 @@ func parseSearchResultsHTML(html []byte) ([]models.Product, error) {
 		}
 
 		product := models.Product{
-			ASIN: asin,
+			ASIN:     asin,
+			Currency: m.Currency,
 		}
 
 		// Extract title - multiple possible selectors
@@ -143,14 +148,14 @@ func parseSearchResultsHTML(html []byte) ([]models.Product, error) {
 		priceEl := s.Find(".a-price .a-offscreen, .a-price-whole")
 		if priceEl.Length() > 0 {
 			priceText := priceEl.First().Text()
-			product.Price = parsePriceFromText(priceText)
+			product.Price = m.ParsePrice(priceText)
 		}
 
 		// Extract original price (if on sale)
 		originalPriceEl := s.Find(".a-price.a-text-price .a-offscreen")
 		if originalPriceEl.Length() > 0 {
 			originalPriceText := originalPriceEl.First().Text()
-			originalPrice := parsePriceFromText(originalPriceText)
+			originalPrice := m.ParsePrice(originalPriceText)
 			if originalPrice > 0 && originalPrice != product.Price {
 				product.OriginalPrice = &originalPrice
 			}
@@ -198,28 +203,9 @@ func parseSearchResultsHTML(html []byte) ([]models.Product, error) {
 	return products, nil
 }
 
-// parsePriceFromText extracts a float64 price from a price string (e.g., "$29.99" -> 29.99)
+// parsePriceFromText extracts a float64 price from a US-formatted price string (e.g., "$29.99" -> 29.99)
 func parsePriceFromText(priceStr string) float64 {
-	// Remove whitespace
-	priceStr = strings.TrimSpace(priceStr)
-
-	// Use regex to extract numeric value (handles formats like $29.99, $1,299.99, etc.)
-	re := regexp.MustCompile(`[\d,]+\.?\d*`)
-	match := re.FindString(priceStr)
-	if match == "" {
-		return 0.0
-	}
-
-	// Remove commas
-	match = strings.ReplaceAll(match, ",", "")
-
-	// Parse to float
-	price, err := strconv.ParseFloat(match, 64)
-	if err != nil {
-		return 0.0
-	}
-
-	return price
+	return marketplace.Default().ParsePrice(priceStr)
 }
 
 // parseRating extracts rating from text like "4.5 out of 5 stars"
@@ -236,11 +222,11 @@ func parseRating(ratingText string) float64 {
 		}
 	}
 
-	// Try to find any decimal number in the format X.X
-	re = regexp.MustCompile(`\d+\.\d+`)
+	// Try to find any decimal number in the format X.X (or X,X on European marketplaces)
+	re = regexp.MustCompile(`\d+[.,]\d+`)
 	match := re.FindString(ratingText)
 	if match != "" {
-		rating, err := strconv.ParseFloat(match, 64)
+		rating, err := strconv.ParseFloat(strings.Replace(match, ",", ".", 1), 64)
 		if err == nil {
 			return rating
 		}
@@ -254,14 +240,15 @@ func parseReviewCount(reviewText string) int {
 	reviewText = strings.TrimSpace(reviewText)
 
 	// Remove "ratings" or other text, extract just the number
-	re := regexp.MustCompile(`([\d,]+)`)
+	// Counts are grouped with "," (US), "." (DE) or a space (FR)
+	re := regexp.MustCompile(`\d[\d,.\x{00a0}\x{202f}]*`)
 	match := re.FindString(reviewText)
 	if match == "" {
 		return 0
 	}
 
-	// Remove commas
-	match = strings.ReplaceAll(match, ",", "")
+	// Remove grouping separators
+	match = strings.NewReplacer(",", "", ".", "", "\u00a0", "", "\u202f", "").Replace(match)
 
 	// Parse to int
 	count, err := strconv.Atoi(match)
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/zkwentz/amazon-cli/internal/marketplace"
 	"github.com/zkwentz/amazon-cli/pkg/models"
 )
 
@@ -60,7 +61,7 @@ func TestParseSearchResultsHTML(t *testing.T) {
 		</html>
 	`)
 
-	products, err := parseSearchResultsHTML(html)
+	products, err := parseSearchResultsHTML(html, marketplace.Default())
 	if err != nil {
 		t.Fatalf("parseSearchResultsHTML failed: %v", err)
 	}
@@ -134,7 +135,7 @@ func TestParseSearchResultsHTML(t *testing.T) {
 func TestParseSearchResultsHTML_EmptyHTML(t *testing.T) {
 	html := []byte(`<html><body></body></html>`)
 
-	products, err := parseSearchResultsHTML(html)
+	products, err := parseSearchResultsHTML(html, marketplace.Default())
 	if err != nil {
 		t.Fatalf("parseSearchResultsHTML failed: %v", err)
 	}
@@ -148,7 +149,7 @@ func TestParseSearchResultsHTML_InvalidHTML(t *testing.T) {
 	html := []byte(`not valid html`)
 
 	// Should still parse but return empty results
-	products, err := parseSearchResultsHTML(html)
+	products, err := parseSearchResultsHTML(html, marketplace.Default())
 	if err != nil {
 		t.Fatalf("parseSearchResultsHTML failed on invalid HTML: %v", err)
 	}
@@ -168,7 +169,7 @@ func TestParseSearchResultsHTML_MissingFields(t *testing.T) {
 		</html>
 	`)
 
-	products, err := parseSearchResultsHTML(html)
+	products, err := parseSearchResultsHTML(html, marketplace.Default())
 	if err != nil {
 		t.Fatalf("parseSearchResultsHTML failed: %v", err)
 	}
@@ -192,7 +193,7 @@ func TestParseSearchResultsHTML_NoASIN(t *testing.T) {
 		</html>
 	`)
 
-	products, err := parseSearchResultsHTML(html)
+	products, err := parseSearchResultsHTML(html, marketplace.Default())
 	if err != nil {
 		t.Fatalf("parseSearchResultsHTML failed: %v", err)
 	}
@@ -321,7 +322,7 @@ func TestParseSearchResultsHTML_VariousPrimeIndicators(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			products, err := parseSearchResultsHTML(tt.html)
+			products, err := parseSearchResultsHTML(tt.html, marketplace.Default())
 			if err != nil {
 				t.Fatalf("parseSearchResultsHTML failed: %v", err)
 			}
@@ -361,7 +362,7 @@ func TestParseSearchResultsHTML_StockStatus(t *testing.T) {
 				</body></html>
 			`)
 
-			products, err := parseSearchResultsHTML(html)
+			products, err := parseSearchResultsHTML(html, marketplace.Default())
 			if err != nil {
 				t.Fatalf("parseSearchResultsHTML failed: %v", err)
 			}
@@ -405,7 +406,7 @@ func TestParseSearchResultsHTML_MultipleProducts(t *testing.T) {
 		</html>
 	`)
 
-	products, err := parseSearchResultsHTML(html)
+	products, err := parseSearchResultsHTML(html, marketplace.Default())
 	if err != nil {
 		t.Fatalf("parseSearchResultsHTML failed: %v", err)
 	}
@@ -0,0 +1,87 @@
+package amazon
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zkwentz/amazon-cli/internal/marketplace"
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+// normalizeOrderStatus maps free-form order status text (e.g. "Delivered Jan 18")
+// in the marketplace's language to one of the models.OrderStatus* values
+func normalizeOrderStatus(text string, m *marketplace.Marketplace) string {
+	return marketplaceOrDefault(m).OrderStatus(text)
+}
+
+// OrderStatuses returns the normalized order statuses accepted by status filters
+func OrderStatuses() []string {
+	return []string{
+		models.OrderStatusPending, models.OrderStatusOutForDelivery, models.OrderStatusDelayed,
+		models.OrderStatusDelivered, models.OrderStatusCancelled, models.OrderStatusReturnStarted,
+		models.OrderStatusReturned, models.OrderStatusRefunded, models.OrderStatusUnknown,
+	}
+}
+
+// ValidateOrderStatus checks a status filter; empty means any status
+func ValidateOrderStatus(status string) error {
+	if status == "" {
+		return nil
+	}
+	for _, s := range OrderStatuses() {
+		if status == s {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported order status %q: use %s", status, strings.Join(OrderStatuses(), ", "))
+}
+
+// orderSubStatuses lists the finer statuses split out of pending and returned, which
+// those filters still match so they select the same orders as before the split
+var orderSubStatuses = map[string][]string{
+	models.OrderStatusPending:  {models.OrderStatusOutForDelivery, models.OrderStatusDelayed},
+	models.OrderStatusReturned: {models.OrderStatusReturnStarted, models.OrderStatusRefunded},
+}
+
+// orderStatusMatches reports whether an order's status satisfies a status filter
+func orderStatusMatches(filter, status string) bool {
+	if filter == "" || filter == status {
+		return true
+	}
+	for _, sub := range orderSubStatuses[filter] {
+		if status == sub {
+			return true
+		}
+	}
+	return false
+}
+
+// Outcomes that end a tracking watch
+const (
+	TrackingDelivered = "delivered"
+	TrackingException = "exception"
+)
+
+// trackingExceptionPhrases mark tracking statuses that need attention and won't
+// resolve to a delivery on their own
+var trackingExceptionPhrases = []string{
+	"exception", "undeliverable", "unable to deliver", "not delivered",
+	"returned to sender", "returning to sender", "lost", "damaged",
+}
+
+// trackingOutcome classifies a tracking status as delivered, an exception, or
+// still in progress (empty)
+func trackingOutcome(status string) string {
+	status = strings.ToLower(strings.TrimSpace(status))
+
+	// Check exceptions first: "not delivered" contains "delivered"
+	for _, phrase := range trackingExceptionPhrases {
+		if strings.Contains(status, phrase) {
+			return TrackingException
+		}
+	}
+	if strings.Contains(status, "delivered") {
+		return TrackingDelivered
+	}
+	return ""
+}
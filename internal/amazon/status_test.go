@@ -0,0 +1,112 @@
+package amazon
+
+import (
+	"testing"
+
+	"github.com/zkwentz/amazon-cli/internal/marketplace"
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+func TestNormalizeOrderStatus(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Delivered Jan 18, 2026", models.OrderStatusDelivered},
+		{"  DELIVERED  ", models.OrderStatusDelivered},
+		{"Arriving Jan 20, 2026", models.OrderStatusPending},
+		{"Shipped", models.OrderStatusPending},
+		{"Not yet shipped", models.OrderStatusPending},
+		{"Out for delivery", models.OrderStatusOutForDelivery},
+		{"Delayed, now arriving Friday", models.OrderStatusDelayed},
+		{"Running late", models.OrderStatusDelayed},
+		{"Cancelled", models.OrderStatusCancelled},
+		{"Canceled", models.OrderStatusCancelled},
+		{"Return started", models.OrderStatusReturnStarted},
+		{"Return complete", models.OrderStatusReturned},
+		{"Returned", models.OrderStatusReturned},
+		{"Refunded", models.OrderStatusRefunded},
+		{"Return complete, refund issued", models.OrderStatusRefunded},
+		{"", models.OrderStatusUnknown},
+		{"Something else entirely", models.OrderStatusUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := normalizeOrderStatus(tt.input, nil); got != tt.expected {
+				t.Errorf("normalizeOrderStatus(%q) = %q, expected %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNormalizeOrderStatus_Localized(t *testing.T) {
+	tests := []struct {
+		code     string
+		input    string
+		expected string
+	}{
+		{"de", "Zugestellt am 18. Januar", models.OrderStatusDelivered},
+		{"de", "Storniert", models.OrderStatusCancelled},
+		{"de", "Versandt", models.OrderStatusPending},
+		{"de", "Rücksendung gestartet", models.OrderStatusReturnStarted},
+		{"de", "Erstattet", models.OrderStatusRefunded},
+		{"de", "Delivered Jan 18, 2026", models.OrderStatusDelivered},
+		{"fr", "Livré le 18 janvier", models.OrderStatusDelivered},
+		{"fr", "Commande annulée", models.OrderStatusCancelled},
+		{"fr", "En cours de livraison", models.OrderStatusOutForDelivery},
+		{"fr", "Remboursé", models.OrderStatusRefunded},
+		{"jp", "配達済み", models.OrderStatusDelivered},
+		{"jp", "キャンセル済み", models.OrderStatusCancelled},
+		{"jp", "発送済み", models.OrderStatusPending},
+		{"jp", "返金済み", models.OrderStatusRefunded},
+		{"us", "Zugestellt", models.OrderStatusUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code+"/"+tt.input, func(t *testing.T) {
+			m, err := marketplace.Get(tt.code)
+			if err != nil {
+				t.Fatalf("marketplace.Get(%q) error = %v", tt.code, err)
+			}
+			if got := normalizeOrderStatus(tt.input, m); got != tt.expected {
+				t.Errorf("normalizeOrderStatus(%q, %s) = %q, expected %q", tt.input, tt.code, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOrderStatusMatches(t *testing.T) {
+	tests := []struct {
+		filter string
+		status string
+		want   bool
+	}{
+		{"", models.OrderStatusDelivered, true},
+		{models.OrderStatusDelivered, models.OrderStatusDelivered, true},
+		{models.OrderStatusPending, models.OrderStatusOutForDelivery, true},
+		{models.OrderStatusPending, models.OrderStatusDelayed, true},
+		{models.OrderStatusPending, models.OrderStatusDelivered, false},
+		{models.OrderStatusReturned, models.OrderStatusReturnStarted, true},
+		{models.OrderStatusReturned, models.OrderStatusRefunded, true},
+		{models.OrderStatusOutForDelivery, models.OrderStatusPending, false},
+		{models.OrderStatusRefunded, models.OrderStatusReturned, false},
+	}
+
+	for _, tt := range tests {
+		if got := orderStatusMatches(tt.filter, tt.status); got != tt.want {
+			t.Errorf("orderStatusMatches(%q, %q) = %v, expected %v", tt.filter, tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestValidateOrderStatus(t *testing.T) {
+	for _, status := range append(OrderStatuses(), "") {
+		if err := ValidateOrderStatus(status); err != nil {
+			t.Errorf("ValidateOrderStatus(%q) error = %v", status, err)
+		}
+	}
+	if err := ValidateOrderStatus("shipped"); err == nil {
+		t.Error("Expected an error for an unknown status")
+	}
+}
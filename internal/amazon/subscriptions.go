@@ -2,7 +2,6 @@ package amazon
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/zkwentz/amazon-cli/pkg/models"
 )
@@ -19,7 +18,7 @@ func (c *Client) GetSubscriptions() (*models.SubscriptionList, error) {
 			Price:          24.99,
 			Discount:       5.0,
 			FrequencyWeeks: 4,
-			NextDelivery:   time.Now().AddDate(0, 0, 14),
+			NextDelivery:   c.now().AddDate(0, 0, 14),
 			Status:         "active",
 			Quantity:       1,
 		},
@@ -30,7 +29,7 @@ func (c *Client) GetSubscriptions() (*models.SubscriptionList, error) {
 			Price:          29.99,
 			Discount:       10.0,
 			FrequencyWeeks: 8,
-			NextDelivery:   time.Now().AddDate(0, 0, 21),
+			NextDelivery:   c.now().AddDate(0, 0, 21),
 			Status:         "active",
 			Quantity:       2,
 		},
@@ -57,7 +56,7 @@ func (c *Client) SkipDelivery(id string) (*models.Subscription, error) {
 		Price:          24.99,
 		Discount:       5.0,
 		FrequencyWeeks: 4,
-		NextDelivery:   time.Now().AddDate(0, 0, 14), // Current next delivery (2 weeks from now)
+		NextDelivery:   c.now().AddDate(0, 0, 14), // Current next delivery (2 weeks from now)
 		Status:         "active",
 		Quantity:       1,
 	}
@@ -83,7 +82,7 @@ func (c *Client) CancelSubscription(id string) (*models.Subscription, error) {
 		Price:          24.99,
 		Discount:       5.0,
 		FrequencyWeeks: 4,
-		NextDelivery:   time.Now().AddDate(0, 0, 14),
+		NextDelivery:   c.now().AddDate(0, 0, 14),
 		Status:         "active",
 		Quantity:       1,
 	}
@@ -113,7 +112,7 @@ func (c *Client) UpdateFrequency(id string, intervalWeeks int) (*models.Subscrip
 		Price:          24.99,
 		Discount:       5.0,
 		FrequencyWeeks: 4,
-		NextDelivery:   time.Now().AddDate(0, 0, 14),
+		NextDelivery:   c.now().AddDate(0, 0, 14),
 		Status:         "active",
 		Quantity:       1,
 	}
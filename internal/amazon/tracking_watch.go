@@ -0,0 +1,103 @@
+package amazon
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+// watchMaxFailures is how many polls in a row may fail before a watch gives up
+const watchMaxFailures = 5
+
+// WatchOrderTracking polls an order's tracking every interval until the shipment is
+// delivered or hits an exception, or ctx is cancelled. fn receives each tracking event
+// not seen in an earlier poll, each status change that brings no new event, and a final
+// update carrying the outcome, which is also returned. A failed poll is reported to fn
+// with its error and retried at the next interval; the watch only gives up after
+// watchMaxFailures failures in a row. Changes between polls are also
+// sent to the notifier, if one is set. Polls go through the client's rate limiter like
+// any other request.
+func (c *Client) WatchOrderTracking(ctx context.Context, orderID string, interval time.Duration, fn func(models.TrackingUpdate) error) (string, error) {
+	return c.WatchShipmentTracking(ctx, orderID, "", interval, fn)
+}
+
+// WatchShipmentTracking is WatchOrderTracking for one package of an order. An empty
+// shipment ID watches the order as a whole.
+func (c *Client) WatchShipmentTracking(ctx context.Context, orderID, shipmentID string, interval time.Duration, fn func(models.TrackingUpdate) error) (string, error) {
+	// A malformed order ID would fail every poll, so reject it up front
+	if !c.marketplace.ValidOrderID(orderID) {
+		return "", fmt.Errorf("invalid order ID format: expected XXX-XXXXXXX-XXXXXXX, got %s", orderID)
+	}
+
+	seen := make(map[string]bool)
+	lastStatus := ""
+	var previous *models.Tracking
+	failures := 0
+
+	for {
+		tracking, err := c.GetShipmentTracking(orderID, shipmentID)
+		if err != nil {
+			failures++
+			if failures >= watchMaxFailures {
+				return "", fmt.Errorf("tracking failed %d times in a row: %w", failures, err)
+			}
+			if err := fn(models.TrackingUpdate{OrderID: orderID, ShipmentID: shipmentID, Status: lastStatus, Error: err.Error()}); err != nil {
+				return "", err
+			}
+			if err := waitInterval(ctx, interval); err != nil {
+				return "", err
+			}
+			continue
+		}
+		failures = 0
+
+		// Notify hooks of changes since the previous poll; the first poll is the baseline
+		c.notify(models.HookSourceWatch, trackingChangeEvents(orderID, shipmentID, previous, tracking))
+		previous = tracking
+
+		// Report events added since the previous poll
+		emitted := false
+		for _, event := range tracking.Events {
+			key := trackingEventKey(event)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			emitted = true
+
+			event := event
+			if err := fn(models.TrackingUpdate{OrderID: orderID, ShipmentID: shipmentID, Status: tracking.Status, Event: &event}); err != nil {
+				return "", err
+			}
+		}
+
+		outcome := trackingOutcome(tracking.Status)
+		if outcome != "" {
+			return outcome, fn(models.TrackingUpdate{OrderID: orderID, ShipmentID: shipmentID, Status: tracking.Status, Outcome: outcome})
+		}
+		if tracking.Status != lastStatus && !emitted {
+			if err := fn(models.TrackingUpdate{OrderID: orderID, ShipmentID: shipmentID, Status: tracking.Status}); err != nil {
+				return "", err
+			}
+		}
+		lastStatus = tracking.Status
+
+		if err := waitInterval(ctx, interval); err != nil {
+			return "", err
+		}
+	}
+}
+
+// waitInterval waits for the next poll, returning early with ctx's error if it is cancelled
+func waitInterval(ctx context.Context, interval time.Duration) error {
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,213 @@
+package amazon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+// trackingPage renders a tracking page with a status and events
+func trackingPage(status string, events ...string) string {
+	var html strings.Builder
+	fmt.Fprintf(&html, `<html><body><div class="tracking-carrier"><span class="value">UPS</span></div><div class="tracking-number"><span class="value">1Z999AA10123456784</span></div><div class="tracking-status"><span class="value">%s</span></div><div class="tracking-events">`, status)
+	for _, event := range events {
+		fmt.Fprintf(&html, `<div class="event"><span class="event-location">Seattle, WA</span><span class="event-status">%s</span></div>`, event)
+	}
+	html.WriteString(`</div></body></html>`)
+	return html.String()
+}
+
+// trackingSequenceServer serves the given pages one per request, repeating the last one
+func trackingSequenceServer(pages ...string) (*httptest.Server, *int) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := pages[len(pages)-1]
+		if polls < len(pages) {
+			page = pages[polls]
+		}
+		polls++
+		_, _ = w.Write([]byte(page))
+	}))
+	return server, &polls
+}
+
+func TestWatchOrderTracking_EmitsOnlyChanges(t *testing.T) {
+	server, polls := trackingSequenceServer(
+		trackingPage("Shipped", "Package left the facility"),
+		trackingPage("Shipped", "Package left the facility"),
+		trackingPage("Out for delivery", "Package left the facility"),
+		trackingPage("Delivered", "Package left the facility", "Delivered to front door"),
+	)
+	defer server.Close()
+
+	client := newSyncTestClient(server.URL)
+
+	var updates []models.TrackingUpdate
+	outcome, err := client.WatchOrderTracking(context.Background(), "123-4567890-1234567", time.Millisecond, func(update models.TrackingUpdate) error {
+		updates = append(updates, update)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WatchOrderTracking() error = %v", err)
+	}
+
+	if outcome != TrackingDelivered {
+		t.Errorf("Expected delivered outcome, got %q", outcome)
+	}
+	if *polls != 4 {
+		t.Errorf("Expected 4 polls, got %d", *polls)
+	}
+
+	// The repeated poll emits nothing; the status change without events emits a status update
+	expected := []struct {
+		event   string
+		status  string
+		outcome string
+	}{
+		{"Package left the facility", "shipped", ""},
+		{"", "out for delivery", ""},
+		{"Delivered to front door", "delivered", ""},
+		{"", "delivered", TrackingDelivered},
+	}
+	if len(updates) != len(expected) {
+		t.Fatalf("Expected %d updates, got %+v", len(expected), updates)
+	}
+	for i, want := range expected {
+		got := updates[i]
+		event := ""
+		if got.Event != nil {
+			event = got.Event.Status
+		}
+		if event != want.event || got.Status != want.status || got.Outcome != want.outcome {
+			t.Errorf("Update %d: expected %+v, got %+v (event %q)", i, want, got, event)
+		}
+	}
+}
+
+func TestWatchOrderTracking_StopsOnExceptionAndCancel(t *testing.T) {
+	server, _ := trackingSequenceServer(trackingPage("Shipped"), trackingPage("Delivery exception: address issue"))
+	defer server.Close()
+
+	client := newSyncTestClient(server.URL)
+	outcome, err := client.WatchOrderTracking(context.Background(), "123-4567890-1234567", time.Millisecond, func(models.TrackingUpdate) error { return nil })
+	if err != nil || outcome != TrackingException {
+		t.Errorf("Expected exception outcome, got %q (%v)", outcome, err)
+	}
+
+	// A cancelled context stops the watch between polls
+	inTransit, _ := trackingSequenceServer(trackingPage("Shipped"))
+	defer inTransit.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client = newSyncTestClient(inTransit.URL)
+	_, err = client.WatchOrderTracking(ctx, "123-4567890-1234567", time.Hour, func(models.TrackingUpdate) error {
+		cancel()
+		return nil
+	})
+	if err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestWatchOrderTracking_RetriesFailedPolls(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		switch polls {
+		case 2, 3:
+			w.WriteHeader(http.StatusNotFound)
+		case 1:
+			_, _ = w.Write([]byte(trackingPage("Shipped", "Package left the facility")))
+		default:
+			_, _ = w.Write([]byte(trackingPage("Delivered", "Package left the facility", "Delivered to front door")))
+		}
+	}))
+	defer server.Close()
+
+	client := newSyncTestClient(server.URL)
+
+	var updates []models.TrackingUpdate
+	outcome, err := client.WatchOrderTracking(context.Background(), "123-4567890-1234567", time.Millisecond, func(update models.TrackingUpdate) error {
+		updates = append(updates, update)
+		return nil
+	})
+	if err != nil || outcome != TrackingDelivered {
+		t.Fatalf("Expected the watch to recover and report delivery, got %q (%v)", outcome, err)
+	}
+
+	failed := 0
+	for _, update := range updates {
+		if update.Error != "" {
+			failed++
+			if update.Status != "shipped" {
+				t.Errorf("Expected a failed poll to keep the last status, got %+v", update)
+			}
+		}
+	}
+	if failed != 2 {
+		t.Errorf("Expected 2 failed polls to be reported, got %+v", updates)
+	}
+}
+
+func TestWatchOrderTracking_StopsAfterConsecutiveFailures(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := newSyncTestClient(server.URL)
+
+	reported := 0
+	_, err := client.WatchOrderTracking(context.Background(), "123-4567890-1234567", time.Millisecond, func(update models.TrackingUpdate) error {
+		reported++
+		return nil
+	})
+	if err == nil {
+		t.Fatal("Expected the watch to give up")
+	}
+	if reported != watchMaxFailures-1 {
+		t.Errorf("Expected %d failed polls reported before giving up, got %d", watchMaxFailures-1, reported)
+	}
+	if polls < watchMaxFailures {
+		t.Errorf("Expected at least %d polls, got %d", watchMaxFailures, polls)
+	}
+}
+
+func TestWatchOrderTracking_RejectsInvalidOrderID(t *testing.T) {
+	server, polls := trackingSequenceServer(trackingPage("Shipped"))
+	defer server.Close()
+
+	client := newSyncTestClient(server.URL)
+	_, err := client.WatchOrderTracking(context.Background(), "not-an-order", time.Millisecond, func(models.TrackingUpdate) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "invalid order ID format") {
+		t.Errorf("Expected invalid order ID error, got %v", err)
+	}
+	if *polls != 0 {
+		t.Errorf("Expected no polls for an invalid order ID, got %d", *polls)
+	}
+}
+
+func TestTrackingOutcome(t *testing.T) {
+	tests := map[string]string{
+		"delivered":                  TrackingDelivered,
+		"Delivered Jan 18":           TrackingDelivered,
+		"not delivered":              TrackingException,
+		"undeliverable":              TrackingException,
+		"package returned to sender": TrackingException,
+		"in transit":                 "",
+		"out for delivery":           "",
+	}
+	for status, expected := range tests {
+		if got := trackingOutcome(status); got != expected {
+			t.Errorf("trackingOutcome(%q) = %q, expected %q", status, got, expected)
+		}
+	}
+}
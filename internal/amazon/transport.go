@@ -0,0 +1,134 @@
+package amazon
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// Default transport settings used when a TransportConfig field is left empty
+const (
+	defaultRequestTimeout = 30 * time.Second
+	defaultDialTimeout    = 30 * time.Second
+	defaultKeepAlive      = 30 * time.Second
+)
+
+// TransportConfig holds the network settings used to build the client's HTTP transport
+type TransportConfig struct {
+	// ProxyURL is an explicit proxy (http://, https://, socks5:// or socks5h://).
+	// When empty the standard HTTP_PROXY/HTTPS_PROXY/NO_PROXY variables are honored.
+	ProxyURL string
+	// CAFile is a PEM bundle of additional trusted root certificates
+	CAFile string
+	// Timeout is the overall per-request timeout
+	Timeout time.Duration
+	// DialTimeout bounds establishing a TCP connection
+	DialTimeout time.Duration
+	// KeepAlive is the TCP keep-alive period for active connections
+	KeepAlive time.Duration
+	// DisableKeepAlives prevents reusing connections between requests
+	DisableKeepAlives bool
+	// DisableHTTP2 forces HTTP/1.1 even when the server offers HTTP/2
+	DisableHTTP2 bool
+}
+
+// NewHTTPClient builds an http.Client from the given transport configuration
+func NewHTTPClient(cfg TransportConfig) (*http.Client, error) {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultRequestTimeout
+	}
+	if cfg.DialTimeout <= 0 {
+		cfg.DialTimeout = defaultDialTimeout
+	}
+	if cfg.KeepAlive <= 0 {
+		cfg.KeepAlive = defaultKeepAlive
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   cfg.DialTimeout,
+		KeepAlive: cfg.KeepAlive,
+	}
+
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     !cfg.DisableHTTP2,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		DisableKeepAlives:     cfg.DisableKeepAlives,
+	}
+
+	// Use an explicit proxy if configured
+	if cfg.ProxyURL != "" {
+		proxyURL, err := parseProxyURL(cfg.ProxyURL)
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	// Trust additional root certificates if a CA bundle is configured
+	if cfg.CAFile != "" {
+		pool, err := loadCertPool(cfg.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	// A non-nil, empty TLSNextProto map disables HTTP/2 negotiation
+	if cfg.DisableHTTP2 {
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   cfg.Timeout,
+	}, nil
+}
+
+// parseProxyURL validates a proxy URL and its scheme
+func parseProxyURL(raw string) (*url.URL, error) {
+	proxyURL, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q (allowed: http, https, socks5, socks5h)", proxyURL.Scheme)
+	}
+
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL: missing host")
+	}
+
+	return proxyURL, nil
+}
+
+// loadCertPool returns the system cert pool extended with the certificates in caFile
+func loadCertPool(caFile string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no valid PEM certificates found in CA file %s", caFile)
+	}
+
+	return pool, nil
+}
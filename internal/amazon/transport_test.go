@@ -0,0 +1,282 @@
+package amazon
+
+import (
+	"encoding/binary"
+	"encoding/pem"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPClient_Defaults(t *testing.T) {
+	httpClient, err := NewHTTPClient(TransportConfig{})
+	if err != nil {
+		t.Fatalf("NewHTTPClient() error = %v", err)
+	}
+
+	if httpClient.Timeout != defaultRequestTimeout {
+		t.Errorf("expected default timeout %v, got %v", defaultRequestTimeout, httpClient.Timeout)
+	}
+
+	transport, ok := httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("expected *http.Transport, got %T", httpClient.Transport)
+	}
+	if !transport.ForceAttemptHTTP2 {
+		t.Error("expected HTTP/2 to be enabled by default")
+	}
+	if transport.DisableKeepAlives {
+		t.Error("expected keep-alives to be enabled by default")
+	}
+}
+
+func TestNewHTTPClient_TransportSettings(t *testing.T) {
+	httpClient, err := NewHTTPClient(TransportConfig{
+		Timeout:           5 * time.Second,
+		DisableKeepAlives: true,
+		DisableHTTP2:      true,
+	})
+	if err != nil {
+		t.Fatalf("NewHTTPClient() error = %v", err)
+	}
+
+	if httpClient.Timeout != 5*time.Second {
+		t.Errorf("expected timeout 5s, got %v", httpClient.Timeout)
+	}
+
+	transport := httpClient.Transport.(*http.Transport)
+	if !transport.DisableKeepAlives {
+		t.Error("expected keep-alives to be disabled")
+	}
+	if transport.ForceAttemptHTTP2 {
+		t.Error("expected HTTP/2 to be disabled")
+	}
+	if transport.TLSNextProto == nil {
+		t.Error("expected non-nil TLSNextProto to disable HTTP/2 negotiation")
+	}
+}
+
+func TestNewHTTPClient_InvalidProxy(t *testing.T) {
+	tests := []string{
+		"ftp://proxy.example.com:21",
+		"http://",
+		"://bad",
+	}
+
+	for _, proxy := range tests {
+		t.Run(proxy, func(t *testing.T) {
+			if _, err := NewHTTPClient(TransportConfig{ProxyURL: proxy}); err == nil {
+				t.Errorf("expected error for proxy %q", proxy)
+			}
+		})
+	}
+}
+
+func TestNewHTTPClient_HTTPProxy(t *testing.T) {
+	var proxied int32
+	// Stand-in for a corporate HTTP proxy: it receives absolute-form request URIs
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&proxied, 1)
+		if r.URL.Host != "www.amazon.com" {
+			t.Errorf("expected proxied request for www.amazon.com, got %q", r.URL.Host)
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("via proxy"))
+	}))
+	defer proxy.Close()
+
+	httpClient, err := NewHTTPClient(TransportConfig{ProxyURL: proxy.URL})
+	if err != nil {
+		t.Fatalf("NewHTTPClient() error = %v", err)
+	}
+
+	resp, err := httpClient.Get("http://www.amazon.com/gp/your-account/order-history")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "via proxy" {
+		t.Errorf("expected response from proxy, got %q", body)
+	}
+	if atomic.LoadInt32(&proxied) != 1 {
+		t.Errorf("expected 1 proxied request, got %d", proxied)
+	}
+}
+
+func TestNewHTTPClient_SOCKS5Proxy(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("via socks"))
+	}))
+	defer target.Close()
+
+	socks, connections := startSOCKS5StandIn(t)
+	defer socks.Close()
+
+	httpClient, err := NewHTTPClient(TransportConfig{ProxyURL: "socks5://" + socks.Addr().String()})
+	if err != nil {
+		t.Fatalf("NewHTTPClient() error = %v", err)
+	}
+
+	resp, err := httpClient.Get(target.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "via socks" {
+		t.Errorf("expected response through SOCKS5 proxy, got %q", body)
+	}
+	if atomic.LoadInt32(connections) == 0 {
+		t.Error("expected connection through SOCKS5 stand-in")
+	}
+}
+
+func TestNewHTTPClient_CustomCAFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("trusted"))
+	}))
+	defer server.Close()
+
+	// Without the CA bundle the self-signed test certificate is rejected
+	plain, _ := NewHTTPClient(TransportConfig{})
+	if _, err := plain.Get(server.URL); err == nil {
+		t.Fatal("expected TLS verification error without custom CA")
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, certPEM, 0600); err != nil {
+		t.Fatalf("failed to write CA file: %v", err)
+	}
+
+	httpClient, err := NewHTTPClient(TransportConfig{CAFile: caFile})
+	if err != nil {
+		t.Fatalf("NewHTTPClient() error = %v", err)
+	}
+
+	resp, err := httpClient.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() with custom CA error = %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestNewHTTPClient_InvalidCAFile(t *testing.T) {
+	if _, err := NewHTTPClient(TransportConfig{CAFile: filepath.Join(t.TempDir(), "missing.pem")}); err == nil {
+		t.Error("expected error for missing CA file")
+	}
+
+	badFile := filepath.Join(t.TempDir(), "bad.pem")
+	_ = os.WriteFile(badFile, []byte("not a certificate"), 0600)
+	if _, err := NewHTTPClient(TransportConfig{CAFile: badFile}); err == nil {
+		t.Error("expected error for CA file without certificates")
+	}
+}
+
+func TestNewClient_WithTransportHTTPClient(t *testing.T) {
+	httpClient, err := NewHTTPClient(TransportConfig{Timeout: 10 * time.Second})
+	if err != nil {
+		t.Fatalf("NewHTTPClient() error = %v", err)
+	}
+
+	c := NewClient(WithHTTPClient(httpClient))
+	if c.httpClient.Timeout != 10*time.Second {
+		t.Errorf("expected timeout 10s, got %v", c.httpClient.Timeout)
+	}
+}
+
+// startSOCKS5StandIn starts a minimal no-auth SOCKS5 CONNECT proxy for tests
+func startSOCKS5StandIn(t *testing.T) (net.Listener, *int32) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start SOCKS5 stand-in: %v", err)
+	}
+
+	var connections int32
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			atomic.AddInt32(&connections, 1)
+			go serveSOCKS5(conn)
+		}
+	}()
+
+	return listener, &connections
+}
+
+// serveSOCKS5 handles a single SOCKS5 CONNECT session
+func serveSOCKS5(conn net.Conn) {
+	defer conn.Close()
+
+	// Greeting: VER, NMETHODS, METHODS...
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return
+	}
+	methods := make([]byte, header[1])
+	if _, err := io.ReadFull(conn, methods); err != nil {
+		return
+	}
+	_, _ = conn.Write([]byte{0x05, 0x00})
+
+	// Request: VER, CMD, RSV, ATYP, DST.ADDR, DST.PORT
+	request := make([]byte, 4)
+	if _, err := io.ReadFull(conn, request); err != nil {
+		return
+	}
+
+	var host string
+	switch request[3] {
+	case 0x01:
+		addr := make([]byte, 4)
+		if _, err := io.ReadFull(conn, addr); err != nil {
+			return
+		}
+		host = net.IP(addr).String()
+	case 0x03:
+		length := make([]byte, 1)
+		if _, err := io.ReadFull(conn, length); err != nil {
+			return
+		}
+		name := make([]byte, length[0])
+		if _, err := io.ReadFull(conn, name); err != nil {
+			return
+		}
+		host = string(name)
+	default:
+		return
+	}
+
+	portBytes := make([]byte, 2)
+	if _, err := io.ReadFull(conn, portBytes); err != nil {
+		return
+	}
+	port := binary.BigEndian.Uint16(portBytes)
+
+	upstream, err := net.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(int(port))))
+	if err != nil {
+		_, _ = conn.Write([]byte{0x05, 0x01, 0x00, 0x01, 0, 0, 0, 0, 0, 0})
+		return
+	}
+	defer upstream.Close()
+
+	_, _ = conn.Write([]byte{0x05, 0x00, 0x00, 0x01, 0, 0, 0, 0, 0, 0})
+
+	go func() { _, _ = io.Copy(upstream, conn) }()
+	_, _ = io.Copy(conn, upstream)
+}
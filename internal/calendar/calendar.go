@@ -0,0 +1,165 @@
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+// FormatICS is the iCalendar export format
+const FormatICS = "ics"
+
+// uidDomain qualifies event UIDs so they don't collide with other calendars' events
+const uidDomain = "amazon-cli"
+
+// maxLineOctets is the longest content line RFC 5545 allows before folding
+const maxLineOctets = 75
+
+// Event is an all-day calendar event
+type Event struct {
+	UID         string
+	Date        time.Time
+	Summary     string
+	Description string
+	URL         string
+}
+
+// DeliveryUID returns the stable UID of a delivery's event: the same order, package or
+// subscription always maps to the same UID, so re-importing updates the event
+func DeliveryUID(d models.Delivery) string {
+	if d.Source == models.DeliverySourceSubscription {
+		return "subscription-" + d.SubscriptionID + "@" + uidDomain
+	}
+	if d.ShipmentID != "" {
+		return "order-" + d.OrderID + "-" + d.ShipmentID + "@" + uidDomain
+	}
+	return "order-" + d.OrderID + "@" + uidDomain
+}
+
+// DeliveryEvents converts deliveries to all-day events on their expected date.
+// Deliveries without a YYYY-MM-DD expected date are skipped.
+func DeliveryEvents(deliveries []models.Delivery) []Event {
+	events := []Event{}
+	for _, d := range deliveries {
+		date, err := time.Parse("2006-01-02", d.ExpectedDate)
+		if err != nil {
+			continue
+		}
+
+		titles := make([]string, 0, len(d.Items))
+		for _, item := range d.Items {
+			if item.Title != "" {
+				titles = append(titles, item.Title)
+			}
+		}
+
+		event := Event{UID: DeliveryUID(d), Date: date}
+		switch {
+		case d.Source == models.DeliverySourceSubscription:
+			event.Summary = "Subscribe & Save delivery"
+		case d.ShipmentID != "":
+			event.Summary = "Amazon delivery (order " + d.OrderID + ", package " + d.ShipmentID + ")"
+		default:
+			event.Summary = "Amazon delivery (order " + d.OrderID + ")"
+		}
+		if len(titles) > 0 {
+			event.Summary += ": " + strings.Join(titles, ", ")
+		}
+
+		// Describe where the package is and how to follow it
+		var lines []string
+		if d.OrderID != "" {
+			lines = append(lines, "Order: "+d.OrderID)
+		}
+		if d.SubscriptionID != "" {
+			lines = append(lines, "Subscription: "+d.SubscriptionID)
+		}
+		if d.Status != "" {
+			lines = append(lines, "Status: "+d.Status)
+		}
+		if t := d.Tracking; t != nil {
+			if t.Carrier != "" {
+				lines = append(lines, "Carrier: "+t.Carrier)
+			}
+			if t.TrackingNumber != "" {
+				lines = append(lines, "Tracking number: "+t.TrackingNumber)
+			}
+			event.URL = t.TrackingURL
+		}
+		for _, title := range titles {
+			lines = append(lines, "- "+title)
+		}
+		event.Description = strings.Join(lines, "\n")
+
+		events = append(events, event)
+	}
+	return events
+}
+
+// Write writes the events as an RFC 5545 calendar. now is used as the DTSTAMP of
+// every event.
+func Write(w io.Writer, events []Event, now time.Time) error {
+	bw := bufio.NewWriter(w)
+	stamp := now.UTC().Format("20060102T150405Z")
+
+	writeLine(bw, "BEGIN:VCALENDAR")
+	writeLine(bw, "VERSION:2.0")
+	writeLine(bw, "PRODID:-//amazon-cli//Deliveries//EN")
+	writeLine(bw, "CALSCALE:GREGORIAN")
+	writeLine(bw, "METHOD:PUBLISH")
+	for _, e := range events {
+		writeLine(bw, "BEGIN:VEVENT")
+		writeLine(bw, "UID:"+escapeText(e.UID))
+		writeLine(bw, "DTSTAMP:"+stamp)
+		writeLine(bw, "DTSTART;VALUE=DATE:"+e.Date.Format("20060102"))
+		writeLine(bw, "DTEND;VALUE=DATE:"+e.Date.AddDate(0, 0, 1).Format("20060102"))
+		writeLine(bw, "SUMMARY:"+escapeText(e.Summary))
+		if e.Description != "" {
+			writeLine(bw, "DESCRIPTION:"+escapeText(e.Description))
+		}
+		if e.URL != "" {
+			writeLine(bw, "URL:"+e.URL)
+		}
+		writeLine(bw, "TRANSP:TRANSPARENT")
+		writeLine(bw, "END:VEVENT")
+	}
+	writeLine(bw, "END:VCALENDAR")
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write calendar: %w", err)
+	}
+	return nil
+}
+
+// writeLine writes a content line terminated by CRLF, folding it into continuation
+// lines of at most 75 octets without splitting UTF-8 characters
+func writeLine(w *bufio.Writer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8Start(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts toward the limit
+		limit = maxLineOctets - 1
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
+
+// utf8Start reports whether b starts a UTF-8 character rather than continuing one
+func utf8Start(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// escapeText escapes a TEXT property value
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
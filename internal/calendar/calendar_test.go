@@ -0,0 +1,117 @@
+package calendar
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+func TestDeliveryUID_IsStable(t *testing.T) {
+	order := models.Delivery{Source: models.DeliverySourceOrder, OrderID: "111-2222222-3333333", ExpectedDate: "2026-03-05"}
+	moved := order
+	moved.ExpectedDate = "2026-03-07"
+	moved.Status = models.OrderStatusDelayed
+
+	if DeliveryUID(order) != DeliveryUID(moved) {
+		t.Error("Expected the UID to stay the same when the delivery changes")
+	}
+	if DeliveryUID(order) != "order-111-2222222-3333333@amazon-cli" {
+		t.Errorf("Unexpected order UID: %s", DeliveryUID(order))
+	}
+
+	pkg := order
+	pkg.ShipmentID = "DmF7kq3Lz"
+	if DeliveryUID(pkg) != "order-111-2222222-3333333-DmF7kq3Lz@amazon-cli" {
+		t.Errorf("Unexpected package UID: %s", DeliveryUID(pkg))
+	}
+
+	sub := models.Delivery{Source: models.DeliverySourceSubscription, SubscriptionID: "sub001"}
+	if DeliveryUID(sub) != "subscription-sub001@amazon-cli" {
+		t.Errorf("Unexpected subscription UID: %s", DeliveryUID(sub))
+	}
+}
+
+func TestDeliveryEvents(t *testing.T) {
+	deliveries := []models.Delivery{
+		{
+			ExpectedDate: "2026-03-05",
+			Source:       models.DeliverySourceOrder,
+			OrderID:      "111-2222222-3333333",
+			Status:       models.OrderStatusPending,
+			Items:        []models.OrderItem{{Title: "Desk Lamp"}},
+			Tracking:     &models.Tracking{Carrier: "UPS", TrackingNumber: "1Z999AA10123456784", TrackingURL: "https://www.ups.com/track?tracknum=1Z999AA10123456784"},
+		},
+		// Without an expected date there is nothing to put on the calendar
+		{Source: models.DeliverySourceOrder, OrderID: "111-2222222-4444444"},
+		{ExpectedDate: "2026-03-06", Source: models.DeliverySourceSubscription, SubscriptionID: "sub001", Items: []models.OrderItem{{Title: "Coffee Pods"}}},
+	}
+
+	events := DeliveryEvents(deliveries)
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %+v", events)
+	}
+
+	order := events[0]
+	if order.Summary != "Amazon delivery (order 111-2222222-3333333): Desk Lamp" {
+		t.Errorf("Unexpected summary: %s", order.Summary)
+	}
+	if !order.Date.Equal(time.Date(2026, time.March, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected date: %v", order.Date)
+	}
+	if !strings.Contains(order.Description, "Tracking number: 1Z999AA10123456784") || order.URL == "" {
+		t.Errorf("Expected tracking details, got %+v", order)
+	}
+
+	if events[1].Summary != "Subscribe & Save delivery: Coffee Pods" {
+		t.Errorf("Unexpected subscription summary: %s", events[1].Summary)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	events := []Event{{
+		UID:         "order-111-2222222-3333333@amazon-cli",
+		Date:        time.Date(2026, time.March, 5, 0, 0, 0, 0, time.UTC),
+		Summary:     "Amazon delivery: Lamp, bulbs; cable",
+		Description: "Order: 111-2222222-3333333\n" + strings.Repeat("Très long titre ", 10),
+	}}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, events, time.Date(2026, time.March, 2, 9, 30, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	out := buf.String()
+
+	for _, line := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"VERSION:2.0\r\n",
+		"UID:order-111-2222222-3333333@amazon-cli\r\n",
+		"DTSTAMP:20260302T093000Z\r\n",
+		"DTSTART;VALUE=DATE:20260305\r\n",
+		"DTEND;VALUE=DATE:20260306\r\n",
+		`SUMMARY:Amazon delivery: Lamp\, bulbs\; cable` + "\r\n",
+		`DESCRIPTION:Order: 111-2222222-3333333\nTrès long titre`,
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, line) {
+			t.Errorf("Expected %q in calendar:\n%s", line, out)
+		}
+	}
+
+	// Long lines are folded at 75 octets without breaking characters
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("Line longer than 75 octets: %q", line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("Line splits a UTF-8 character: %q", line)
+		}
+	}
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	if !strings.Contains(unfolded, strings.Repeat("Très long titre ", 10)) {
+		t.Error("Expected the description to unfold to its original text")
+	}
+}
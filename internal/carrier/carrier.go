@@ -0,0 +1,237 @@
+package carrier
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Normalized carrier codes
+const (
+	UPS    = "ups"
+	USPS   = "usps"
+	FedEx  = "fedex"
+	DHL    = "dhl"
+	Amazon = "amazon"
+)
+
+// trackingURLs are the public tracking page prefixes of each carrier, followed by the tracking number
+var trackingURLs = map[string]string{
+	UPS:    "https://www.ups.com/track?tracknum=",
+	USPS:   "https://tools.usps.com/go/TrackConfirmAction?tLabels=",
+	FedEx:  "https://www.fedex.com/fedextrack/?trknbr=",
+	DHL:    "https://www.dhl.com/global-en/home/tracking.html?tracking-id=",
+	Amazon: "https://track.amazon.com/tracking/",
+}
+
+// carrierNames maps lowercase words of carrier display names to codes; the first
+// match wins
+var carrierNames = []struct {
+	fragment string
+	code     string
+}{
+	{"united parcel service", UPS},
+	{"postal service", USPS},
+	{"fedex", FedEx},
+	{"federal express", FedEx},
+	{"dhl", DHL},
+	{"usps", USPS},
+	{"ups", UPS},
+	{"amazon", Amazon},
+	{"amzl", Amazon},
+}
+
+var (
+	upsRegex       = regexp.MustCompile(`^1Z[0-9A-Z]{16}$`)
+	amazonRegex    = regexp.MustCompile(`^TBA[0-9]{12}$`)
+	uspsS10Regex   = regexp.MustCompile(`^[A-Z]{2}[0-9]{9}US$`)
+	dhlParcelRegex = regexp.MustCompile(`^(JJD[0-9]{18}|JVGL[0-9]{16})$`)
+	digitsRegex    = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// Normalize uppercases a tracking number and strips spaces and dashes
+func Normalize(number string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '\t':
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(number)))
+}
+
+// Detect returns the code of the carrier whose format and check digit a tracking
+// number matches, or "" if it matches none
+func Detect(number string) string {
+	number = Normalize(number)
+	for _, code := range []string{Amazon, UPS, USPS, FedEx, DHL} {
+		if Valid(code, number) {
+			return code
+		}
+	}
+	return ""
+}
+
+// Valid reports whether a tracking number is well-formed for the carrier, including
+// its check digit where the format has one
+func Valid(code, number string) bool {
+	number = Normalize(number)
+	switch code {
+	case UPS:
+		return upsRegex.MatchString(number) && upsCheckDigit(number[2:17]) == number[17]
+	case USPS:
+		if uspsS10Regex.MatchString(number) {
+			return s10CheckDigit(number[2:10]) == number[10]
+		}
+		return digitsRegex.MatchString(number) && (len(number) == 20 || len(number) == 22) &&
+			mod10CheckDigit(number[:len(number)-1]) == number[len(number)-1]
+	case FedEx:
+		if !digitsRegex.MatchString(number) {
+			return false
+		}
+		switch len(number) {
+		case 12:
+			return fedexExpressCheckDigit(number[:11]) == number[11]
+		case 15:
+			return mod10CheckDigit(number[:14]) == number[14]
+		}
+		return false
+	case DHL:
+		if dhlParcelRegex.MatchString(number) {
+			return true
+		}
+		return digitsRegex.MatchString(number) && len(number) == 10 && dhlCheckDigit(number[:9]) == number[9]
+	case Amazon:
+		return amazonRegex.MatchString(number)
+	}
+	return false
+}
+
+// FromName returns the code of the carrier a display name such as "Amazon Logistics"
+// or "United Parcel Service" refers to, or "" if it isn't recognised
+func FromName(name string) string {
+	name = strings.ToLower(name)
+	for _, n := range carrierNames {
+		if containsWord(name, n.fragment) {
+			return n.code
+		}
+	}
+	return ""
+}
+
+// TrackingURL returns the public tracking page of a package, or "" for an unknown
+// carrier or an empty tracking number
+func TrackingURL(code, number string) string {
+	base, ok := trackingURLs[code]
+	number = Normalize(number)
+	if !ok || number == "" {
+		return ""
+	}
+	return base + url.QueryEscape(number)
+}
+
+// Identify classifies a package by its carrier display name and tracking number and
+// returns the carrier code and tracking URL. A tracking number valid for the named
+// carrier confirms it; otherwise a recognised tracking number takes precedence over
+// the name, which is used as a last resort.
+func Identify(name, number string) (string, string) {
+	code := FromName(name)
+	if number == "" || (code != "" && Valid(code, number)) {
+		return code, TrackingURL(code, number)
+	}
+	if detected := Detect(number); detected != "" {
+		code = detected
+	}
+	return code, TrackingURL(code, number)
+}
+
+// upsCheckDigit computes the UPS check digit of the 15 characters after "1Z".
+// Letters count as (position in the alphabet + 2) mod 10 and every second
+// character is doubled.
+func upsCheckDigit(s string) byte {
+	sum := 0
+	for i := 0; i < len(s); i++ {
+		value := int(s[i] - '0')
+		if s[i] >= 'A' && s[i] <= 'Z' {
+			value = (int(s[i]-'A') + 2) % 10
+		}
+		if i%2 == 1 {
+			value *= 2
+		}
+		sum += value
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// mod10CheckDigit computes the check digit used by USPS and FedEx Ground barcodes:
+// digits are weighted 3 and 1 alternately from the right
+func mod10CheckDigit(digits string) byte {
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		value := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 0 {
+			value *= 3
+		}
+		sum += value
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// s10CheckDigit computes the check digit of a UPU S10 international item number
+func s10CheckDigit(digits string) byte {
+	weights := []int{8, 6, 4, 2, 3, 5, 9, 7}
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		sum += int(digits[i]-'0') * weights[i]
+	}
+	check := 11 - sum%11
+	switch check {
+	case 10:
+		check = 0
+	case 11:
+		check = 5
+	}
+	return byte('0' + check)
+}
+
+// fedexExpressCheckDigit computes the FedEx Express check digit: digits are weighted
+// 1, 3 and 7 repeating from the right and the sum is taken mod 11, then mod 10
+func fedexExpressCheckDigit(digits string) byte {
+	weights := []int{1, 3, 7}
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		sum += int(digits[len(digits)-1-i]-'0') * weights[i%3]
+	}
+	return byte('0' + sum%11%10)
+}
+
+// dhlCheckDigit computes the DHL Express waybill check digit: the number mod 7
+func dhlCheckDigit(digits string) byte {
+	remainder := 0
+	for i := 0; i < len(digits); i++ {
+		remainder = (remainder*10 + int(digits[i]-'0')) % 7
+	}
+	return byte('0' + remainder)
+}
+
+// containsWord reports whether fragment occurs in s without letters on either side,
+// so "ups" matches "UPS Ground" but not "groups"
+func containsWord(s, fragment string) bool {
+	for start := 0; ; {
+		i := strings.Index(s[start:], fragment)
+		if i < 0 {
+			return false
+		}
+		i += start
+		end := i + len(fragment)
+		if (i == 0 || !isLetter(s[i-1])) && (end == len(s) || !isLetter(s[end])) {
+			return true
+		}
+		start = i + 1
+	}
+}
+
+// isLetter reports whether b is an ASCII letter
+func isLetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
@@ -0,0 +1,111 @@
+package carrier
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		number   string
+		expected string
+	}{
+		{"1Z999AA10123456784", UPS},
+		{"1z 999 aa1 0123 4567 84", UPS},
+		{"1Z999AA10123456785", ""},
+		{"9400111899223344556677", USPS},
+		{"03071020000012345671", USPS},
+		{"RA473124829US", USPS},
+		{"RA473124828US", ""},
+		{"987654321010", FedEx},
+		{"987654321011", ""},
+		{"961212345678901", FedEx},
+		{"1234567891", DHL},
+		{"1234567892", ""},
+		{"JJD000390007827193478", DHL},
+		{"TBA123456789000", Amazon},
+		{"TBA12345", ""},
+		{"", ""},
+		{"not-a-number", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Detect(tt.number); got != tt.expected {
+			t.Errorf("Detect(%q) = %q, expected %q", tt.number, got, tt.expected)
+		}
+	}
+}
+
+func TestValid_ChecksTheNamedCarrierOnly(t *testing.T) {
+	if !Valid(UPS, "1Z999AA10123456784") {
+		t.Error("Expected a valid UPS number")
+	}
+	if Valid(FedEx, "1Z999AA10123456784") {
+		t.Error("Expected a UPS number to be invalid for FedEx")
+	}
+	if Valid("ontrac", "1Z999AA10123456784") {
+		t.Error("Expected unknown carriers to validate nothing")
+	}
+}
+
+func TestFromName(t *testing.T) {
+	tests := map[string]string{
+		"UPS":                           UPS,
+		"UPS Ground":                    UPS,
+		"United Parcel Service":         UPS,
+		"USPS":                          USPS,
+		"U.S. Postal Service":           USPS,
+		"FedEx Home Delivery":           FedEx,
+		"DHL eCommerce":                 DHL,
+		"Amazon Logistics":              Amazon,
+		"AMZL_US":                       Amazon,
+		"Shipping groups international": "",
+		"OnTrac":                        "",
+		"":                              "",
+	}
+
+	for name, expected := range tests {
+		if got := FromName(name); got != expected {
+			t.Errorf("FromName(%q) = %q, expected %q", name, got, expected)
+		}
+	}
+}
+
+func TestTrackingURL(t *testing.T) {
+	if got := TrackingURL(UPS, "1z999aa10123456784"); got != "https://www.ups.com/track?tracknum=1Z999AA10123456784" {
+		t.Errorf("Unexpected UPS URL: %s", got)
+	}
+	if got := TrackingURL(Amazon, "TBA123456789000"); got != "https://track.amazon.com/tracking/TBA123456789000" {
+		t.Errorf("Unexpected Amazon URL: %s", got)
+	}
+	if got := TrackingURL("", "1Z999AA10123456784"); got != "" {
+		t.Errorf("Expected no URL for an unknown carrier, got %s", got)
+	}
+	if got := TrackingURL(USPS, ""); got != "" {
+		t.Errorf("Expected no URL without a tracking number, got %s", got)
+	}
+}
+
+func TestIdentify(t *testing.T) {
+	tests := []struct {
+		name   string
+		number string
+		code   string
+		hasURL bool
+	}{
+		// The name and number agree
+		{"UPS", "1Z999AA10123456784", UPS, true},
+		// A recognised number overrides a mislabeled name
+		{"Amazon Logistics", "9400111899223344556677", USPS, true},
+		// An unrecognised number falls back to the name
+		{"FedEx", "ABC123", FedEx, true},
+		// A name without a number has no URL
+		{"USPS", "", USPS, false},
+		// Neither is recognised
+		{"OnTrac", "C11234567890123", "", false},
+	}
+
+	for _, tt := range tests {
+		code, url := Identify(tt.name, tt.number)
+		if code != tt.code || (url != "") != tt.hasURL {
+			t.Errorf("Identify(%q, %q) = %q, %q", tt.name, tt.number, code, url)
+		}
+	}
+}
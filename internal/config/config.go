@@ -5,7 +5,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"time"
+
+	"github.com/zkwentz/amazon-cli/internal/accounting"
+	"github.com/zkwentz/amazon-cli/internal/hooks"
 )
 
 // AuthConfig holds authentication configuration
@@ -18,6 +23,11 @@ type AuthConfig struct {
 // Config represents the complete application configuration
 type Config struct {
 	Auth AuthConfig `json:"auth"`
+	// Accounting holds the account mappings used by orders export
+	Accounting *accounting.Config `json:"accounting,omitempty"`
+	// Hooks are run for changes detected by orders sync and orders track --watch;
+	// tracking_event hooks only fire from the watchers
+	Hooks []hooks.Hook `json:"hooks,omitempty"`
 }
 
 // DefaultConfigPath returns the default configuration file path
@@ -65,6 +75,8 @@ func LoadConfig(path string) (*Config, error) {
 			RefreshToken string `json:"refresh_token"`
 			ExpiresAt    string `json:"expires_at"`
 		} `json:"auth"`
+		Accounting *accounting.Config `json:"accounting"`
+		Hooks      []hooks.Hook       `json:"hooks"`
 	}
 
 	if err := json.Unmarshal(data, &raw); err != nil {
@@ -77,6 +89,8 @@ func LoadConfig(path string) (*Config, error) {
 			AccessToken:  raw.Auth.AccessToken,
 			RefreshToken: raw.Auth.RefreshToken,
 		},
+		Accounting: raw.Accounting,
+		Hooks:      raw.Hooks,
 	}
 
 	// Parse time if not empty
@@ -119,8 +133,30 @@ func SaveConfig(config *Config, path string) error {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
+	// Keep the settings Config does not model, such as the network and
+	// rate_limiting sections read through viper, so rewriting the file
+	// (e.g. on auth logout) never drops them
+	settings := map[string]json.RawMessage{}
+	if existing, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(existing, &settings)
+	}
+
+	// The sections Config models are replaced outright, so one that is now empty
+	// (and omitted when marshaled) is removed rather than kept from the old file
+	for _, key := range configKeys() {
+		delete(settings, key)
+	}
+
+	fields, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if err := json.Unmarshal(fields, &settings); err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
 	// Marshal to JSON with indentation for readability
-	data, err := json.MarshalIndent(config, "", "  ")
+	data, err := json.MarshalIndent(settings, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
@@ -133,6 +169,19 @@ func SaveConfig(config *Config, path string) error {
 	return nil
 }
 
+// configKeys returns the top-level JSON keys of the sections Config models
+func configKeys() []string {
+	t := reflect.TypeOf(Config{})
+	keys := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			keys = append(keys, name)
+		}
+	}
+	return keys
+}
+
 // IsAuthenticated checks if the user has valid authentication
 func (c *Config) IsAuthenticated() bool {
 	if c == nil || c.Auth.AccessToken == "" {
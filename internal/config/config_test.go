@@ -410,3 +410,192 @@ func TestRoundTrip(t *testing.T) {
 			originalConfig.Auth.ExpiresAt, loadedConfig.Auth.ExpiresAt)
 	}
 }
+
+func TestSaveConfig_PreservesUnmodeledSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "config.json")
+
+	existing := `{
+  "auth": {"access_token": "token", "refresh_token": "refresh", "expires_at": "2030-01-01T00:00:00Z"},
+  "network": {"proxy_url": "socks5://proxy.corp.example:1080", "timeout": "45s"},
+  "rate_limiting": {"min_delay_ms": 500}
+}`
+	if err := os.WriteFile(path, []byte(existing), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	cfg.ClearAuth()
+	if err := SaveConfig(cfg, path); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+	var saved struct {
+		Auth    AuthConfig `json:"auth"`
+		Network struct {
+			ProxyURL string `json:"proxy_url"`
+			Timeout  string `json:"timeout"`
+		} `json:"network"`
+		RateLimiting struct {
+			MinDelayMS int `json:"min_delay_ms"`
+		} `json:"rate_limiting"`
+	}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("failed to parse saved config: %v", err)
+	}
+
+	// Settings read through viper must survive a save, e.g. after auth logout rewrites the file
+	if saved.Network.ProxyURL != "socks5://proxy.corp.example:1080" || saved.Network.Timeout != "45s" {
+		t.Errorf("network settings were not preserved: %+v", saved.Network)
+	}
+	if saved.RateLimiting.MinDelayMS != 500 {
+		t.Errorf("rate limiting settings were not preserved: %+v", saved.RateLimiting)
+	}
+	if saved.Auth.AccessToken != "" {
+		t.Errorf("expected cleared access token to be saved, got %q", saved.Auth.AccessToken)
+	}
+}
+
+func TestSaveConfig_PreservesMarketplaceAndProfile(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "config.json")
+
+	// The marketplace and profile are read through viper; saving auth changes must keep them
+	if err := os.WriteFile(path, []byte(`{"marketplace": "de", "profile": "work"}`), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if err := SaveConfig(&Config{}, path); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+	var saved struct {
+		Marketplace string `json:"marketplace"`
+		Profile     string `json:"profile"`
+	}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("failed to parse saved config: %v", err)
+	}
+	if saved.Marketplace != "de" {
+		t.Errorf("Expected marketplace de, got %q", saved.Marketplace)
+	}
+	if saved.Profile != "work" {
+		t.Errorf("Expected profile work, got %q", saved.Profile)
+	}
+}
+
+func TestSaveConfig_RemovesClearedSections(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "config.json")
+
+	existing := `{
+  "accounting": {"payment_account": "Liabilities:Visa"},
+  "hooks": [{"command": "notify-send delivered"}],
+  "marketplace": "de"
+}`
+	if err := os.WriteFile(path, []byte(existing), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	cfg.Accounting = nil
+	cfg.Hooks = nil
+	if err := SaveConfig(cfg, path); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+	var saved map[string]json.RawMessage
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("failed to parse saved config: %v", err)
+	}
+
+	// Cleared sections are removed; settings Config does not model are kept
+	for _, key := range []string{"accounting", "hooks"} {
+		if _, exists := saved[key]; exists {
+			t.Errorf("Expected cleared %s section to be removed, got %s", key, saved[key])
+		}
+	}
+	if string(saved["marketplace"]) != `"de"` {
+		t.Errorf("Expected marketplace to be preserved, got %s", saved["marketplace"])
+	}
+	if _, exists := saved["auth"]; !exists {
+		t.Error("Expected auth section to be saved")
+	}
+}
+
+func TestLoadConfig_Accounting(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "config.json")
+
+	data := `{
+  "accounting": {
+    "payment_account": "Liabilities:Visa",
+    "rules": [{"seller": "acme", "account": "Expenses:Household"}]
+  }
+}`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	loadedConfig, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	if loadedConfig.Accounting == nil {
+		t.Fatal("Expected accounting config to be loaded")
+	}
+	if loadedConfig.Accounting.PaymentAccount != "Liabilities:Visa" {
+		t.Errorf("Expected payment account Liabilities:Visa, got %q", loadedConfig.Accounting.PaymentAccount)
+	}
+	if len(loadedConfig.Accounting.Rules) != 1 || loadedConfig.Accounting.Rules[0].Account != "Expenses:Household" {
+		t.Errorf("Expected one account rule, got %+v", loadedConfig.Accounting.Rules)
+	}
+}
+
+func TestLoadConfig_Hooks(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "config.json")
+
+	data := `{
+  "hooks": [
+    {"events": ["status_changed"], "command": "notify-send shipped"},
+    {"url": "https://hooks.example.com/amazon", "retries": 5}
+  ]
+}`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	loadedConfig, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	if len(loadedConfig.Hooks) != 2 {
+		t.Fatalf("Expected two hooks, got %+v", loadedConfig.Hooks)
+	}
+	if loadedConfig.Hooks[0].Command != "notify-send shipped" || len(loadedConfig.Hooks[0].Events) != 1 {
+		t.Errorf("Unexpected command hook: %+v", loadedConfig.Hooks[0])
+	}
+	if loadedConfig.Hooks[1].URL != "https://hooks.example.com/amazon" || loadedConfig.Hooks[1].Retries == nil || *loadedConfig.Hooks[1].Retries != 5 {
+		t.Errorf("Unexpected webhook: %+v", loadedConfig.Hooks[1])
+	}
+}
@@ -0,0 +1,237 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+// Defaults used when a hook leaves them unset
+const (
+	DefaultRetries = 3
+	DefaultTimeout = 10 * time.Second
+)
+
+// defaultBackoff is the delay before the first webhook retry; it doubles on each retry
+const defaultBackoff = time.Second
+
+// Hook runs a shell command or calls a webhook when matching events occur.
+// The event is passed as JSON on the command's stdin or as the webhook's POST body.
+type Hook struct {
+	// Events limits the hook to these event types; empty means all events
+	Events []string `json:"events,omitempty"`
+	// Command is run with "sh -c"
+	Command string `json:"command,omitempty"`
+	// URL receives the event as a JSON POST
+	URL string `json:"url,omitempty"`
+	// Headers are added to webhook requests, e.g. for authentication
+	Headers map[string]string `json:"headers,omitempty"`
+	// Retries is how many times a failed webhook call is retried (default 3)
+	Retries *int `json:"retries,omitempty"`
+	// Timeout bounds each command run or webhook call as a Go duration (default 10s)
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// EventTypes returns the event types hooks can subscribe to
+func EventTypes() []string {
+	return []string{models.HookEventStatusChanged, models.HookEventTrackingEvent, models.HookEventDeliveryDateChanged}
+}
+
+// hook is a validated Hook
+type hook struct {
+	Hook
+	retries int
+	timeout time.Duration
+}
+
+// Dispatcher sends events to the configured hooks
+type Dispatcher struct {
+	hooks      []hook
+	httpClient *http.Client
+	backoff    time.Duration
+}
+
+// NewDispatcher validates the hooks and returns a dispatcher for them
+func NewDispatcher(hooks []Hook) (*Dispatcher, error) {
+	d := &Dispatcher{
+		httpClient: &http.Client{},
+		backoff:    defaultBackoff,
+	}
+
+	for i, h := range hooks {
+		if (h.Command == "") == (h.URL == "") {
+			return nil, fmt.Errorf("hook %d: set exactly one of command or url", i+1)
+		}
+		if h.URL != "" && !strings.HasPrefix(h.URL, "http://") && !strings.HasPrefix(h.URL, "https://") {
+			return nil, fmt.Errorf("hook %d: url must start with http:// or https://", i+1)
+		}
+		for _, event := range h.Events {
+			if !validEventType(event) {
+				return nil, fmt.Errorf("hook %d: unknown event %q: use %s", i+1, event, strings.Join(EventTypes(), ", "))
+			}
+		}
+
+		validated := hook{Hook: h, retries: DefaultRetries, timeout: DefaultTimeout}
+		if h.Retries != nil {
+			if *h.Retries < 0 {
+				return nil, fmt.Errorf("hook %d: retries must not be negative", i+1)
+			}
+			validated.retries = *h.Retries
+		}
+		if h.Timeout != "" {
+			timeout, err := time.ParseDuration(h.Timeout)
+			if err != nil || timeout <= 0 {
+				return nil, fmt.Errorf("hook %d: invalid timeout %q", i+1, h.Timeout)
+			}
+			validated.timeout = timeout
+		}
+		d.hooks = append(d.hooks, validated)
+	}
+
+	return d, nil
+}
+
+// Len returns the number of configured hooks
+func (d *Dispatcher) Len() int {
+	return len(d.hooks)
+}
+
+// Dispatch sends an event to every hook subscribed to its type. All hooks are run
+// even if some fail; the failures are returned together.
+func (d *Dispatcher) Dispatch(ctx context.Context, event models.HookEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", event.Type, err)
+	}
+
+	var errs []error
+	for i, h := range d.hooks {
+		if !h.subscribed(event.Type) {
+			continue
+		}
+
+		if h.Command != "" {
+			err = d.runCommand(ctx, h, event, payload)
+		} else {
+			err = d.callWebhook(ctx, h, payload)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("hook %d (%s): %w", i+1, event.Type, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// runCommand runs a command hook with the event on stdin and its type and order in
+// the environment
+func (d *Dispatcher) runCommand(ctx context.Context, h hook, event models.HookEvent, payload []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", h.Command)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Env = append(os.Environ(),
+		"AMAZON_CLI_EVENT="+event.Type,
+		"AMAZON_CLI_ORDER_ID="+event.OrderID,
+		"AMAZON_CLI_SHIPMENT_ID="+event.ShipmentID,
+	)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("command failed: %w: %s", err, msg)
+		}
+		return fmt.Errorf("command failed: %w", err)
+	}
+	return nil
+}
+
+// callWebhook POSTs the event to a webhook, retrying network errors, 429 and 5xx
+// responses with exponential backoff
+func (d *Dispatcher) callWebhook(ctx context.Context, h hook, payload []byte) error {
+	backoff := d.backoff
+	var lastErr error
+
+	for attempt := 0; attempt <= h.retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		retry, err := d.postWebhook(ctx, h, payload)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+	}
+
+	return lastErr
+}
+
+// postWebhook makes one webhook call and reports whether a failure is worth retrying
+func (d *Dispatcher) postWebhook(ctx context.Context, h hook, payload []byte) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(payload))
+	if err != nil {
+		return false, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "amazon-cli")
+	for name, value := range h.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("webhook request failed: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+}
+
+// subscribed reports whether the hook receives events of the given type
+func (h hook) subscribed(eventType string) bool {
+	if len(h.Events) == 0 {
+		return true
+	}
+	for _, e := range h.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// validEventType reports whether an event type is known
+func validEventType(eventType string) bool {
+	for _, e := range EventTypes() {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
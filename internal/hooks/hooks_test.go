@@ -0,0 +1,166 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+func statusEvent() models.HookEvent {
+	return models.HookEvent{
+		Type:     models.HookEventStatusChanged,
+		Source:   models.HookSourceSync,
+		OrderID:  "111-2222222-3333333",
+		Previous: models.OrderStatusPending,
+		Current:  models.OrderStatusDelivered,
+		Order:    &models.Order{OrderID: "111-2222222-3333333", Status: models.OrderStatusDelivered},
+		Time:     time.Date(2026, time.March, 5, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func TestNewDispatcher_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		hook Hook
+	}{
+		{"neither command nor url", Hook{}},
+		{"both command and url", Hook{Command: "true", URL: "http://localhost"}},
+		{"unsupported url scheme", Hook{URL: "ftp://example.com"}},
+		{"unknown event", Hook{Command: "true", Events: []string{"shipped"}}},
+		{"negative retries", Hook{URL: "http://localhost", Retries: intPtr(-1)}},
+		{"invalid timeout", Hook{Command: "true", Timeout: "soon"}},
+	}
+
+	for _, tt := range tests {
+		if _, err := NewDispatcher([]Hook{tt.hook}); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+
+	d, err := NewDispatcher([]Hook{{Command: "true", Events: EventTypes()}, {URL: "https://example.com/hook"}})
+	if err != nil || d.Len() != 2 {
+		t.Errorf("Expected two valid hooks, got %v", err)
+	}
+}
+
+func TestDispatch_WebhookRetriesUntilSuccess(t *testing.T) {
+	requests := 0
+	var received models.HookEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" || r.Header.Get("X-Token") != "secret" {
+			t.Errorf("Unexpected request: %s %v", r.Method, r.Header)
+		}
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Errorf("Invalid payload: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	d, err := NewDispatcher([]Hook{{URL: server.URL, Headers: map[string]string{"X-Token": "secret"}}})
+	if err != nil {
+		t.Fatalf("NewDispatcher() error = %v", err)
+	}
+	d.backoff = time.Millisecond
+
+	if err := d.Dispatch(context.Background(), statusEvent()); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("Expected 2 retries before success, got %d requests", requests)
+	}
+	if received.Type != models.HookEventStatusChanged || received.Current != models.OrderStatusDelivered || received.Order == nil {
+		t.Errorf("Unexpected payload: %+v", received)
+	}
+}
+
+func TestDispatch_WebhookGivesUp(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		expected int
+	}{
+		// Server errors are retried until the retries run out
+		{"server error", http.StatusInternalServerError, 3},
+		// Client errors won't succeed on retry
+		{"client error", http.StatusBadRequest, 1},
+	}
+
+	for _, tt := range tests {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.WriteHeader(tt.status)
+		}))
+
+		d, _ := NewDispatcher([]Hook{{URL: server.URL, Retries: intPtr(2)}})
+		d.backoff = time.Millisecond
+		err := d.Dispatch(context.Background(), statusEvent())
+		server.Close()
+
+		if err == nil || !strings.Contains(err.Error(), "webhook returned status") {
+			t.Errorf("%s: expected a webhook error, got %v", tt.name, err)
+		}
+		if requests != tt.expected {
+			t.Errorf("%s: expected %d requests, got %d", tt.name, tt.expected, requests)
+		}
+	}
+}
+
+func TestDispatch_CommandReceivesEvent(t *testing.T) {
+	dir := t.TempDir()
+	payload := filepath.Join(dir, "event.json")
+	env := filepath.Join(dir, "env")
+
+	d, err := NewDispatcher([]Hook{
+		{Command: "cat > " + payload + " && echo \"$AMAZON_CLI_EVENT $AMAZON_CLI_ORDER_ID\" > " + env},
+		// Not subscribed to status changes, so it must not run
+		{Command: "exit 1", Events: []string{models.HookEventTrackingEvent}},
+	})
+	if err != nil {
+		t.Fatalf("NewDispatcher() error = %v", err)
+	}
+
+	if err := d.Dispatch(context.Background(), statusEvent()); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+
+	data, err := os.ReadFile(payload)
+	if err != nil {
+		t.Fatalf("Expected the command to receive the event: %v", err)
+	}
+	var received models.HookEvent
+	if err := json.Unmarshal(data, &received); err != nil || received.OrderID != "111-2222222-3333333" {
+		t.Errorf("Unexpected payload %s (%v)", data, err)
+	}
+	if vars, _ := os.ReadFile(env); strings.TrimSpace(string(vars)) != "status_changed 111-2222222-3333333" {
+		t.Errorf("Unexpected environment: %q", vars)
+	}
+}
+
+func TestDispatch_CollectsCommandFailures(t *testing.T) {
+	d, _ := NewDispatcher([]Hook{{Command: "echo boom >&2; exit 3"}, {Command: "true"}})
+
+	err := d.Dispatch(context.Background(), statusEvent())
+	if err == nil || !strings.Contains(err.Error(), "hook 1") || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("Expected the first hook's failure with its output, got %v", err)
+	}
+}
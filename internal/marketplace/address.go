@@ -0,0 +1,77 @@
+package marketplace
+
+import (
+	"regexp"
+	"strings"
+)
+
+// cityLineFormat describes how a marketplace writes the city line of a postal
+// address and which submatches hold the city, state and postal code (0 if absent)
+type cityLineFormat struct {
+	regex             *regexp.Regexp
+	city, state, code int
+}
+
+// City line formats
+var (
+	// "Seattle, WA 98101" or "Toronto, ON M5V 2T6"
+	cityStatePostcode = cityLineFormat{regexp.MustCompile(`^(.+?),\s*([\p{L} .]+?)\s+([0-9A-Za-z -]{3,10})$`), 1, 2, 3}
+	// "London SW1A 1AA" or "London, SW1A 1AA"
+	cityPostcode = cityLineFormat{regexp.MustCompile(`^(.+?),?\s+([A-Za-z]{1,2}\d[A-Za-z\d]?\s*\d[A-Za-z]{2})$`), 1, 0, 2}
+	// "10115 Berlin", "75001 Paris" or "〒100-0001 東京都千代田区"
+	postcodeCity = cityLineFormat{regexp.MustCompile(`^(?:〒\s*)?(\d{3}-\d{4}|\d{4,5})\s+(.+)$`), 2, 0, 1}
+)
+
+// Localized "Visa ending in 1234" phrases, in lower case
+var (
+	englishPaymentPhrases  = []string{"ending in"}
+	germanPaymentPhrases   = []string{"endet auf", "endend auf", "mit endziffern"}
+	frenchPaymentPhrases   = []string{"se terminant par", "finissant par", "terminant par"}
+	italianPaymentPhrases  = []string{"che termina con", "termina con", "terminante in"}
+	spanishPaymentPhrases  = []string{"que termina en", "terminada en", "terminado en"}
+	japanesePaymentPhrases = []string{"末尾", "下4桁"}
+)
+
+// ParseCityLine splits the city line of an address in the marketplace's format
+// (e.g. "Seattle, WA 98101", "London SW1A 1AA" or "10115 Berlin"). It reports
+// false if the line doesn't match, in which case the whole line is the city.
+func (m *Marketplace) ParseCityLine(line string) (city, state, postcode string, ok bool) {
+	line = strings.TrimSpace(line)
+	format := m.cityLine
+	if format.regex == nil {
+		format = cityStatePostcode
+	}
+
+	match := format.regex.FindStringSubmatch(line)
+	if match == nil {
+		return line, "", "", false
+	}
+	city = strings.TrimSpace(match[format.city])
+	if format.state > 0 {
+		state = strings.TrimSpace(match[format.state])
+	}
+	postcode = strings.TrimSpace(match[format.code])
+	return city, state, postcode, true
+}
+
+// ParsePaymentCard splits a payment summary such as "Visa ending in 1234" or
+// "Visa endet auf 1234" into the card type and its last four digits. English
+// phrases, which Amazon also shows on some localized pages, are always recognized.
+func (m *Marketplace) ParsePaymentCard(text string) (cardType, last4 string, ok bool) {
+	match := m.paymentRegex.FindStringSubmatch(text)
+	if match == nil {
+		return "", "", false
+	}
+	return strings.TrimSpace(match[1]), match[2], true
+}
+
+// compilePaymentRegex builds the regular expression matching a card type, one of the
+// payment phrases and the last four digits, allowing for punctuation and the
+// Japanese particle in "末尾が1234"
+func compilePaymentRegex(phrases []string) *regexp.Regexp {
+	quoted := make([]string, 0, len(phrases)+len(englishPaymentPhrases))
+	for _, phrase := range append(append([]string{}, phrases...), englishPaymentPhrases...) {
+		quoted = append(quoted, regexp.QuoteMeta(phrase))
+	}
+	return regexp.MustCompile(`(?i)^(.*?)\s*(?:` + strings.Join(quoted, "|") + `)[\s:：が]*(\d{4})`)
+}
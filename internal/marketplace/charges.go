@@ -0,0 +1,111 @@
+package marketplace
+
+import "strings"
+
+// Kinds of order summary rows returned by ChargeKind
+const (
+	ChargeSubtotal  = "subtotal"
+	ChargeShipping  = "shipping"
+	ChargePromotion = "promotion"
+	ChargeGiftCard  = "gift_card"
+	ChargeBeforeTax = "before_tax"
+	ChargeTax       = "tax"
+	ChargeRefund    = "refund"
+	ChargeTotal     = "total"
+)
+
+// chargeRule maps phrases from the labels of an order summary row to its kind.
+// Rules are checked in order, so more specific phrases (e.g. "free shipping" or
+// "total before tax") must come before the general ones they contain.
+type chargeRule struct {
+	phrases []string
+	kind    string
+}
+
+// Localized order summary labels, in lower case
+var (
+	englishChargeRules = []chargeRule{
+		{[]string{"gift card", "gift certificate"}, ChargeGiftCard},
+		{[]string{"free shipping", "free delivery", "promotion", "discount", "coupon", "saving"}, ChargePromotion},
+		{[]string{"subtotal"}, ChargeSubtotal},
+		{[]string{"refund"}, ChargeRefund},
+		{[]string{"shipping", "delivery", "postage"}, ChargeShipping},
+		{[]string{"before tax", "before vat", "excluding vat", "excl. vat"}, ChargeBeforeTax},
+		{[]string{"tax", "vat", "gst"}, ChargeTax},
+		{[]string{"total"}, ChargeTotal},
+	}
+	germanChargeRules = []chargeRule{
+		{[]string{"geschenkgutschein", "geschenkkarte"}, ChargeGiftCard},
+		{[]string{"kostenloser versand", "kostenlose lieferung", "rabatt", "aktion", "gutschein", "coupon"}, ChargePromotion},
+		{[]string{"zwischensumme"}, ChargeSubtotal},
+		{[]string{"erstattung", "erstattet"}, ChargeRefund},
+		{[]string{"versand", "lieferung"}, ChargeShipping},
+		{[]string{"ohne mwst", "vor mwst", "netto"}, ChargeBeforeTax},
+		{[]string{"mwst", "umsatzsteuer"}, ChargeTax},
+		{[]string{"summe", "gesamt"}, ChargeTotal},
+	}
+	frenchChargeRules = []chargeRule{
+		{[]string{"chèque-cadeau", "chèque cadeau", "carte cadeau"}, ChargeGiftCard},
+		{[]string{"livraison gratuite", "promotion", "réduction", "remise", "coupon"}, ChargePromotion},
+		{[]string{"sous-total", "sous total"}, ChargeSubtotal},
+		{[]string{"rembours"}, ChargeRefund},
+		{[]string{"livraison", "expédition"}, ChargeShipping},
+		{[]string{"hors taxe", "total ht", "montant ht"}, ChargeBeforeTax},
+		{[]string{"tva"}, ChargeTax},
+		{[]string{"total", "montant"}, ChargeTotal},
+	}
+	italianChargeRules = []chargeRule{
+		{[]string{"buono regalo", "carta regalo"}, ChargeGiftCard},
+		{[]string{"spedizione gratuita", "promozione", "sconto", "coupon"}, ChargePromotion},
+		{[]string{"subtotale"}, ChargeSubtotal},
+		{[]string{"rimbors"}, ChargeRefund},
+		{[]string{"spedizione", "consegna"}, ChargeShipping},
+		{[]string{"senza iva", "iva esclusa", "imponibile"}, ChargeBeforeTax},
+		{[]string{"iva"}, ChargeTax},
+		{[]string{"totale"}, ChargeTotal},
+	}
+	spanishChargeRules = []chargeRule{
+		{[]string{"cheque regalo", "tarjeta regalo"}, ChargeGiftCard},
+		{[]string{"envío gratis", "envío gratuito", "promoción", "descuento", "cupón"}, ChargePromotion},
+		{[]string{"subtotal"}, ChargeSubtotal},
+		{[]string{"reembols"}, ChargeRefund},
+		{[]string{"envío", "entrega"}, ChargeShipping},
+		{[]string{"sin iva", "antes de impuestos", "iva excluido"}, ChargeBeforeTax},
+		{[]string{"iva", "impuesto"}, ChargeTax},
+		{[]string{"total"}, ChargeTotal},
+	}
+	// Japanese totals are labeled "(税込)" (tax included), so totals come before tax
+	japaneseChargeRules = []chargeRule{
+		{[]string{"ギフトカード", "ギフト券"}, ChargeGiftCard},
+		{[]string{"配送料無料", "無料配送", "割引", "クーポン", "プロモーション", "ポイント"}, ChargePromotion},
+		{[]string{"小計"}, ChargeSubtotal},
+		{[]string{"返金"}, ChargeRefund},
+		{[]string{"配送料", "手数料"}, ChargeShipping},
+		{[]string{"税抜"}, ChargeBeforeTax},
+		{[]string{"合計", "ご請求額"}, ChargeTotal},
+		{[]string{"消費税", "税"}, ChargeTax},
+	}
+)
+
+// ChargeKind maps the label of an order summary row (e.g. "Shipping & Handling:" or
+// "Verpackung & Versand:") to one of the Charge* kinds, or "" if it is not recognized.
+// The marketplace's own labels are checked before the English ones, which Amazon
+// also shows on some localized pages.
+func (m *Marketplace) ChargeKind(label string) string {
+	label = strings.ToLower(strings.TrimSpace(label))
+	if label == "" {
+		return ""
+	}
+
+	for _, rules := range [][]chargeRule{m.chargeRules, englishChargeRules} {
+		for _, rule := range rules {
+			for _, phrase := range rule.phrases {
+				if strings.Contains(label, phrase) {
+					return rule.kind
+				}
+			}
+		}
+	}
+
+	return ""
+}
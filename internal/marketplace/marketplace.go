@@ -0,0 +1,328 @@
+package marketplace
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultCode is the marketplace used when none is configured
+const DefaultCode = "us"
+
+// Marketplace describes a regional Amazon storefront and its formatting conventions
+type Marketplace struct {
+	// Code is the short identifier used by --marketplace (e.g. "uk")
+	Code string `json:"code"`
+	// Domain is the storefront host (e.g. "www.amazon.co.uk")
+	Domain string `json:"domain"`
+	// Currency is the ISO 4217 currency code (e.g. "GBP")
+	Currency string `json:"currency"`
+	// Locale is the BCP 47 language tag used for Accept-Language (e.g. "en-GB")
+	Locale string `json:"locale"`
+	// DecimalSeparator separates whole and fractional parts of prices
+	DecimalSeparator string `json:"decimal_separator"`
+	// ThousandsSeparator groups digits in prices ("," "." or " ")
+	ThousandsSeparator string `json:"thousands_separator"`
+	// DateLayouts are Go time layouts for dates after month names are translated to English
+	DateLayouts []string `json:"date_layouts"`
+	// OrderIDPattern matches an order ID on this marketplace (unanchored)
+	OrderIDPattern string `json:"order_id_pattern"`
+
+	// months maps lower-case localized month names to English month names
+	months map[string]string
+	// dateNoise lists words removed from dates before parsing (e.g. Spanish "de")
+	dateNoise []string
+	// statusRules are the localized order status phrases; English ones always apply
+	statusRules []statusRule
+	// chargeRules are the localized order summary labels; English ones always apply
+	chargeRules []chargeRule
+	// paymentPhrases are the localized "ending in" phrases of card summaries
+	paymentPhrases []string
+	// cityLine is the format of the city line of addresses (default "City, ST 12345")
+	cityLine cityLineFormat
+
+	orderIDRegex      *regexp.Regexp
+	orderIDExactRegex *regexp.Regexp
+	priceRegex        *regexp.Regexp
+	dateRegex         *regexp.Regexp
+	paymentRegex      *regexp.Regexp
+}
+
+// Common date layouts, applied after month names are translated to English
+var (
+	monthFirstLayouts = []string{"January 2, 2006", "Jan 2, 2006", "January 2 2006", "2 January 2006", "2 Jan 2006"}
+	dayFirstLayouts   = []string{"2 January 2006", "2 Jan 2006", "2. January 2006", "2. Jan 2006", "2 Jan. 2006", "January 2, 2006", "Jan 2, 2006"}
+	japaneseLayouts   = []string{"2006年1月2日", "2006/01/02"}
+)
+
+// Localized month names
+var (
+	germanMonths = map[string]string{
+		"januar": "January", "februar": "February", "märz": "March", "april": "April",
+		"mai": "May", "juni": "June", "juli": "July", "august": "August",
+		"september": "September", "oktober": "October", "november": "November", "dezember": "December",
+	}
+	frenchMonths = map[string]string{
+		"janvier": "January", "février": "February", "mars": "March", "avril": "April",
+		"mai": "May", "juin": "June", "juillet": "July", "août": "August",
+		"septembre": "September", "octobre": "October", "novembre": "November", "décembre": "December",
+	}
+	italianMonths = map[string]string{
+		"gennaio": "January", "febbraio": "February", "marzo": "March", "aprile": "April",
+		"maggio": "May", "giugno": "June", "luglio": "July", "agosto": "August",
+		"settembre": "September", "ottobre": "October", "novembre": "November", "dicembre": "December",
+	}
+	spanishMonths = map[string]string{
+		"enero": "January", "febrero": "February", "marzo": "March", "abril": "April",
+		"mayo": "May", "junio": "June", "julio": "July", "agosto": "August",
+		"septiembre": "September", "octubre": "October", "noviembre": "November", "diciembre": "December",
+	}
+)
+
+// standardOrderID matches order IDs in the XXX-XXXXXXX-XXXXXXX format used by all
+// marketplaces; digital orders start with "D" instead (e.g. D01-1234567-1234567)
+const standardOrderID = `(?:\d{3}|D\d{2})-\d{7}-\d{7}`
+
+// registry holds all supported marketplaces keyed by code
+var registry = map[string]*Marketplace{}
+
+func init() {
+	for _, m := range []*Marketplace{
+		{Code: "us", Domain: "www.amazon.com", Currency: "USD", Locale: "en-US", DecimalSeparator: ".", ThousandsSeparator: ",", DateLayouts: monthFirstLayouts},
+		{Code: "ca", Domain: "www.amazon.ca", Currency: "CAD", Locale: "en-CA", DecimalSeparator: ".", ThousandsSeparator: ",", DateLayouts: monthFirstLayouts},
+		{Code: "mx", Domain: "www.amazon.com.mx", Currency: "MXN", Locale: "es-MX", DecimalSeparator: ".", ThousandsSeparator: ",", DateLayouts: dayFirstLayouts, months: spanishMonths, dateNoise: []string{"de"}, statusRules: spanishStatusRules, chargeRules: spanishChargeRules, paymentPhrases: spanishPaymentPhrases},
+		{Code: "uk", Domain: "www.amazon.co.uk", Currency: "GBP", Locale: "en-GB", DecimalSeparator: ".", ThousandsSeparator: ",", DateLayouts: dayFirstLayouts, cityLine: cityPostcode},
+		{Code: "de", Domain: "www.amazon.de", Currency: "EUR", Locale: "de-DE", DecimalSeparator: ",", ThousandsSeparator: ".", DateLayouts: dayFirstLayouts, months: germanMonths, statusRules: germanStatusRules, chargeRules: germanChargeRules, paymentPhrases: germanPaymentPhrases, cityLine: postcodeCity},
+		{Code: "fr", Domain: "www.amazon.fr", Currency: "EUR", Locale: "fr-FR", DecimalSeparator: ",", ThousandsSeparator: " ", DateLayouts: dayFirstLayouts, months: frenchMonths, dateNoise: []string{"le"}, statusRules: frenchStatusRules, chargeRules: frenchChargeRules, paymentPhrases: frenchPaymentPhrases, cityLine: postcodeCity},
+		{Code: "it", Domain: "www.amazon.it", Currency: "EUR", Locale: "it-IT", DecimalSeparator: ",", ThousandsSeparator: ".", DateLayouts: dayFirstLayouts, months: italianMonths, statusRules: italianStatusRules, chargeRules: italianChargeRules, paymentPhrases: italianPaymentPhrases, cityLine: postcodeCity},
+		{Code: "es", Domain: "www.amazon.es", Currency: "EUR", Locale: "es-ES", DecimalSeparator: ",", ThousandsSeparator: ".", DateLayouts: dayFirstLayouts, months: spanishMonths, dateNoise: []string{"de"}, statusRules: spanishStatusRules, chargeRules: spanishChargeRules, paymentPhrases: spanishPaymentPhrases, cityLine: postcodeCity},
+		{Code: "jp", Domain: "www.amazon.co.jp", Currency: "JPY", Locale: "ja-JP", DecimalSeparator: ".", ThousandsSeparator: ",", DateLayouts: japaneseLayouts, statusRules: japaneseStatusRules, chargeRules: japaneseChargeRules, paymentPhrases: japanesePaymentPhrases, cityLine: postcodeCity},
+		{Code: "in", Domain: "www.amazon.in", Currency: "INR", Locale: "en-IN", DecimalSeparator: ".", ThousandsSeparator: ",", DateLayouts: dayFirstLayouts},
+		{Code: "au", Domain: "www.amazon.com.au", Currency: "AUD", Locale: "en-AU", DecimalSeparator: ".", ThousandsSeparator: ",", DateLayouts: dayFirstLayouts},
+	} {
+		m.OrderIDPattern = standardOrderID
+		m.compile()
+		registry[m.Code] = m
+	}
+}
+
+// compile builds the regular expressions derived from the marketplace formats
+func (m *Marketplace) compile() {
+	m.orderIDRegex = regexp.MustCompile(m.OrderIDPattern)
+	m.orderIDExactRegex = regexp.MustCompile(`^(?:` + m.OrderIDPattern + `)$`)
+	m.paymentRegex = compilePaymentRegex(m.paymentPhrases)
+
+	// Prices may contain digits, the decimal separator and the thousands separator.
+	// Space-grouped locales also use no-break and narrow no-break spaces.
+	separators := regexp.QuoteMeta(m.DecimalSeparator + m.ThousandsSeparator)
+	if m.ThousandsSeparator == " " {
+		separators += `\x{00a0}\x{202f}`
+	}
+	m.priceRegex = regexp.MustCompile(`\d[\d` + separators + `]*`)
+
+	// Dates are located in free text after translation to English month names
+	m.dateRegex = regexp.MustCompile(
+		`\d{4}-\d{2}-\d{2}` +
+			`|\d{4}年\d{1,2}月\d{1,2}日` +
+			`|[A-Z][a-z]{2,8}\.? \d{1,2},? \d{4}` +
+			`|\d{1,2}\.? [A-Z][a-z]{2,8}\.? \d{4}`)
+}
+
+// Get returns the marketplace registered under code (case-insensitive)
+func Get(code string) (*Marketplace, error) {
+	m, exists := registry[strings.ToLower(strings.TrimSpace(code))]
+	if !exists {
+		return nil, fmt.Errorf("unknown marketplace %q (supported: %s)", code, strings.Join(Codes(), ", "))
+	}
+	return m, nil
+}
+
+// Default returns the default (US) marketplace
+func Default() *Marketplace {
+	return registry[DefaultCode]
+}
+
+// Codes returns the codes of all supported marketplaces in sorted order
+func Codes() []string {
+	codes := make([]string, 0, len(registry))
+	for code := range registry {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// BaseURL returns the storefront URL (e.g. "https://www.amazon.de")
+func (m *Marketplace) BaseURL() string {
+	return "https://" + m.Domain
+}
+
+// AcceptLanguage returns the Accept-Language header value for this marketplace
+func (m *Marketplace) AcceptLanguage() string {
+	language := strings.SplitN(m.Locale, "-", 2)[0]
+	if language == "en" {
+		return fmt.Sprintf("%s,en;q=0.9", m.Locale)
+	}
+	return fmt.Sprintf("%s,%s;q=0.9,en;q=0.8", m.Locale, language)
+}
+
+// FindOrderID returns the first order ID found in text, or "" if none
+func (m *Marketplace) FindOrderID(text string) string {
+	return m.orderIDRegex.FindString(text)
+}
+
+// ValidOrderID reports whether id is a well-formed order ID for this marketplace
+func (m *Marketplace) ValidOrderID(id string) bool {
+	return m.orderIDExactRegex.MatchString(id)
+}
+
+// ParsePrice extracts a price from localized text (e.g. "1.299,99 €" -> 1299.99).
+// A minus sign before the amount or its currency ("-$5.00", "-5,00 €") or right
+// after the amount ("5,00-") makes the price negative, as on promotion, refund and
+// gift card lines. It returns 0 if no price can be found.
+func (m *Marketplace) ParsePrice(text string) float64 {
+	text = strings.TrimSpace(text)
+	loc := m.priceRegex.FindStringIndex(text)
+	if loc == nil {
+		return 0.0
+	}
+
+	// Drop trailing separators such as the space before a currency symbol
+	match := strings.TrimRight(text[loc[0]:loc[1]], m.DecimalSeparator+m.ThousandsSeparator+"\u00a0\u202f")
+	negative := hasTrailingMinus(text[:loc[0]]) || hasLeadingMinus(text[loc[0]+len(match):])
+
+	// Remove grouping and normalize the decimal separator
+	for _, sep := range []string{m.ThousandsSeparator, "\u00a0", "\u202f"} {
+		if sep != "" && sep != m.DecimalSeparator {
+			match = strings.ReplaceAll(match, sep, "")
+		}
+	}
+	match = strings.Replace(match, m.DecimalSeparator, ".", 1)
+
+	price, err := strconv.ParseFloat(match, 64)
+	if err != nil {
+		return 0.0
+	}
+
+	if negative {
+		return -price
+	}
+	return price
+}
+
+// hasTrailingMinus reports whether the text before an amount ends with a minus sign,
+// ignoring spaces, currency symbols and currency codes in between (e.g. "-$", "- EUR ").
+// A hyphen joined to a word, as in "Item-12", is not a sign.
+func hasTrailingMinus(prefix string) bool {
+	prefix = strings.TrimRightFunc(prefix, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.Is(unicode.Sc, r) || (r >= 'A' && r <= 'Z')
+	})
+	rest, found := strings.CutSuffix(prefix, "-")
+	if !found {
+		rest, found = strings.CutSuffix(prefix, "−")
+	}
+	if !found || rest == "" {
+		return found
+	}
+	last, _ := utf8.DecodeLastRuneInString(rest)
+	return !unicode.IsLetter(last) && !unicode.IsDigit(last)
+}
+
+// hasLeadingMinus reports whether the text right after an amount starts with a minus
+// sign that isn't the dash of a price range such as "$10-$20"
+func hasLeadingMinus(suffix string) bool {
+	rest, found := strings.CutPrefix(suffix, "-")
+	if !found {
+		rest, found = strings.CutPrefix(suffix, "−")
+	}
+	return found && strings.IndexFunc(rest, unicode.IsDigit) < 0
+}
+
+// ParseDate finds and parses a localized date in text (e.g. "Bestellt am 15. Januar 2024").
+func (m *Marketplace) ParseDate(text string) (time.Time, error) {
+	normalized := m.normalizeDate(text)
+
+	candidate := m.dateRegex.FindString(normalized)
+	if candidate == "" {
+		candidate = normalized
+	}
+
+	layouts := append([]string{"2006-01-02"}, m.DateLayouts...)
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, candidate); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognized %s date: %q", m.Code, strings.TrimSpace(text))
+}
+
+// timeOfDayRegex matches clock times such as "3:04 PM" or "14:30"
+var timeOfDayRegex = regexp.MustCompile(`(\d{1,2}):(\d{2})\s*([AaPp]\.?[Mm]\.?)?`)
+
+// ParseDateTime parses a localized date with an optional time of day
+// (e.g. "January 15, 2024 3:45 PM" or "15. Januar 2024 15:45")
+func (m *Marketplace) ParseDateTime(text string) (time.Time, error) {
+	date, err := m.ParseDate(text)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	matches := timeOfDayRegex.FindStringSubmatch(text)
+	if matches == nil {
+		return date, nil
+	}
+
+	hour, _ := strconv.Atoi(matches[1])
+	minute, _ := strconv.Atoi(matches[2])
+	meridiem := strings.ToLower(strings.ReplaceAll(matches[3], ".", ""))
+	if meridiem == "pm" && hour < 12 {
+		hour += 12
+	} else if meridiem == "am" && hour == 12 {
+		hour = 0
+	}
+
+	return date.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute), nil
+}
+
+// normalizeDate translates localized month names to English and removes filler words
+func (m *Marketplace) normalizeDate(text string) string {
+	words := strings.Fields(text)
+	result := make([]string, 0, len(words))
+
+	for _, word := range words {
+		lower := strings.ToLower(strings.TrimRight(word, ","))
+
+		if m.isDateNoise(lower) {
+			continue
+		}
+
+		// French ordinal first day of month ("1er")
+		if lower == "1er" {
+			word = "1"
+		}
+
+		if english, ok := m.months[strings.TrimSuffix(lower, ".")]; ok {
+			word = english
+		}
+
+		result = append(result, word)
+	}
+
+	return strings.Join(result, " ")
+}
+
+// isDateNoise reports whether a word should be dropped before date parsing
+func (m *Marketplace) isDateNoise(word string) bool {
+	for _, noise := range m.dateNoise {
+		if word == noise {
+			return true
+		}
+	}
+	return false
+}
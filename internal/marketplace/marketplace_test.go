@@ -0,0 +1,309 @@
+package marketplace
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGet(t *testing.T) {
+	m, err := Get("UK")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if m.Domain != "www.amazon.co.uk" {
+		t.Errorf("expected www.amazon.co.uk, got %s", m.Domain)
+	}
+	if m.Currency != "GBP" {
+		t.Errorf("expected GBP, got %s", m.Currency)
+	}
+
+	if _, err := Get("zz"); err == nil {
+		t.Error("expected error for unknown marketplace")
+	}
+}
+
+func TestDefault(t *testing.T) {
+	m := Default()
+	if m.Code != DefaultCode {
+		t.Errorf("expected default code %s, got %s", DefaultCode, m.Code)
+	}
+	if m.BaseURL() != "https://www.amazon.com" {
+		t.Errorf("expected https://www.amazon.com, got %s", m.BaseURL())
+	}
+}
+
+func TestCodes(t *testing.T) {
+	codes := Codes()
+	if len(codes) < 10 {
+		t.Errorf("expected at least 10 marketplaces, got %d", len(codes))
+	}
+	for i := 1; i < len(codes); i++ {
+		if codes[i-1] > codes[i] {
+			t.Errorf("expected sorted codes, got %v", codes)
+			break
+		}
+	}
+}
+
+func TestAcceptLanguage(t *testing.T) {
+	tests := map[string]string{
+		"us": "en-US,en;q=0.9",
+		"uk": "en-GB,en;q=0.9",
+		"de": "de-DE,de;q=0.9,en;q=0.8",
+		"jp": "ja-JP,ja;q=0.9,en;q=0.8",
+	}
+
+	for code, expected := range tests {
+		m, _ := Get(code)
+		if got := m.AcceptLanguage(); got != expected {
+			t.Errorf("%s: expected %q, got %q", code, expected, got)
+		}
+	}
+}
+
+func TestParsePrice(t *testing.T) {
+	tests := []struct {
+		code     string
+		input    string
+		expected float64
+	}{
+		{"us", "$1,299.99", 1299.99},
+		{"us", "$29.99", 29.99},
+		{"uk", "£12.50", 12.50},
+		{"de", "1.299,99 €", 1299.99},
+		{"de", "EUR 29,99", 29.99},
+		{"fr", "1 299,99 €", 1299.99},
+		{"fr", "1 299,99 €", 1299.99},
+		{"it", "24,90 €", 24.90},
+		{"jp", "￥1,280", 1280},
+		{"in", "₹1,499.00", 1499},
+		{"us", "", 0},
+		{"de", "nicht verfügbar", 0},
+		{"us", "-$5.00", -5},
+		{"us", "Promotion Applied: -$5.99", -5.99},
+		{"us", "−$12.00", -12},
+		{"us", "$10.00-$20.00", 10},
+		{"ca", "- CDN$ 3.50", -3.50},
+		{"de", "-5,00 €", -5},
+		{"de", "EUR -29,99", -29.99},
+		{"de", "5,00- €", -5},
+		{"fr", "-1 299,99 €", -1299.99},
+		{"jp", "-￥500", -500},
+		{"us", "Item-12", 12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code+" "+tt.input, func(t *testing.T) {
+			m, _ := Get(tt.code)
+			if got := m.ParsePrice(tt.input); got != tt.expected {
+				t.Errorf("ParsePrice(%q) = %v, expected %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	expected := time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		code  string
+		input string
+	}{
+		{"us", "January 15, 2024"},
+		{"us", "Order placed: Jan 15, 2024"},
+		{"us", "2024-01-15"},
+		{"uk", "15 January 2024"},
+		{"uk", "Reviewed in the United Kingdom on 15 Jan 2024"},
+		{"de", "15. Januar 2024"},
+		{"de", "Bestellung aufgegeben: 15. Januar 2024"},
+		{"fr", "le 15 janvier 2024"},
+		{"it", "15 gennaio 2024"},
+		{"es", "15 de enero de 2024"},
+		{"mx", "15 de enero de 2024"},
+		{"jp", "2024年1月15日"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code+" "+tt.input, func(t *testing.T) {
+			m, _ := Get(tt.code)
+			got, err := m.ParseDate(tt.input)
+			if err != nil {
+				t.Fatalf("ParseDate(%q) error = %v", tt.input, err)
+			}
+			if !got.Equal(expected) {
+				t.Errorf("ParseDate(%q) = %v, expected %v", tt.input, got, expected)
+			}
+		})
+	}
+}
+
+func TestParseDate_Invalid(t *testing.T) {
+	for _, input := range []string{"", "yesterday", "Delivered"} {
+		if _, err := Default().ParseDate(input); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
+
+func TestParseDateTime(t *testing.T) {
+	us := Default()
+	got, err := us.ParseDateTime("January 15, 2024 3:45 PM")
+	if err != nil {
+		t.Fatalf("ParseDateTime() error = %v", err)
+	}
+	expected := time.Date(2024, time.January, 15, 15, 45, 0, 0, time.UTC)
+	if !got.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	de, _ := Get("de")
+	got, err = de.ParseDateTime("15. Januar 2024 14:30")
+	if err != nil {
+		t.Fatalf("ParseDateTime() error = %v", err)
+	}
+	expected = time.Date(2024, time.January, 15, 14, 30, 0, 0, time.UTC)
+	if !got.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	// A date without a time falls back to midnight
+	got, err = us.ParseDateTime("January 15, 2024")
+	if err != nil {
+		t.Fatalf("ParseDateTime() error = %v", err)
+	}
+	if got.Hour() != 0 || got.Minute() != 0 {
+		t.Errorf("expected midnight, got %v", got)
+	}
+}
+
+func TestOrderID(t *testing.T) {
+	m := Default()
+
+	if !m.ValidOrderID("123-4567890-1234567") {
+		t.Error("expected valid order ID")
+	}
+	if !m.ValidOrderID("D01-4567890-1234567") {
+		t.Error("expected valid digital order ID")
+	}
+	for _, id := range []string{"", "123-456-789", "order 123-4567890-1234567", "abc-defghij-klmnopq", "DD1-4567890-1234567"} {
+		if m.ValidOrderID(id) {
+			t.Errorf("expected %q to be invalid", id)
+		}
+	}
+
+	if got := m.FindOrderID("Order # 123-4567890-1234567 placed"); got != "123-4567890-1234567" {
+		t.Errorf("expected order ID to be found, got %q", got)
+	}
+	if got := m.FindOrderID("no order here"); got != "" {
+		t.Errorf("expected empty result, got %q", got)
+	}
+}
+
+func TestParseCityLine(t *testing.T) {
+	tests := []struct {
+		code     string
+		input    string
+		city     string
+		state    string
+		postcode string
+		ok       bool
+	}{
+		{"us", "Seattle, WA 98101", "Seattle", "WA", "98101", true},
+		{"ca", "Toronto, ON M5V 2T6", "Toronto", "ON", "M5V 2T6", true},
+		{"uk", "London SW1A 1AA", "London", "", "SW1A 1AA", true},
+		{"uk", "Manchester, M1 1AE", "Manchester", "", "M1 1AE", true},
+		{"de", "10115 Berlin", "Berlin", "", "10115", true},
+		{"fr", "75001 Paris", "Paris", "", "75001", true},
+		{"jp", "〒100-0001 東京都千代田区", "東京都千代田区", "", "100-0001", true},
+		{"de", "Berlin", "Berlin", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code+" "+tt.input, func(t *testing.T) {
+			m, _ := Get(tt.code)
+			city, state, postcode, ok := m.ParseCityLine(tt.input)
+			if city != tt.city || state != tt.state || postcode != tt.postcode || ok != tt.ok {
+				t.Errorf("ParseCityLine(%q) = %q, %q, %q, %v; expected %q, %q, %q, %v",
+					tt.input, city, state, postcode, ok, tt.city, tt.state, tt.postcode, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParsePaymentCard(t *testing.T) {
+	tests := []struct {
+		code     string
+		input    string
+		cardType string
+		last4    string
+	}{
+		{"us", "Visa ending in 1234", "Visa", "1234"},
+		{"uk", "Mastercard ending in 0005", "Mastercard", "0005"},
+		{"de", "Visa endet auf 4821", "Visa", "4821"},
+		{"de", "Visa ending in 4821", "Visa", "4821"},
+		{"fr", "Carte Visa se terminant par 7310", "Carte Visa", "7310"},
+		{"it", "Mastercard che termina con 9034", "Mastercard", "9034"},
+		{"es", "Visa que termina en 1111", "Visa", "1111"},
+		{"jp", "JCB 末尾が5678", "JCB", "5678"},
+		{"jp", "VISA 下4桁: 2468", "VISA", "2468"},
+		{"us", "Amazon Gift Card", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code+" "+tt.input, func(t *testing.T) {
+			m, _ := Get(tt.code)
+			cardType, last4, ok := m.ParsePaymentCard(tt.input)
+			if cardType != tt.cardType || last4 != tt.last4 || ok != (tt.last4 != "") {
+				t.Errorf("ParsePaymentCard(%q) = %q, %q, %v; expected %q, %q", tt.input, cardType, last4, ok, tt.cardType, tt.last4)
+			}
+		})
+	}
+}
+
+func TestChargeKind(t *testing.T) {
+	tests := []struct {
+		code  string
+		label string
+		kind  string
+	}{
+		{"us", "Item(s) Subtotal:", ChargeSubtotal},
+		{"us", "Shipping & Handling:", ChargeShipping},
+		{"us", "Free Shipping:", ChargePromotion},
+		{"us", "Total before tax:", ChargeBeforeTax},
+		{"us", "Estimated tax to be collected:", ChargeTax},
+		{"us", "Gift Card Amount:", ChargeGiftCard},
+		{"us", "Refund Total:", ChargeRefund},
+		{"us", "Grand Total:", ChargeTotal},
+		{"uk", "Postage & Packing:", ChargeShipping},
+		{"uk", "VAT:", ChargeTax},
+		{"de", "Zwischensumme:", ChargeSubtotal},
+		{"de", "Verpackung & Versand:", ChargeShipping},
+		{"de", "Kostenloser Versand:", ChargePromotion},
+		{"de", "Summe ohne MwSt.:", ChargeBeforeTax},
+		{"de", "MwSt.:", ChargeTax},
+		{"de", "Gesamtsumme:", ChargeTotal},
+		{"de", "Grand Total:", ChargeTotal},
+		{"fr", "Sous-total :", ChargeSubtotal},
+		{"fr", "Livraison :", ChargeShipping},
+		{"fr", "TVA :", ChargeTax},
+		{"fr", "Montant total TTC :", ChargeTotal},
+		{"it", "Spedizione:", ChargeShipping},
+		{"it", "Totale ordine:", ChargeTotal},
+		{"es", "Envío:", ChargeShipping},
+		{"es", "Total del pedido:", ChargeTotal},
+		{"jp", "商品の小計：", ChargeSubtotal},
+		{"jp", "配送料・手数料：", ChargeShipping},
+		{"jp", "注文合計（税込）：", ChargeTotal},
+		{"jp", "消費税：", ChargeTax},
+		{"us", "Payment method", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code+" "+tt.label, func(t *testing.T) {
+			m, _ := Get(tt.code)
+			if kind := m.ChargeKind(tt.label); kind != tt.kind {
+				t.Errorf("ChargeKind(%q) = %q, expected %q", tt.label, kind, tt.kind)
+			}
+		})
+	}
+}
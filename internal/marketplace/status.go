@@ -0,0 +1,102 @@
+package marketplace
+
+import (
+	"strings"
+
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+// statusRule maps phrases from Amazon's order status text to a normalized status.
+// Rules are checked in order, so more specific phrases (e.g. "return started")
+// must come before the general ones they contain (e.g. "return").
+type statusRule struct {
+	phrases []string
+	status  string
+}
+
+// Localized order status phrases, in lower case
+var (
+	englishStatusRules = []statusRule{
+		{[]string{"return started", "return requested", "return in progress"}, models.OrderStatusReturnStarted},
+		{[]string{"refund"}, models.OrderStatusRefunded},
+		{[]string{"returned", "return complete", "return received"}, models.OrderStatusReturned},
+		{[]string{"cancelled", "canceled"}, models.OrderStatusCancelled},
+		{[]string{"out for delivery"}, models.OrderStatusOutForDelivery},
+		{[]string{"delayed", "running late", "delivery attempted"}, models.OrderStatusDelayed},
+		{[]string{"delivered"}, models.OrderStatusDelivered},
+		{[]string{"arriving", "shipping", "shipped", "not yet shipped", "preparing for shipment", "ordered"}, models.OrderStatusPending},
+	}
+	germanStatusRules = []statusRule{
+		{[]string{"rücksendung gestartet", "rücksendung angefordert", "rücksendung eingeleitet", "rücksendung in bearbeitung"}, models.OrderStatusReturnStarted},
+		{[]string{"erstattet", "erstattung"}, models.OrderStatusRefunded},
+		{[]string{"zurückgesendet", "zurückgegeben", "rücksendung abgeschlossen", "rücksendung erhalten"}, models.OrderStatusReturned},
+		{[]string{"storniert"}, models.OrderStatusCancelled},
+		{[]string{"in zustellung", "wird heute zugestellt"}, models.OrderStatusOutForDelivery},
+		{[]string{"verspätet", "verzögert", "zustellversuch"}, models.OrderStatusDelayed},
+		{[]string{"zugestellt"}, models.OrderStatusDelivered},
+		{[]string{"versandt", "verschickt", "unterwegs", "wird vorbereitet", "lieferung", "bestellt"}, models.OrderStatusPending},
+	}
+	frenchStatusRules = []statusRule{
+		{[]string{"retour lancé", "retour demandé", "retour en cours"}, models.OrderStatusReturnStarted},
+		{[]string{"rembours"}, models.OrderStatusRefunded},
+		{[]string{"retourné", "retour terminé", "retour reçu"}, models.OrderStatusReturned},
+		{[]string{"annulé"}, models.OrderStatusCancelled},
+		{[]string{"en cours de livraison", "en livraison"}, models.OrderStatusOutForDelivery},
+		{[]string{"retardé", "en retard", "tentative de livraison"}, models.OrderStatusDelayed},
+		{[]string{"livré"}, models.OrderStatusDelivered},
+		{[]string{"expédié", "en cours d'expédition", "arrivée", "livraison prévue", "commandé"}, models.OrderStatusPending},
+	}
+	italianStatusRules = []statusRule{
+		{[]string{"reso avviato", "reso richiesto", "reso in corso"}, models.OrderStatusReturnStarted},
+		{[]string{"rimbors"}, models.OrderStatusRefunded},
+		{[]string{"restituito", "reso completato", "reso ricevuto"}, models.OrderStatusReturned},
+		{[]string{"annullato", "cancellato"}, models.OrderStatusCancelled},
+		{[]string{"in consegna"}, models.OrderStatusOutForDelivery},
+		{[]string{"in ritardo", "tentativo di consegna"}, models.OrderStatusDelayed},
+		{[]string{"consegnato"}, models.OrderStatusDelivered},
+		{[]string{"spedito", "in arrivo", "arriva", "in preparazione", "ordinato"}, models.OrderStatusPending},
+	}
+	spanishStatusRules = []statusRule{
+		{[]string{"devolución iniciada", "devolución solicitada", "devolución en curso"}, models.OrderStatusReturnStarted},
+		{[]string{"reembols"}, models.OrderStatusRefunded},
+		{[]string{"devuelto", "devolución completada", "devolución recibida"}, models.OrderStatusReturned},
+		{[]string{"cancelado"}, models.OrderStatusCancelled},
+		{[]string{"en reparto"}, models.OrderStatusOutForDelivery},
+		{[]string{"retrasado", "intento de entrega"}, models.OrderStatusDelayed},
+		{[]string{"entregado"}, models.OrderStatusDelivered},
+		{[]string{"enviado", "llega", "en preparación", "pedido realizado"}, models.OrderStatusPending},
+	}
+	japaneseStatusRules = []statusRule{
+		{[]string{"返品手続き中", "返品リクエスト"}, models.OrderStatusReturnStarted},
+		{[]string{"返金"}, models.OrderStatusRefunded},
+		{[]string{"返品済み", "返品完了"}, models.OrderStatusReturned},
+		{[]string{"キャンセル"}, models.OrderStatusCancelled},
+		{[]string{"配達中"}, models.OrderStatusOutForDelivery},
+		{[]string{"遅延", "配達を試みました"}, models.OrderStatusDelayed},
+		{[]string{"配達済み", "配達しました", "お届け済み"}, models.OrderStatusDelivered},
+		{[]string{"発送済み", "発送準備中", "未発送", "お届け予定", "配送中", "注文済み"}, models.OrderStatusPending},
+	}
+)
+
+// OrderStatus maps free-form order status text (e.g. "Delivered Jan 18" or
+// "Zugestellt am 18. Januar") to one of the models.OrderStatus* values.
+// The marketplace's own phrases are checked before the English ones, which
+// Amazon also shows on some localized pages.
+func (m *Marketplace) OrderStatus(text string) string {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "" {
+		return models.OrderStatusUnknown
+	}
+
+	for _, rules := range [][]statusRule{m.statusRules, englishStatusRules} {
+		for _, rule := range rules {
+			for _, phrase := range rule.phrases {
+				if strings.Contains(text, phrase) {
+					return rule.status
+				}
+			}
+		}
+	}
+
+	return models.OrderStatusUnknown
+}
@@ -0,0 +1,210 @@
+package orderindex
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/zkwentz/amazon-cli/internal/atomicfile"
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+// DefaultProfile is the profile used when none is configured
+const DefaultProfile = "default"
+
+// formatVersion is the on-disk format version of the index file
+const formatVersion = 1
+
+// profileRegex restricts profile names to characters that are safe in file names
+var profileRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// SyncState tracks incremental sync progress for a profile
+type SyncState struct {
+	// LastSyncedOrderID is the newest order seen by the last completed sync
+	LastSyncedOrderID string `json:"last_synced_order_id,omitempty"`
+	// LastSyncedAt is when the last sync completed
+	LastSyncedAt time.Time `json:"last_synced_at,omitempty"`
+	// InProgress is set while a sync runs; it remains after an interruption so the next sync resumes
+	InProgress *SyncCheckpoint `json:"in_progress,omitempty"`
+}
+
+// SyncCheckpoint records an unfinished sync
+type SyncCheckpoint struct {
+	StartedAt     time.Time `json:"started_at"`
+	NewestOrderID string    `json:"newest_order_id,omitempty"`
+	Processed     int       `json:"processed"`
+}
+
+// indexFile is the JSON document stored on disk
+type indexFile struct {
+	Version     int                     `json:"version"`
+	Profile     string                  `json:"profile"`
+	Marketplace string                  `json:"marketplace,omitempty"`
+	Sync        SyncState               `json:"sync"`
+	Orders      map[string]models.Order `json:"orders"`
+}
+
+// Index is a local store of orders for one profile, persisted as a JSON file
+type Index struct {
+	path string
+	data indexFile
+	mu   sync.Mutex
+}
+
+// ValidateProfile checks that a profile name can be used as a file name
+func ValidateProfile(profile string) error {
+	if !profileRegex.MatchString(profile) {
+		return fmt.Errorf("invalid profile %q: use letters, digits, '-' and '_'", profile)
+	}
+	return nil
+}
+
+// DefaultPath returns the index file path for a profile (~/.amazon-cli/index/<profile>.json)
+func DefaultPath(profile string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".amazon-cli", "index", profile+".json")
+}
+
+// Open loads the index stored at path for the given profile.
+// If the file doesn't exist, an empty index is returned; it is created on Save.
+func Open(path, profile string) (*Index, error) {
+	idx := &Index{
+		path: path,
+		data: indexFile{
+			Version: formatVersion,
+			Profile: profile,
+			Orders:  make(map[string]models.Order),
+		},
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return idx, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read order index: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &idx.data); err != nil {
+		return nil, fmt.Errorf("failed to parse order index %s: %w", path, err)
+	}
+	if idx.data.Version > formatVersion {
+		return nil, fmt.Errorf("order index %s has unsupported version %d", path, idx.data.Version)
+	}
+	if idx.data.Orders == nil {
+		idx.data.Orders = make(map[string]models.Order)
+	}
+
+	return idx, nil
+}
+
+// Path returns the file the index is stored in
+func (i *Index) Path() string {
+	return i.path
+}
+
+// Profile returns the profile the index belongs to
+func (i *Index) Profile() string {
+	return i.data.Profile
+}
+
+// Marketplace returns the marketplace code the orders were synced from
+func (i *Index) Marketplace() string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.data.Marketplace
+}
+
+// SetMarketplace records the marketplace code the orders are synced from
+func (i *Index) SetMarketplace(code string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.data.Marketplace = code
+}
+
+// Get returns the stored order with the given ID
+func (i *Index) Get(orderID string) (models.Order, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	order, exists := i.data.Orders[orderID]
+	return order, exists
+}
+
+// Put stores or replaces an order
+func (i *Index) Put(order models.Order) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.data.Orders[order.OrderID] = order
+}
+
+// Len returns the number of stored orders
+func (i *Index) Len() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return len(i.data.Orders)
+}
+
+// Orders returns all stored orders, newest first.
+// Dates are compared as stored, so they should be normalized to YYYY-MM-DD.
+func (i *Index) Orders() []models.Order {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	orders := make([]models.Order, 0, len(i.data.Orders))
+	for _, order := range i.data.Orders {
+		orders = append(orders, order)
+	}
+	sort.Slice(orders, func(a, b int) bool {
+		if orders[a].Date != orders[b].Date {
+			return orders[a].Date > orders[b].Date
+		}
+		return orders[a].OrderID > orders[b].OrderID
+	})
+
+	return orders
+}
+
+// SyncState returns the sync progress
+func (i *Index) SyncState() SyncState {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.data.Sync
+}
+
+// SetSyncState replaces the sync progress
+func (i *Index) SetSyncState(state SyncState) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.data.Sync = state
+}
+
+// Save writes the index to disk atomically with 0600 permissions
+func (i *Index) Save() error {
+	i.mu.Lock()
+	data, err := json.MarshalIndent(i.data, "", "  ")
+	i.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal order index: %w", err)
+	}
+
+	// Ensure directory exists
+	dir := filepath.Dir(i.path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create order index directory: %w", err)
+	}
+
+	// Write to a temporary file and rename it so an interruption never leaves a partial index
+	if err := atomicfile.Write(i.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write order index: %w", err)
+	}
+
+	return nil
+}
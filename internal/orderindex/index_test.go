@@ -0,0 +1,120 @@
+package orderindex
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+func TestOpen_MissingFileReturnsEmptyIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index", "default.json")
+
+	idx, err := Open(path, DefaultProfile)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if idx.Len() != 0 {
+		t.Errorf("Expected empty index, got %d orders", idx.Len())
+	}
+	if idx.Profile() != DefaultProfile {
+		t.Errorf("Expected profile %s, got %s", DefaultProfile, idx.Profile())
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Expected Open not to create the file")
+	}
+}
+
+func TestIndex_SaveAndReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index", "work.json")
+
+	idx, _ := Open(path, "work")
+	idx.SetMarketplace("uk")
+	idx.Put(models.Order{OrderID: "111-0000000-0000001", Date: "2024-01-15", Total: 29.99})
+	idx.Put(models.Order{OrderID: "111-0000000-0000002", Date: "2025-03-01", Total: 54.99})
+	state := SyncState{
+		LastSyncedOrderID: "111-0000000-0000002",
+		LastSyncedAt:      time.Date(2025, 3, 2, 10, 0, 0, 0, time.UTC),
+	}
+	idx.SetSyncState(state)
+
+	if err := idx.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Expected index file to exist: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected 0600 permissions, got %v", info.Mode().Perm())
+	}
+
+	reopened, err := Open(path, "work")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if reopened.Len() != 2 {
+		t.Errorf("Expected 2 orders, got %d", reopened.Len())
+	}
+	if reopened.Marketplace() != "uk" {
+		t.Errorf("Expected marketplace uk, got %s", reopened.Marketplace())
+	}
+	if got := reopened.SyncState(); got.LastSyncedOrderID != state.LastSyncedOrderID || !got.LastSyncedAt.Equal(state.LastSyncedAt) {
+		t.Errorf("Expected sync state %+v, got %+v", state, got)
+	}
+
+	order, exists := reopened.Get("111-0000000-0000001")
+	if !exists || order.Total != 29.99 {
+		t.Errorf("Expected stored order, got %+v (exists=%v)", order, exists)
+	}
+
+	// No temporary files are left behind
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("Expected only the index file, got %d entries", len(entries))
+	}
+}
+
+func TestIndex_OrdersNewestFirst(t *testing.T) {
+	idx, _ := Open(filepath.Join(t.TempDir(), "default.json"), DefaultProfile)
+	idx.Put(models.Order{OrderID: "111-0000000-0000001", Date: "2023-06-01"})
+	idx.Put(models.Order{OrderID: "111-0000000-0000003", Date: "2025-01-10"})
+	idx.Put(models.Order{OrderID: "111-0000000-0000002", Date: "2025-01-10"})
+
+	orders := idx.Orders()
+	expected := []string{"111-0000000-0000003", "111-0000000-0000002", "111-0000000-0000001"}
+	for i, order := range orders {
+		if order.OrderID != expected[i] {
+			t.Errorf("Position %d: expected %s, got %s", i, expected[i], order.OrderID)
+		}
+	}
+}
+
+func TestOpen_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "default.json")
+	_ = os.WriteFile(path, []byte("{not json"), 0600)
+	if _, err := Open(path, DefaultProfile); err == nil {
+		t.Error("Expected error for corrupt index")
+	}
+
+	_ = os.WriteFile(path, []byte(`{"version": 99}`), 0600)
+	if _, err := Open(path, DefaultProfile); err == nil {
+		t.Error("Expected error for unsupported version")
+	}
+}
+
+func TestValidateProfile(t *testing.T) {
+	for _, profile := range []string{"default", "work", "team_uk-2"} {
+		if err := ValidateProfile(profile); err != nil {
+			t.Errorf("Expected %q to be valid: %v", profile, err)
+		}
+	}
+	for _, profile := range []string{"", "../etc", "a/b", "with space"} {
+		if err := ValidateProfile(profile); err == nil {
+			t.Errorf("Expected %q to be invalid", profile)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package orderstats
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/zkwentz/amazon-cli/internal/marketplace"
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+// DefaultMinOrders is how many orders an item needs to count as repurchased by default
+const DefaultMinOrders = 2
+
+// Repurchase is an item bought in several orders
+type Repurchase struct {
+	ASIN        string  `json:"asin"`
+	Title       string  `json:"title"`
+	Orders      int     `json:"orders"`
+	Quantity    int     `json:"quantity"`
+	LastOrdered string  `json:"last_ordered"`
+	LastOrderID string  `json:"last_order_id"`
+	LastPrice   float64 `json:"last_price"`
+	// IntervalDays is the average number of days between orders of the item
+	IntervalDays float64 `json:"interval_days"`
+}
+
+// RepurchaseList ranks the items bought most often
+type RepurchaseList struct {
+	Items      []Repurchase `json:"items"`
+	TotalCount int          `json:"total_count"`
+}
+
+// repurchaseTotals accumulates an item across orders
+type repurchaseTotals struct {
+	item   Repurchase
+	orders map[string]bool
+	first  time.Time
+	last   time.Time
+}
+
+// Repurchases ranks the ASINs that appear in at least minOrders orders, most
+// frequently ordered first, then most recently ordered. Cancelled orders are ignored.
+// A limit of 0 or less returns every item. Dates are parsed in the given
+// marketplace's format (nil means US).
+func Repurchases(orders []models.Order, minOrders, limit int, m *marketplace.Marketplace) (*RepurchaseList, error) {
+	if m == nil {
+		m = marketplace.Default()
+	}
+	if minOrders < 1 {
+		minOrders = 1
+	}
+
+	items := make(map[string]*repurchaseTotals)
+	for _, order := range orders {
+		if order.Status == models.OrderStatusCancelled {
+			continue
+		}
+		date, err := m.ParseDate(order.Date)
+		if err != nil {
+			return nil, fmt.Errorf("order %s: %w", order.OrderID, err)
+		}
+
+		for _, item := range order.Items {
+			if item.ASIN == "" {
+				continue
+			}
+			totals, exists := items[item.ASIN]
+			if !exists {
+				totals = &repurchaseTotals{item: Repurchase{ASIN: item.ASIN}, orders: make(map[string]bool), first: date, last: date}
+				items[item.ASIN] = totals
+			}
+			totals.orders[order.OrderID] = true
+			totals.item.Quantity += itemQuantity(item)
+			if date.Before(totals.first) {
+				totals.first = date
+			}
+			if !date.Before(totals.last) {
+				totals.last = date
+				totals.item.LastOrderID = order.OrderID
+				totals.item.LastPrice = item.Price
+				if item.Title != "" {
+					totals.item.Title = item.Title
+				}
+			}
+			if totals.item.Title == "" {
+				totals.item.Title = item.Title
+			}
+		}
+	}
+
+	list := &RepurchaseList{Items: []Repurchase{}}
+	for _, totals := range items {
+		totals.item.Orders = len(totals.orders)
+		if totals.item.Orders < minOrders {
+			continue
+		}
+		totals.item.LastOrdered = totals.last.Format("2006-01-02")
+		if totals.item.Orders > 1 {
+			totals.item.IntervalDays = round(totals.last.Sub(totals.first).Hours() / 24 / float64(totals.item.Orders-1))
+		}
+		list.Items = append(list.Items, totals.item)
+	}
+
+	sort.Slice(list.Items, func(i, j int) bool {
+		a, b := list.Items[i], list.Items[j]
+		if a.Orders != b.Orders {
+			return a.Orders > b.Orders
+		}
+		if a.LastOrdered != b.LastOrdered {
+			return a.LastOrdered > b.LastOrdered
+		}
+		return a.ASIN < b.ASIN
+	})
+	if limit > 0 && len(list.Items) > limit {
+		list.Items = list.Items[:limit]
+	}
+	list.TotalCount = len(list.Items)
+
+	return list, nil
+}
+
+// Header returns the table and csv column names
+func (l *RepurchaseList) Header() []string {
+	return []string{"asin", "title", "orders", "quantity", "last_ordered", "last_price", "interval_days"}
+}
+
+// Rows returns a row per item
+func (l *RepurchaseList) Rows() [][]string {
+	rows := make([][]string, 0, len(l.Items))
+	for _, item := range l.Items {
+		rows = append(rows, []string{
+			item.ASIN, item.Title, strconv.Itoa(item.Orders), strconv.Itoa(item.Quantity),
+			item.LastOrdered, formatAmount(item.LastPrice), strconv.FormatFloat(item.IntervalDays, 'f', 1, 64),
+		})
+	}
+	return rows
+}
@@ -0,0 +1,63 @@
+package orderstats
+
+import (
+	"testing"
+
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+func TestRepurchases(t *testing.T) {
+	orders := []models.Order{
+		{OrderID: "111-0000000-0000001", Date: "January 1, 2024", Status: models.OrderStatusDelivered,
+			Items: []models.OrderItem{{ASIN: "B000FILTER", Title: "Water Filter", Quantity: 2, Price: 12.50}, {ASIN: "B0000SOAP1", Title: "Soap", Quantity: 1, Price: 4}}},
+		{OrderID: "111-0000000-0000002", Date: "2024-03-01", Status: models.OrderStatusDelivered,
+			Items: []models.OrderItem{{ASIN: "B000FILTER", Title: "Water Filter (2-Pack)", Quantity: 1, Price: 13.25}}},
+		{OrderID: "111-0000000-0000003", Date: "April 30, 2024", Status: models.OrderStatusDelivered,
+			Items: []models.OrderItem{{ASIN: "B000FILTER", Title: "Water Filter", Quantity: 1, Price: 13.99}, {ASIN: "B0000SOAP1", Title: "Soap", Quantity: 3, Price: 3.50}}},
+		{OrderID: "111-0000000-0000004", Date: "May 2, 2024", Status: models.OrderStatusDelivered,
+			Items: []models.OrderItem{{ASIN: "B0000LAMP1", Title: "Lamp", Quantity: 1, Price: 40}}},
+		// Cancelled orders don't count
+		{OrderID: "111-0000000-0000005", Date: "May 3, 2024", Status: models.OrderStatusCancelled,
+			Items: []models.OrderItem{{ASIN: "B0000LAMP1", Title: "Lamp", Quantity: 1, Price: 40}}},
+	}
+
+	list, err := Repurchases(orders, DefaultMinOrders, 0, nil)
+	if err != nil {
+		t.Fatalf("Repurchases() error = %v", err)
+	}
+
+	if list.TotalCount != 2 || len(list.Items) != 2 {
+		t.Fatalf("Expected 2 repurchased items, got %+v", list.Items)
+	}
+
+	filter := list.Items[0]
+	if filter.ASIN != "B000FILTER" || filter.Orders != 3 || filter.Quantity != 4 {
+		t.Errorf("Unexpected first item: %+v", filter)
+	}
+	if filter.LastOrdered != "2024-04-30" || filter.LastOrderID != "111-0000000-0000003" || filter.LastPrice != 13.99 || filter.Title != "Water Filter" {
+		t.Errorf("Expected the latest order's details, got %+v", filter)
+	}
+	// January 1 to April 30 is 120 days over two intervals
+	if filter.IntervalDays != 60 {
+		t.Errorf("Expected a 60 day interval, got %v", filter.IntervalDays)
+	}
+
+	soap := list.Items[1]
+	if soap.ASIN != "B0000SOAP1" || soap.Orders != 2 || soap.Quantity != 4 || soap.IntervalDays != 120 {
+		t.Errorf("Unexpected second item: %+v", soap)
+	}
+
+	// A minimum of one order includes everything; the limit keeps the top items
+	list, err = Repurchases(orders, 1, 2, nil)
+	if err != nil || len(list.Items) != 2 || list.Items[1].ASIN != "B0000SOAP1" {
+		t.Errorf("Expected the top two items, got %+v (%v)", list, err)
+	}
+	list, _ = Repurchases(orders, 1, 0, nil)
+	if len(list.Items) != 3 || list.Items[2].IntervalDays != 0 {
+		t.Errorf("Expected the lamp bought once without an interval, got %+v", list.Items)
+	}
+
+	if rows := list.Rows(); len(rows) != 3 || rows[0][2] != "3" || rows[0][6] != "60.0" {
+		t.Errorf("Unexpected rows: %v", rows)
+	}
+}
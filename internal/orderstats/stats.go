@@ -0,0 +1,300 @@
+package orderstats
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zkwentz/amazon-cli/internal/marketplace"
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+// Groupings supported by Compute
+const (
+	GroupByMonth    = "month"
+	GroupByCategory = "category"
+	GroupBySeller   = "seller"
+	GroupByASIN     = "asin"
+)
+
+// Keys of spending that can't be attributed to an item value
+const (
+	// KeyUnknown collects items without a category, seller or ASIN
+	KeyUnknown = "(unknown)"
+	// KeyOther collects order amounts not covered by item prices, such as tax and shipping
+	KeyOther = "(other)"
+)
+
+// DefaultTopItems is the number of top items reported by default
+const DefaultTopItems = 10
+
+// Group is the spending of one month, category, seller or ASIN
+type Group struct {
+	Key     string  `json:"key"`
+	Orders  int     `json:"orders"`
+	Items   int     `json:"items"`
+	Total   float64 `json:"total"`
+	Average float64 `json:"average"`
+	Refunds float64 `json:"refunds"`
+	Net     float64 `json:"net"`
+}
+
+// TopItem is an item ranked by total spend
+type TopItem struct {
+	ASIN     string  `json:"asin"`
+	Title    string  `json:"title"`
+	Quantity int     `json:"quantity"`
+	Orders   int     `json:"orders"`
+	Total    float64 `json:"total"`
+}
+
+// Stats summarizes spending across orders
+type Stats struct {
+	GroupBy        string    `json:"group_by"`
+	Currency       string    `json:"currency"`
+	Orders         int       `json:"orders"`
+	Total          float64   `json:"total"`
+	Average        float64   `json:"average"`
+	RefundedOrders int       `json:"refunded_orders"`
+	Refunds        float64   `json:"refunds"`
+	Net            float64   `json:"net"`
+	Groups         []Group   `json:"groups"`
+	TopItems       []TopItem `json:"top_items"`
+}
+
+// GroupBys returns the supported groupings
+func GroupBys() []string {
+	return []string{GroupByMonth, GroupByCategory, GroupBySeller, GroupByASIN}
+}
+
+// ValidateGroupBy checks that a grouping is supported
+func ValidateGroupBy(groupBy string) error {
+	for _, g := range GroupBys() {
+		if groupBy == g {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported grouping %q: use %s", groupBy, strings.Join(GroupBys(), ", "))
+}
+
+// groupTotals accumulates a group before averages are computed
+type groupTotals struct {
+	group  Group
+	orders map[string]bool
+}
+
+// Compute summarizes the spending of orders grouped by month, category, seller or ASIN.
+// Cancelled orders are ignored. Every order counts as spending and its refunded amount,
+// when known, as a refund, so Net is what was kept; this matches the purchase and
+// refund transactions of accounting exports. Item groupings attribute each item's
+// price to its group and the rest of the order total (tax, shipping) to "(other)".
+// A full refund is attributed like the spending; a partial refund, which can't be
+// matched to items, goes to "(other)".
+// Dates are parsed in the given marketplace's format (nil means US).
+func Compute(orders []models.Order, groupBy string, topItems int, m *marketplace.Marketplace) (*Stats, error) {
+	if err := ValidateGroupBy(groupBy); err != nil {
+		return nil, err
+	}
+	if m == nil {
+		m = marketplace.Default()
+	}
+
+	stats := &Stats{
+		GroupBy:  groupBy,
+		Currency: m.Currency,
+		Groups:   []Group{},
+		TopItems: []TopItem{},
+	}
+	groups := make(map[string]*groupTotals)
+	items := make(map[string]*TopItem)
+	itemOrders := make(map[string]map[string]bool)
+
+	add := func(key, orderID string, quantity int, amount, refund float64) {
+		g, exists := groups[key]
+		if !exists {
+			g = &groupTotals{group: Group{Key: key}, orders: make(map[string]bool)}
+			groups[key] = g
+		}
+		g.orders[orderID] = true
+		g.group.Items += quantity
+		g.group.Total += amount
+		g.group.Refunds += refund
+	}
+
+	for _, order := range orders {
+		if order.Status == models.OrderStatusCancelled {
+			continue
+		}
+		refund := order.RefundAmount()
+		fullRefund := refund > 0 && refund >= order.Total
+		if order.Currency != "" {
+			stats.Currency = order.Currency
+		}
+
+		stats.Orders++
+		stats.Total += order.Total
+		if refund > 0 {
+			stats.RefundedOrders++
+			stats.Refunds += refund
+		}
+
+		// Rank items by spend
+		itemized := 0.0
+		quantity := 0
+		for _, item := range order.Items {
+			amount := item.Price * float64(itemQuantity(item))
+			itemized += amount
+			quantity += itemQuantity(item)
+
+			id := item.ASIN
+			if id == "" {
+				id = item.Title
+			}
+			top, exists := items[id]
+			if !exists {
+				top = &TopItem{ASIN: item.ASIN, Title: item.Title}
+				items[id] = top
+				itemOrders[id] = make(map[string]bool)
+			}
+			top.Quantity += itemQuantity(item)
+			top.Total += amount
+			itemOrders[id][order.OrderID] = true
+		}
+
+		if groupBy == GroupByMonth {
+			date, err := m.ParseDate(order.Date)
+			if err != nil {
+				return nil, fmt.Errorf("order %s: %w", order.OrderID, err)
+			}
+			add(date.Format("2006-01"), order.OrderID, quantity, order.Total, refund)
+			continue
+		}
+
+		for _, item := range order.Items {
+			amount := item.Price * float64(itemQuantity(item))
+			add(itemKey(item, groupBy), order.OrderID, itemQuantity(item), amount, fullRefundOf(amount, fullRefund))
+		}
+		if remainder := order.Total - itemized; math.Abs(remainder) >= 0.005 {
+			add(KeyOther, order.OrderID, 0, remainder, fullRefundOf(remainder, fullRefund))
+		}
+		if refund > 0 && !fullRefund {
+			add(KeyOther, order.OrderID, 0, 0, refund)
+		}
+	}
+
+	// Finish the groups
+	for _, g := range groups {
+		g.group.Orders = len(g.orders)
+		g.group.Total = round(g.group.Total)
+		g.group.Refunds = round(g.group.Refunds)
+		g.group.Net = round(g.group.Total - g.group.Refunds)
+		g.group.Average = round(g.group.Total / float64(g.group.Orders))
+		stats.Groups = append(stats.Groups, g.group)
+	}
+	sort.Slice(stats.Groups, func(i, j int) bool {
+		a, b := stats.Groups[i], stats.Groups[j]
+		if groupBy != GroupByMonth && a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		return a.Key < b.Key
+	})
+
+	// Rank the top items
+	for id, top := range items {
+		top.Orders = len(itemOrders[id])
+		top.Total = round(top.Total)
+		stats.TopItems = append(stats.TopItems, *top)
+	}
+	sort.Slice(stats.TopItems, func(i, j int) bool {
+		a, b := stats.TopItems[i], stats.TopItems[j]
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		return a.ASIN < b.ASIN
+	})
+	if topItems >= 0 && len(stats.TopItems) > topItems {
+		stats.TopItems = stats.TopItems[:topItems]
+	}
+
+	stats.Total = round(stats.Total)
+	stats.Refunds = round(stats.Refunds)
+	stats.Net = round(stats.Total - stats.Refunds)
+	if stats.Orders > 0 {
+		stats.Average = round(stats.Total / float64(stats.Orders))
+	}
+
+	return stats, nil
+}
+
+// Header returns the table and csv column names
+func (s *Stats) Header() []string {
+	return []string{s.GroupBy, "orders", "items", "total", "average", "refunds", "net"}
+}
+
+// Rows returns a row per group followed by a total row
+func (s *Stats) Rows() [][]string {
+	rows := make([][]string, 0, len(s.Groups)+1)
+	for _, g := range s.Groups {
+		rows = append(rows, []string{
+			g.Key, strconv.Itoa(g.Orders), strconv.Itoa(g.Items),
+			formatAmount(g.Total), formatAmount(g.Average), formatAmount(g.Refunds), formatAmount(g.Net),
+		})
+	}
+
+	items := 0
+	for _, g := range s.Groups {
+		items += g.Items
+	}
+	rows = append(rows, []string{
+		"TOTAL", strconv.Itoa(s.Orders), strconv.Itoa(items),
+		formatAmount(s.Total), formatAmount(s.Average), formatAmount(s.Refunds), formatAmount(s.Net),
+	})
+
+	return rows
+}
+
+// itemKey returns the group an item belongs to
+func itemKey(item models.OrderItem, groupBy string) string {
+	var key string
+	switch groupBy {
+	case GroupByCategory:
+		key = item.Category
+	case GroupBySeller:
+		key = item.Seller
+	case GroupByASIN:
+		key = item.ASIN
+	}
+	if key = strings.TrimSpace(key); key == "" {
+		return KeyUnknown
+	}
+	return key
+}
+
+// itemQuantity returns an item's quantity, treating a missing quantity as one
+func itemQuantity(item models.OrderItem) int {
+	if item.Quantity <= 0 {
+		return 1
+	}
+	return item.Quantity
+}
+
+// fullRefundOf returns the refunded part of amount: all of it when the order was
+// fully refunded, nothing otherwise
+func fullRefundOf(amount float64, fullRefund bool) float64 {
+	if fullRefund {
+		return amount
+	}
+	return 0
+}
+
+// formatAmount formats an amount with two decimals
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+// round rounds an amount to cents
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
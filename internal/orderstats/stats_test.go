@@ -0,0 +1,162 @@
+package orderstats
+
+import (
+	"testing"
+
+	"github.com/zkwentz/amazon-cli/pkg/models"
+)
+
+func sampleOrders() []models.Order {
+	return []models.Order{
+		{
+			OrderID: "111-0000000-0000001", Date: "January 5, 2024", Total: 30, Status: models.OrderStatusDelivered,
+			Items: []models.OrderItem{
+				{ASIN: "B08N5WRWNW", Title: "Echo Dot", Quantity: 1, Price: 24.99, Category: "Electronics", Seller: "Amazon.com"},
+			},
+		},
+		{
+			OrderID: "111-0000000-0000002", Date: "January 20, 2024", Total: 25, Status: models.OrderStatusDelivered,
+			Items: []models.OrderItem{
+				{ASIN: "B000FILTER", Title: "Water Filter", Quantity: 2, Price: 12.50, Seller: "Acme"},
+			},
+		},
+		{
+			OrderID: "111-0000000-0000003", Date: "February 2, 2024", Total: 24.99, Status: models.OrderStatusRefunded,
+			Items: []models.OrderItem{
+				{ASIN: "B08N5WRWNW", Title: "Echo Dot", Quantity: 1, Price: 24.99, Category: "Electronics", Seller: "Amazon.com"},
+			},
+			Charges: &models.OrderCharges{Total: 24.99, Refund: 24.99},
+		},
+		{OrderID: "111-0000000-0000004", Date: "March 1, 2024", Total: 99, Status: models.OrderStatusCancelled},
+	}
+}
+
+func TestCompute_ByMonth(t *testing.T) {
+	stats, err := Compute(sampleOrders(), GroupByMonth, DefaultTopItems, nil)
+	if err != nil {
+		t.Fatalf("Compute() error = %v", err)
+	}
+
+	// The cancelled order is ignored
+	if stats.Orders != 3 || stats.Total != 79.99 || stats.Refunds != 24.99 || stats.Net != 55 {
+		t.Errorf("Unexpected totals: %+v", stats)
+	}
+	if stats.RefundedOrders != 1 || stats.Average != 26.66 {
+		t.Errorf("Unexpected refund count or average: %+v", stats)
+	}
+
+	if len(stats.Groups) != 2 {
+		t.Fatalf("Expected 2 months, got %+v", stats.Groups)
+	}
+	january := stats.Groups[0]
+	if january.Key != "2024-01" || january.Orders != 2 || january.Items != 3 || january.Total != 55 || january.Average != 27.5 {
+		t.Errorf("Unexpected January group: %+v", january)
+	}
+	if february := stats.Groups[1]; february.Key != "2024-02" || february.Refunds != 24.99 || february.Net != 0 {
+		t.Errorf("Unexpected February group: %+v", february)
+	}
+
+	// Echo Dot was bought twice
+	if len(stats.TopItems) != 2 || stats.TopItems[0].ASIN != "B08N5WRWNW" || stats.TopItems[0].Orders != 2 || stats.TopItems[0].Total != 49.98 {
+		t.Errorf("Unexpected top items: %+v", stats.TopItems)
+	}
+}
+
+func TestCompute_ByCategory(t *testing.T) {
+	stats, err := Compute(sampleOrders(), GroupByCategory, 1, nil)
+	if err != nil {
+		t.Fatalf("Compute() error = %v", err)
+	}
+
+	expected := map[string]float64{"Electronics": 49.98, KeyUnknown: 25, KeyOther: 5.01}
+	if len(stats.Groups) != len(expected) {
+		t.Fatalf("Expected %d groups, got %+v", len(expected), stats.Groups)
+	}
+	sum := 0.0
+	for _, g := range stats.Groups {
+		if g.Total != expected[g.Key] {
+			t.Errorf("Group %s: expected total %.2f, got %.2f", g.Key, expected[g.Key], g.Total)
+		}
+		sum += g.Total
+	}
+	if round(sum) != stats.Total {
+		t.Errorf("Expected groups to add up to %.2f, got %.2f", stats.Total, sum)
+	}
+
+	// Groups are ordered by spend
+	if stats.Groups[0].Key != "Electronics" {
+		t.Errorf("Expected the largest group first, got %s", stats.Groups[0].Key)
+	}
+	if len(stats.TopItems) != 1 {
+		t.Errorf("Expected top items to be limited to 1, got %d", len(stats.TopItems))
+	}
+}
+
+func TestCompute_PartialAndUnknownRefunds(t *testing.T) {
+	orders := []models.Order{
+		{
+			OrderID: "111-0000000-0000005", Date: "April 3, 2024", Total: 50, Status: models.OrderStatusReturned,
+			Items: []models.OrderItem{
+				{ASIN: "B0AAAA1111", Title: "Mug", Quantity: 2, Price: 25, Category: "Kitchen"},
+			},
+			Charges: &models.OrderCharges{Total: 50, Refund: 25},
+		},
+		// Returned but no refund reported yet: spending only
+		{OrderID: "111-0000000-0000006", Date: "April 9, 2024", Total: 10, Status: models.OrderStatusReturned},
+	}
+
+	stats, err := Compute(orders, GroupByCategory, DefaultTopItems, nil)
+	if err != nil {
+		t.Fatalf("Compute() error = %v", err)
+	}
+	if stats.Total != 60 || stats.Refunds != 25 || stats.Net != 35 || stats.RefundedOrders != 1 {
+		t.Errorf("Unexpected totals: %+v", stats)
+	}
+
+	for _, g := range stats.Groups {
+		switch g.Key {
+		case "Kitchen":
+			if g.Refunds != 0 {
+				t.Errorf("Expected a partial refund not to be attributed to items, got %+v", g)
+			}
+		case KeyOther:
+			if g.Refunds != 25 {
+				t.Errorf("Expected the partial refund under %s, got %+v", KeyOther, g)
+			}
+		}
+	}
+}
+
+func TestStats_Rows(t *testing.T) {
+	stats, _ := Compute(sampleOrders(), GroupBySeller, DefaultTopItems, nil)
+
+	header := stats.Header()
+	if header[0] != "seller" || len(header) != 7 {
+		t.Errorf("Unexpected header %v", header)
+	}
+
+	rows := stats.Rows()
+	if len(rows) != len(stats.Groups)+1 {
+		t.Fatalf("Expected a row per group plus a total, got %d", len(rows))
+	}
+	if rows[0][0] != "Amazon.com" || rows[0][3] != "49.98" {
+		t.Errorf("Unexpected first row %v", rows[0])
+	}
+	if total := rows[len(rows)-1]; total[0] != "TOTAL" || total[3] != "79.99" || total[6] != "55.00" {
+		t.Errorf("Unexpected total row %v", total)
+	}
+}
+
+func TestValidateGroupBy(t *testing.T) {
+	for _, groupBy := range GroupBys() {
+		if err := ValidateGroupBy(groupBy); err != nil {
+			t.Errorf("Expected %s to be valid: %v", groupBy, err)
+		}
+	}
+	if err := ValidateGroupBy("day"); err == nil {
+		t.Error("Expected error for unsupported grouping")
+	}
+	if _, err := Compute(nil, "day", DefaultTopItems, nil); err == nil {
+		t.Error("Expected Compute to reject unsupported grouping")
+	}
+}
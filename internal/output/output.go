@@ -1,9 +1,12 @@
 package output
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
+	"text/tabwriter"
 )
 
 // Format represents the output format type
@@ -13,8 +16,18 @@ const (
 	FormatJSON  Format = "json"
 	FormatTable Format = "table"
 	FormatRaw   Format = "raw"
+	// FormatNDJSON prints each value as a single line of JSON (newline-delimited JSON)
+	FormatNDJSON Format = "ndjson"
+	// FormatCSV prints tabular values as comma-separated rows with a header
+	FormatCSV Format = "csv"
 )
 
+// Tabular is implemented by values that can be printed as rows in the table and csv formats
+type Tabular interface {
+	Header() []string
+	Rows() [][]string
+}
+
 // Printer handles output formatting
 type Printer struct {
 	format Format
@@ -24,7 +37,7 @@ type Printer struct {
 // NewPrinter creates a new Printer with the specified format
 func NewPrinter(format string, quiet bool) *Printer {
 	f := Format(format)
-	if f != FormatJSON && f != FormatTable && f != FormatRaw {
+	if f != FormatJSON && f != FormatTable && f != FormatRaw && f != FormatNDJSON && f != FormatCSV {
 		f = FormatJSON
 	}
 	return &Printer{
@@ -43,11 +56,21 @@ func (p *Printer) Print(data interface{}) error {
 	case FormatJSON:
 		return p.printJSON(data)
 	case FormatTable:
-		// For now, fall back to JSON
+		// Values without a tabular form fall back to JSON
+		if tabular, ok := data.(Tabular); ok {
+			return p.printTable(tabular)
+		}
+		return p.printJSON(data)
+	case FormatCSV:
+		if tabular, ok := data.(Tabular); ok {
+			return p.printCSV(tabular)
+		}
 		return p.printJSON(data)
 	case FormatRaw:
 		fmt.Fprintf(os.Stdout, "%v\n", data)
 		return nil
+	case FormatNDJSON:
+		return p.printNDJSON(data)
 	default:
 		return p.printJSON(data)
 	}
@@ -62,6 +85,35 @@ func (p *Printer) printJSON(data interface{}) error {
 	return nil
 }
 
+func (p *Printer) printNDJSON(data interface{}) error {
+	output, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stdout, string(output))
+	return nil
+}
+
+func (p *Printer) printTable(data Tabular) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.ToUpper(strings.Join(data.Header(), "\t")))
+	for _, row := range data.Rows() {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+func (p *Printer) printCSV(data Tabular) error {
+	w := csv.NewWriter(os.Stdout)
+	if err := w.Write(data.Header()); err != nil {
+		return err
+	}
+	if err := w.WriteAll(data.Rows()); err != nil {
+		return err
+	}
+	return w.Error()
+}
+
 // PrintError outputs an error in the configured format
 func (p *Printer) PrintError(err error) error {
 	errResponse := map[string]interface{}{
@@ -431,3 +431,82 @@ func TestError_IncludesAllFields(t *testing.T) {
 		})
 	}
 }
+
+func TestPrinter_NDJSONPrintsOneLinePerValue(t *testing.T) {
+	// Capture stdout
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	printer := NewPrinter("ndjson", false)
+	for i := 1; i <= 3; i++ {
+		if err := printer.Print(map[string]interface{}{"id": i, "tags": []string{"a", "b"}}); err != nil {
+			t.Fatalf("Print() returned error: %v", err)
+		}
+	}
+
+	// Restore stdout and read captured output
+	w.Close()
+	os.Stdout = old
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %d: %s", len(lines), buf.String())
+	}
+
+	for i, line := range lines {
+		var result map[string]interface{}
+		if err := json.Unmarshal(line, &result); err != nil {
+			t.Fatalf("Line %d is not valid JSON: %v", i, err)
+		}
+		if result["id"].(float64) != float64(i+1) {
+			t.Errorf("Line %d: expected id %d, got %v", i, i+1, result["id"])
+		}
+	}
+}
+
+// testTable is a minimal Tabular value
+type testTable struct{}
+
+func (testTable) Header() []string { return []string{"name", "total"} }
+func (testTable) Rows() [][]string {
+	return [][]string{{"Echo Dot", "24.99"}, {"Filter, 2-pack", "12.50"}}
+}
+
+func TestPrinter_TabularFormats(t *testing.T) {
+	capture := func(format string, data interface{}) string {
+		old := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		if err := NewPrinter(format, false).Print(data); err != nil {
+			t.Fatalf("Print() returned error: %v", err)
+		}
+
+		w.Close()
+		os.Stdout = old
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		return buf.String()
+	}
+
+	table := capture("table", testTable{})
+	expectedTable := "NAME            TOTAL\nEcho Dot        24.99\nFilter, 2-pack  12.50\n"
+	if table != expectedTable {
+		t.Errorf("Expected table:\n%s\ngot:\n%s", expectedTable, table)
+	}
+
+	csvOutput := capture("csv", testTable{})
+	expectedCSV := "name,total\nEcho Dot,24.99\n\"Filter, 2-pack\",12.50\n"
+	if csvOutput != expectedCSV {
+		t.Errorf("Expected CSV:\n%s\ngot:\n%s", expectedCSV, csvOutput)
+	}
+
+	// Values without a tabular form fall back to JSON
+	var result map[string]interface{}
+	if err := json.Unmarshal([]byte(capture("csv", map[string]int{"count": 1})), &result); err != nil {
+		t.Errorf("Expected JSON fallback for non-tabular data: %v", err)
+	}
+}
@@ -7,6 +7,9 @@ import (
 	"time"
 )
 
+// DefaultMaxBackoff caps the exponential backoff when no maximum delay is configured
+const DefaultMaxBackoff = 60 * time.Second
+
 // RateLimiter manages rate limiting with exponential backoff and jitter
 type RateLimiter struct {
 	minDelay   time.Duration
@@ -63,9 +66,8 @@ func (rl *RateLimiter) WaitWithBackoff(attempt int) {
 	delay := time.Duration(backoff)
 
 	// Cap at 60 seconds
-	maxBackoff := 60 * time.Second
-	if delay > maxBackoff {
-		delay = maxBackoff
+	if delay > DefaultMaxBackoff {
+		delay = DefaultMaxBackoff
 	}
 
 	// Ensure delay doesn't exceed maxDelay if set
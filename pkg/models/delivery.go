@@ -0,0 +1,62 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Delivery sources
+const (
+	DeliverySourceOrder        = "order"
+	DeliverySourceSubscription = "subscription"
+)
+
+// Delivery is a package or Subscribe & Save shipment expected to arrive
+type Delivery struct {
+	// ExpectedDate is the expected delivery date (YYYY-MM-DD), empty when Amazon hasn't given one
+	ExpectedDate   string      `json:"expected_date"`
+	Source         string      `json:"source"`
+	OrderID        string      `json:"order_id,omitempty"`
+	ShipmentID     string      `json:"shipment_id,omitempty"`
+	SubscriptionID string      `json:"subscription_id,omitempty"`
+	Status         string      `json:"status"`
+	Items          []OrderItem `json:"items"`
+	Tracking       *Tracking   `json:"tracking,omitempty"`
+}
+
+// DeliveriesResponse lists what is expected to arrive up to a date
+type DeliveriesResponse struct {
+	Days       int        `json:"days"`
+	Until      string     `json:"until"`
+	Deliveries []Delivery `json:"deliveries"`
+	TotalCount int        `json:"total_count"`
+}
+
+// Header returns the table and csv column names
+func (r *DeliveriesResponse) Header() []string {
+	return []string{"expected", "source", "id", "status", "carrier", "items"}
+}
+
+// Rows returns a row per delivery
+func (r *DeliveriesResponse) Rows() [][]string {
+	rows := make([][]string, 0, len(r.Deliveries))
+	for _, d := range r.Deliveries {
+		id := d.OrderID
+		if d.Source == DeliverySourceSubscription {
+			id = d.SubscriptionID
+		}
+		carrier := ""
+		if d.Tracking != nil {
+			carrier = d.Tracking.Carrier
+		}
+		items := make([]string, len(d.Items))
+		for i, item := range d.Items {
+			items[i] = item.Title
+			if item.Quantity > 1 {
+				items[i] += " x" + strconv.Itoa(item.Quantity)
+			}
+		}
+		rows = append(rows, []string{d.ExpectedDate, d.Source, id, d.Status, carrier, strings.Join(items, "; ")})
+	}
+	return rows
+}
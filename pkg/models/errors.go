@@ -27,6 +27,11 @@ const (
 	ExitNetworkError   = 4
 	ExitRateLimited    = 5
 	ExitNotFound       = 6
+
+	// ExitTrackingException is returned by 'orders track --watch' when a shipment hits a delivery exception
+	ExitTrackingException = 7
+	// ExitInterrupted is returned by 'orders track --watch' when it is stopped with Ctrl-C (128 + SIGINT)
+	ExitInterrupted = 130
 )
 
 // CLIError represents a structured error for CLI output
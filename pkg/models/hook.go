@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Hook event types
+const (
+	// HookEventStatusChanged fires when an order, package or tracking status changes
+	HookEventStatusChanged = "status_changed"
+	// HookEventTrackingEvent fires for each new carrier tracking event
+	HookEventTrackingEvent = "tracking_event"
+	// HookEventDeliveryDateChanged fires when the expected delivery date moves
+	HookEventDeliveryDateChanged = "delivery_date_changed"
+)
+
+// Hook event sources
+const (
+	HookSourceSync  = "sync"
+	HookSourceWatch = "watch"
+)
+
+// HookEvent is a change detected by orders sync or orders track --watch, sent to
+// notification hooks. Previous and Current hold the old and new status or delivery
+// date; Order, Tracking and TrackingEvent carry the affected data where available.
+type HookEvent struct {
+	Type          string         `json:"type"`
+	Source        string         `json:"source"`
+	OrderID       string         `json:"order_id"`
+	ShipmentID    string         `json:"shipment_id,omitempty"`
+	Previous      string         `json:"previous,omitempty"`
+	Current       string         `json:"current,omitempty"`
+	Order         *Order         `json:"order,omitempty"`
+	Tracking      *Tracking      `json:"tracking,omitempty"`
+	TrackingEvent *TrackingEvent `json:"tracking_event,omitempty"`
+	Time          time.Time      `json:"time"`
+}
@@ -0,0 +1,22 @@
+package models
+
+// InvoiceFile describes an order invoice saved to disk
+type InvoiceFile struct {
+	OrderID  string `json:"order_id"`
+	Date     string `json:"date,omitempty"`
+	URL      string `json:"url,omitempty"`
+	Path     string `json:"path"`
+	TextPath string `json:"text_path,omitempty"`
+	Skipped  bool   `json:"skipped,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// InvoicesResponse summarizes a bulk invoice download
+type InvoicesResponse struct {
+	Year       int           `json:"year"`
+	Dir        string        `json:"dir"`
+	Invoices   []InvoiceFile `json:"invoices"`
+	Downloaded int           `json:"downloaded"`
+	Skipped    int           `json:"skipped"`
+	Failed     int           `json:"failed"`
+}
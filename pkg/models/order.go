@@ -2,26 +2,103 @@ package models
 
 // Order represents an Amazon order
 type Order struct {
-	OrderID  string      `json:"order_id"`
-	Date     string      `json:"date"`
-	Total    float64     `json:"total"`
-	Status   string      `json:"status"`
-	Items    []OrderItem `json:"items"`
-	Tracking *Tracking   `json:"tracking,omitempty"`
+	OrderID         string         `json:"order_id"`
+	Type            string         `json:"type,omitempty"`
+	Date            string         `json:"date"`
+	Total           float64        `json:"total"`
+	Currency        string         `json:"currency,omitempty"`
+	Status          string         `json:"status"`
+	Items           []OrderItem    `json:"items"`
+	Tracking        *Tracking      `json:"tracking,omitempty"`
+	Shipments       []Shipment     `json:"shipments,omitempty"`
+	ShippingAddress *Address       `json:"shipping_address,omitempty"`
+	PaymentMethod   *PaymentMethod `json:"payment_method,omitempty"`
+	Charges         *OrderCharges  `json:"charges,omitempty"`
 }
 
+// Order types: physical goods shipped in packages, or digital purchases such as
+// Kindle books, videos, apps and software delivered to a device or account
+const (
+	OrderTypePhysical = "physical"
+	OrderTypeDigital  = "digital"
+)
+
+// Shipment represents a package within an order with its own items and tracking
+type Shipment struct {
+	ShipmentID string      `json:"shipment_id,omitempty"`
+	Status     string      `json:"status"`
+	Items      []OrderItem `json:"items"`
+	Tracking   *Tracking   `json:"tracking,omitempty"`
+}
+
+// OrderTracking is the tracking of each package of an order
+type OrderTracking struct {
+	OrderID   string     `json:"order_id"`
+	Shipments []Shipment `json:"shipments"`
+}
+
+// OrderCharges is the breakdown of an order's total.
+// Promotions and GiftCard are the amounts deducted from the total. Refund is the
+// amount refunded after a return or cancellation, zero until Amazon reports one.
+type OrderCharges struct {
+	Subtotal   float64 `json:"subtotal"`
+	Shipping   float64 `json:"shipping"`
+	Tax        float64 `json:"tax"`
+	Promotions float64 `json:"promotions"`
+	GiftCard   float64 `json:"gift_card"`
+	Total      float64 `json:"total"`
+	Refund     float64 `json:"refund,omitempty"`
+}
+
+// RefundAmount returns the amount refunded for the order, capped at its total.
+// It is zero when no refund is known, even for returned orders, because a return
+// can cover only some of the items.
+func (o Order) RefundAmount() float64 {
+	if o.Charges == nil || o.Charges.Refund <= 0 {
+		return 0
+	}
+	if o.Total > 0 && o.Charges.Refund > o.Total {
+		return o.Total
+	}
+	return o.Charges.Refund
+}
+
+// Normalized order statuses
+const (
+	OrderStatusPending        = "pending"
+	OrderStatusOutForDelivery = "out_for_delivery"
+	OrderStatusDelayed        = "delayed"
+	OrderStatusDelivered      = "delivered"
+	OrderStatusCancelled      = "cancelled"
+	OrderStatusReturnStarted  = "return_started"
+	OrderStatusReturned       = "returned"
+	OrderStatusRefunded       = "refunded"
+	OrderStatusUnknown        = "unknown"
+)
+
 // OrderItem represents an item within an order
 type OrderItem struct {
 	ASIN     string  `json:"asin"`
 	Title    string  `json:"title"`
 	Quantity int     `json:"quantity"`
 	Price    float64 `json:"price"`
+	Image    string  `json:"image,omitempty"`
+	Seller   string  `json:"seller,omitempty"`
+	Category string  `json:"category,omitempty"`
+	// Format is the edition of a digital item, e.g. "Kindle Edition" or "Prime Video HD"
+	Format string `json:"format,omitempty"`
+	// DeliveredTo is the device or library a digital item was delivered to
+	DeliveredTo string `json:"delivered_to,omitempty"`
+	// License is the license terms or key of a software download
+	License string `json:"license,omitempty"`
 }
 
 // Tracking represents shipment tracking information
 type Tracking struct {
 	Carrier        string          `json:"carrier"`
+	CarrierCode    string          `json:"carrier_code,omitempty"`
 	TrackingNumber string          `json:"tracking_number"`
+	TrackingURL    string          `json:"tracking_url,omitempty"`
 	Status         string          `json:"status"`
 	DeliveryDate   string          `json:"delivery_date,omitempty"`
 	Events         []TrackingEvent `json:"events,omitempty"`
@@ -39,3 +116,46 @@ type OrdersResponse struct {
 	Orders     []Order `json:"orders"`
 	TotalCount int     `json:"total_count"`
 }
+
+// Order cancellation statuses
+const (
+	// CancelStatusCancelled means Amazon cancelled the items immediately
+	CancelStatusCancelled = "cancelled"
+	// CancelStatusRequested means Amazon accepted a cancellation request it will try to fulfil
+	CancelStatusRequested = "requested"
+)
+
+// CancellableItem is an order item as listed on the cancellation page
+type CancellableItem struct {
+	ASIN        string `json:"asin"`
+	Title       string `json:"title"`
+	Quantity    int    `json:"quantity"`
+	Cancellable bool   `json:"cancellable"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// OrderCancellation lists which items of an order can still be cancelled
+type OrderCancellation struct {
+	OrderID string            `json:"order_id"`
+	Items   []CancellableItem `json:"items"`
+}
+
+// CancelResult is the outcome of an order cancellation
+type CancelResult struct {
+	OrderID   string            `json:"order_id"`
+	Status    string            `json:"status"`
+	Cancelled []CancellableItem `json:"cancelled"`
+	Remaining []CancellableItem `json:"remaining"`
+	Message   string            `json:"message,omitempty"`
+}
+
+// TrackingUpdate is a change reported while watching an order's tracking: a new
+// event, a status change, a failed poll, or the final outcome (delivered or exception)
+type TrackingUpdate struct {
+	OrderID    string         `json:"order_id"`
+	ShipmentID string         `json:"shipment_id,omitempty"`
+	Status     string         `json:"status"`
+	Event      *TrackingEvent `json:"event,omitempty"`
+	Outcome    string         `json:"outcome,omitempty"`
+	Error      string         `json:"error,omitempty"`
+}
@@ -6,6 +6,7 @@ type Product struct {
 	Title            string   `json:"title"`
 	Price            float64  `json:"price"`
 	OriginalPrice    *float64 `json:"original_price,omitempty"`
+	Currency         string   `json:"currency,omitempty"`
 	Rating           float64  `json:"rating"`
 	ReviewCount      int      `json:"review_count"`
 	Prime            bool     `json:"prime"`
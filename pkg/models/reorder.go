@@ -0,0 +1,32 @@
+package models
+
+// ReorderItem is an item of a past order with its current price and availability
+type ReorderItem struct {
+	ASIN        string  `json:"asin"`
+	Title       string  `json:"title"`
+	Quantity    int     `json:"quantity"`
+	OldPrice    float64 `json:"old_price"`
+	NewPrice    float64 `json:"new_price"`
+	PriceChange float64 `json:"price_change"`
+	InStock     bool    `json:"in_stock"`
+	// Reason explains why the item can't be reordered
+	Reason string `json:"reason,omitempty"`
+}
+
+// ReorderPlan compares a past order's items with their current prices. Totals cover
+// only the items that can be reordered.
+type ReorderPlan struct {
+	OrderID  string        `json:"order_id"`
+	Currency string        `json:"currency,omitempty"`
+	Items    []ReorderItem `json:"items"`
+	OldTotal float64       `json:"old_total"`
+	NewTotal float64       `json:"new_total"`
+}
+
+// ReorderResult is the outcome of adding a past order's items to the cart
+type ReorderResult struct {
+	OrderID string        `json:"order_id"`
+	Added   []ReorderItem `json:"added"`
+	Skipped []ReorderItem `json:"skipped"`
+	Cart    *Cart         `json:"cart,omitempty"`
+}
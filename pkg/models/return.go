@@ -1,22 +1,98 @@
 package models
 
-// ReturnableItem represents an item that can be returned
+import "strconv"
+
+// ReturnableItem represents an item that can be returned.
+// PurchaseDate and ReturnWindow (the last day a return can be started) are
+// YYYY-MM-DD dates.
 type ReturnableItem struct {
 	OrderID      string  `json:"order_id"`
 	ItemID       string  `json:"item_id"`
 	ASIN         string  `json:"asin"`
 	Title        string  `json:"title"`
 	Price        float64 `json:"price"`
+	Quantity     int     `json:"quantity,omitempty"`
 	PurchaseDate string  `json:"purchase_date"`
 	ReturnWindow string  `json:"return_window"`
+	// DaysLeft is the number of days until the return window closes; 0 means today
+	DaysLeft int `json:"days_left"`
+}
+
+// ReturnableItemsResponse lists the items that can still be returned, the ones
+// whose return window closes soonest first
+type ReturnableItemsResponse struct {
+	Items      []ReturnableItem `json:"items"`
+	TotalCount int              `json:"total_count"`
+}
+
+// Header returns the table and csv column names
+func (r *ReturnableItemsResponse) Header() []string {
+	return []string{"return_by", "days_left", "order_id", "item_id", "asin", "title", "price"}
+}
+
+// Rows returns a row per returnable item
+func (r *ReturnableItemsResponse) Rows() [][]string {
+	rows := make([][]string, 0, len(r.Items))
+	for _, item := range r.Items {
+		rows = append(rows, []string{
+			item.ReturnWindow, strconv.Itoa(item.DaysLeft), item.OrderID, item.ItemID, item.ASIN, item.Title,
+			strconv.FormatFloat(item.Price, 'f', 2, 64),
+		})
+	}
+	return rows
 }
 
+// Return methods offered by the returns center
+const (
+	ReturnMethodUPSDropoff = "ups_dropoff"
+	ReturnMethodWholeFoods = "whole_foods"
+	ReturnMethodKohls      = "kohls"
+	ReturnMethodPickup     = "pickup"
+	ReturnMethodMail       = "mail"
+)
+
+// Ways a return is refunded
+const (
+	RefundOriginalPayment = "original_payment"
+	RefundGiftCard        = "gift_card"
+)
+
 // ReturnOption represents a method for returning an item
 type ReturnOption struct {
+	// Method is one of the ReturnMethod codes, or a code derived from the label
+	// for methods not listed there
 	Method          string  `json:"method"`
 	Label           string  `json:"label"`
 	DropoffLocation string  `json:"dropoff_location,omitempty"`
 	Fee             float64 `json:"fee"`
+	// RefundMethod is RefundOriginalPayment or RefundGiftCard
+	RefundMethod string `json:"refund_method,omitempty"`
+	// RefundTiming describes when the refund is issued, e.g. "when the item is dropped off"
+	RefundTiming string `json:"refund_timing,omitempty"`
+}
+
+// ReturnOptionsResponse lists the ways an order item can be returned
+type ReturnOptionsResponse struct {
+	OrderID    string         `json:"order_id"`
+	ItemID     string         `json:"item_id"`
+	Options    []ReturnOption `json:"options"`
+	TotalCount int            `json:"total_count"`
+}
+
+// Header returns the table and csv column names
+func (r *ReturnOptionsResponse) Header() []string {
+	return []string{"method", "label", "fee", "dropoff_location", "refund_method", "refund_timing"}
+}
+
+// Rows returns a row per return option
+func (r *ReturnOptionsResponse) Rows() [][]string {
+	rows := make([][]string, 0, len(r.Options))
+	for _, o := range r.Options {
+		rows = append(rows, []string{
+			o.Method, o.Label, strconv.FormatFloat(o.Fee, 'f', 2, 64), o.DropoffLocation, o.RefundMethod, o.RefundTiming,
+		})
+	}
+	return rows
 }
 
 // Return represents a product return
@@ -27,6 +103,9 @@ type Return struct {
 	Status    string `json:"status"`
 	Reason    string `json:"reason"`
 	CreatedAt string `json:"created_at"`
+	// Method is the return method chosen from the item's return options
+	Method       string `json:"method,omitempty"`
+	RefundMethod string `json:"refund_method,omitempty"`
 }
 
 // ReturnLabel represents a shipping label for a return